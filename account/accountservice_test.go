@@ -1,10 +1,12 @@
 package account
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"testing"
 
+	"github.com/aergoio/aergo/account/key"
 	"github.com/aergoio/aergo/config"
 	"github.com/aergoio/aergo/message"
 	"github.com/aergoio/aergo/types"
@@ -180,3 +182,64 @@ func TestVerfiyFail(t *testing.T) {
 		t.Fatal("should not success to verify")
 	}
 }
+
+func TestMultiSigSignVerify(t *testing.T) {
+	initTest()
+	defer deinitTest()
+	var testaccounts []*types.Account
+	var pubKeys [][]byte
+	testsize := 3
+	for i := 0; i < testsize; i++ {
+		passphrase := fmt.Sprintf("test%d", i)
+		account, err := as.createAccount(passphrase)
+		if err != nil {
+			t.Fatalf("failed to create account:%s", err)
+		}
+		pubKey, err := as.ks.GetPubKey(account.Address, passphrase)
+		if err != nil {
+			t.Fatalf("failed to get pubkey:%s", err)
+		}
+		testaccounts = append(testaccounts, account)
+		pubKeys = append(pubKeys, pubKey)
+	}
+	address, err := key.GenerateMultiSigAddress(2, pubKeys)
+	if err != nil {
+		t.Fatalf("failed to generate multisig address: %s", err)
+	}
+	reversed := [][]byte{pubKeys[2], pubKeys[1], pubKeys[0]}
+	if other, _ := key.GenerateMultiSigAddress(2, reversed); !bytes.Equal(address, other) {
+		t.Error("multisig address should not depend on order of pubkeys")
+	}
+	tx := &types.Tx{Body: &types.TxBody{
+		Account:  address,
+		MultiSig: &types.MultiSig{Threshold: 2, PubKeys: pubKeys},
+	}}
+
+	as.unlockAccount(testaccounts[0].Address, "test0")
+	if err = as.ks.SignTx(tx); err != nil {
+		t.Fatalf("failed to sign: %s", err)
+	}
+	if err = as.ks.VerifyTx(tx); err != message.ErrNotEnoughSign {
+		t.Errorf("should return :%s", message.ErrNotEnoughSign)
+	}
+	if err = as.ks.SignTx(tx); err != message.ErrShouldUnlockAccount {
+		t.Errorf("should not sign twice with same key: %v", err)
+	}
+
+	as.unlockAccount(testaccounts[2].Address, "test2")
+	if err = as.ks.SignTx(tx); err != nil {
+		t.Fatalf("failed to sign: %s", err)
+	}
+	if len(tx.Body.MultiSig.Signs) != 2 {
+		t.Fatalf("invalid sign count:%d", len(tx.Body.MultiSig.Signs))
+	}
+	if err = as.ks.VerifyTx(tx); err != nil {
+		t.Fatalf("failed to verify: %s", err)
+	}
+
+	//edit tx after sign
+	tx.Body.Amount = 0xff
+	if err = as.ks.VerifyTx(tx); err != message.ErrSignNotMatch {
+		t.Errorf("should return :%s", message.ErrSignNotMatch)
+	}
+}
@@ -6,6 +6,11 @@ import (
 	"encoding/binary"
 	"io/ioutil"
 	"os"
+	"sort"
+
+	"github.com/aergoio/aergo/message"
+	"github.com/btcsuite/btcd/btcec"
+	sha256 "github.com/minio/sha256-simd"
 )
 
 type Address = []byte
@@ -17,6 +22,54 @@ func GenerateAddress(pubkey *ecdsa.PublicKey) []byte {
 	return addr.Bytes()[:20] //TODO: ADDRESSLENGTH ?
 }
 
+// GenerateMultiSigAddress returns the address of an account which requires
+// threshold signatures out of pubKeys. Pubkeys are compressed and sorted before
+// hashing, so the address does not depend on the order they are given in.
+func GenerateMultiSigAddress(threshold uint32, pubKeys [][]byte) ([]byte, error) {
+	keys, err := normalizePubKeys(threshold, pubKeys)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	binary.Write(h, binary.LittleEndian, threshold)
+	for _, pubKey := range keys {
+		h.Write(pubKey)
+	}
+	return h.Sum(nil)[:20], nil
+}
+
+func normalizePubKeys(threshold uint32, pubKeys [][]byte) ([][]byte, error) {
+	if threshold == 0 || int(threshold) > len(pubKeys) {
+		return nil, message.ErrInvalidMultiSig
+	}
+	keys := make([][]byte, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		parsed, err := btcec.ParsePubKey(pubKey, btcec.S256())
+		if err != nil {
+			return nil, message.ErrInvalidMultiSig
+		}
+		keys[i] = parsed.SerializeCompressed()
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+	for i := 1; i < len(keys); i++ {
+		if bytes.Equal(keys[i-1], keys[i]) {
+			return nil, message.ErrInvalidMultiSig
+		}
+	}
+	return keys, nil
+}
+
+func containsPubKey(keys [][]byte, pubKey []byte) bool {
+	for _, k := range keys {
+		if bytes.Equal(k, pubKey) {
+			return true
+		}
+	}
+	return false
+}
+
 func (ks *Store) SaveAddress(addr Address) error {
 	f, err := os.OpenFile(ks.addresses, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
 	if err != nil {
@@ -5,8 +5,6 @@ import (
 
 	sha256 "github.com/minio/sha256-simd"
 
-	"encoding/binary"
-
 	"github.com/aergoio/aergo/message"
 	"github.com/aergoio/aergo/types"
 	"github.com/btcsuite/btcd/btcec"
@@ -23,6 +21,16 @@ func (ks *Store) Sign(addr Address, pass string, hash []byte) ([]byte, error) {
 	return btcec.SignCompact(btcec.S256(), key, hash, true)
 }
 
+//SignMultiSig append a partial sign with key in the store to the multi-signature transaction
+func (ks *Store) SignMultiSig(addr Address, pass string, tx *types.Tx) error {
+	k, err := ks.getKey(addr, pass)
+	if k == nil {
+		return err
+	}
+	key, _ := btcec.PrivKeyFromBytes(btcec.S256(), k)
+	return SignMultiSigTx(tx, key)
+}
+
 func SignTx(tx *types.Tx, key *aergokey) error {
 	hash := CalculateHashWithoutSign(tx.Body)
 	sign, err := btcec.SignCompact(btcec.S256(), key, hash, true)
@@ -34,8 +42,72 @@ func SignTx(tx *types.Tx, key *aergokey) error {
 	return nil
 }
 
-//SignTx return transaction which signed with unlocked key
+//SignMultiSigTx append a partial sign of key to the multi-signature transaction
+func SignMultiSigTx(tx *types.Tx, key *aergokey) error {
+	ms := tx.Body.MultiSig
+	if ms == nil {
+		return message.ErrInvalidMultiSig
+	}
+	keys, err := normalizePubKeys(ms.Threshold, ms.PubKeys)
+	if err != nil {
+		return err
+	}
+	pubKey := key.PubKey().SerializeCompressed()
+	if !containsPubKey(keys, pubKey) {
+		return message.ErrNotMultiSigMember
+	}
+	hash := CalculateHashWithoutSign(tx.Body)
+	for _, sign := range ms.Signs {
+		signer, _, err := btcec.RecoverCompact(btcec.S256(), sign, hash)
+		if err == nil && bytes.Equal(signer.SerializeCompressed(), pubKey) {
+			return message.ErrAlreadySigned
+		}
+	}
+	sign, err := btcec.SignCompact(btcec.S256(), key, hash, true)
+	if err != nil {
+		return err
+	}
+	ms.Signs = append(ms.Signs, sign)
+	tx.Hash = tx.CalculateTxHash()
+	return nil
+}
+
+//SignFeePayerTx set the sign of fee payer to the transaction
+func SignFeePayerTx(tx *types.Tx, key *aergokey) error {
+	hash := CalculateHashWithoutSign(tx.Body)
+	sign, err := btcec.SignCompact(btcec.S256(), key, hash, true)
+	if err != nil {
+		return err
+	}
+	tx.Body.FeePayerSign = sign
+	tx.Hash = tx.CalculateTxHash()
+	return nil
+}
+
+//SignTx return transaction which signed with unlocked key.
+//For multi-signature transaction, every unlocked member key which has not signed yet adds its sign.
+//If the fee payer of transaction is unlocked, it signs the transaction as well
 func (ks *Store) SignTx(tx *types.Tx) error {
+	payerSigned := false
+	if payer := tx.Body.FeePayer; len(payer) > 0 {
+		if key, exist := ks.unlocked[base58.Encode(payer)]; exist {
+			if err := SignFeePayerTx(tx, key); err != nil {
+				return err
+			}
+			payerSigned = true
+		}
+	}
+	err := ks.signSenderTx(tx)
+	if err == message.ErrShouldUnlockAccount && payerSigned {
+		return nil
+	}
+	return err
+}
+
+func (ks *Store) signSenderTx(tx *types.Tx) error {
+	if tx.Body.MultiSig != nil {
+		return ks.signMultiSigTx(tx)
+	}
 	addr := tx.Body.Account
 	key, exist := ks.unlocked[base58.Encode(addr)]
 	if !exist {
@@ -44,34 +116,89 @@ func (ks *Store) SignTx(tx *types.Tx) error {
 	return SignTx(tx, key)
 }
 
-//VerifyTx return result to varify sign
+func (ks *Store) signMultiSigTx(tx *types.Tx) error {
+	signed := false
+	for _, key := range ks.unlocked {
+		err := SignMultiSigTx(tx, key)
+		switch err {
+		case nil:
+			signed = true
+		case message.ErrNotMultiSigMember, message.ErrAlreadySigned:
+		default:
+			return err
+		}
+	}
+	if !signed {
+		return message.ErrShouldUnlockAccount
+	}
+	return nil
+}
+
+//VerifyTx return result to varify sign of sender and fee payer
 func VerifyTx(tx *types.Tx) error {
 	txBody := tx.Body
 	hash := CalculateHashWithoutSign(txBody)
-	pubkey, _, err := btcec.RecoverCompact(btcec.S256(), txBody.Sign, hash)
+	var err error
+	if txBody.MultiSig != nil {
+		err = verifyMultiSigTx(txBody, hash)
+	} else {
+		err = verifySign(txBody.Account, txBody.Sign, hash)
+	}
+	if err != nil {
+		return err
+	}
+	if len(txBody.FeePayer) > 0 {
+		return verifySign(txBody.FeePayer, txBody.FeePayerSign, hash)
+	}
+	return nil
+}
+
+func verifySign(address, sign, hash []byte) error {
+	pubkey, _, err := btcec.RecoverCompact(btcec.S256(), sign, hash)
 	if err != nil {
 		return message.ErrCouldNotRecoverPubKey
 	}
-	address := GenerateAddress(pubkey.ToECDSA())
+	if !bytes.Equal(GenerateAddress(pubkey.ToECDSA()), address) {
+		return message.ErrSignNotMatch
+	}
+	return nil
+}
+
+func verifyMultiSigTx(txBody *types.TxBody, hash []byte) error {
+	ms := txBody.MultiSig
+	address, err := GenerateMultiSigAddress(ms.Threshold, ms.PubKeys)
+	if err != nil {
+		return err
+	}
 	if !bytes.Equal(address, txBody.Account) {
 		return message.ErrSignNotMatch
 	}
+	keys, _ := normalizePubKeys(ms.Threshold, ms.PubKeys)
+	signers := map[string]bool{}
+	for _, sign := range ms.Signs {
+		pubkey, _, err := btcec.RecoverCompact(btcec.S256(), sign, hash)
+		if err != nil {
+			return message.ErrCouldNotRecoverPubKey
+		}
+		signer := pubkey.SerializeCompressed()
+		if !containsPubKey(keys, signer) || signers[string(signer)] {
+			return message.ErrSignNotMatch
+		}
+		signers[string(signer)] = true
+	}
+	if uint32(len(signers)) < ms.Threshold {
+		return message.ErrNotEnoughSign
+	}
 	return nil
 }
+
 func (ks *Store) VerifyTx(tx *types.Tx) error {
 	return VerifyTx(tx)
 }
 
-//CalculateHashWithoutSign return hash of tx without sign field
+//CalculateHashWithoutSign return hash of tx without sign fields of sender, fee payer and members of multi-signature
 func CalculateHashWithoutSign(txBody *types.TxBody) []byte {
 	h := sha256.New()
-	binary.Write(h, binary.LittleEndian, txBody.Nonce)
-	h.Write(txBody.Account)
-	h.Write(txBody.Recipient)
-	binary.Write(h, binary.LittleEndian, txBody.Amount)
-	h.Write(txBody.Payload)
-	binary.Write(h, binary.LittleEndian, txBody.Limit)
-	binary.Write(h, binary.LittleEndian, txBody.Price)
-	binary.Write(h, binary.LittleEndian, txBody.Type)
+	types.WriteTxBody(h, txBody, false)
 	return h.Sum(nil)
 }
@@ -85,6 +85,16 @@ func (ks *Store) Lock(addr Address, pass string) (Address, error) {
 	return addr, nil
 }
 
+// GetPubKey returns the compressed public key of the address
+func (ks *Store) GetPubKey(addr Address, pass string) ([]byte, error) {
+	k, err := ks.getKey(addr, pass)
+	if k == nil {
+		return nil, err
+	}
+	_, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), k)
+	return pubKey.SerializeCompressed(), nil
+}
+
 func (ks *Store) getKey(address []byte, passphrase string) ([]byte, error) {
 	encryptkey := hashBytes(address, []byte(passphrase))
 	key := ks.storage.Get(hashBytes(address, encryptkey))
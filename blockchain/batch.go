@@ -0,0 +1,160 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aergoio/aergo-lib/db"
+	"github.com/aergoio/aergo/contract"
+	"github.com/aergoio/aergo/message"
+	"github.com/aergoio/aergo/state"
+	"github.com/aergoio/aergo/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/mr-tron/base58/base58"
+)
+
+var (
+	errBatchEmpty       = errors.New("batch has no operation")
+	errBatchNoRecipient = errors.New("operation has no recipient")
+)
+
+// batchState keeps the account states changed by a batch transaction. The
+// changes are put to the block state all together only if every operation
+// succeeds.
+type batchState struct {
+	*contract.StateSet
+	gasUsed uint64
+}
+
+func newBatchState(sdb *state.ChainStateDB, bs *types.BlockState) *batchState {
+	return &batchState{StateSet: contract.NewStateSet(sdb, bs)}
+}
+
+func (b *batchState) get(account []byte) (*types.State, error) {
+	return b.GetAccount(types.ToAccountID(account))
+}
+
+// BatchResult is the result of an operation of a batch transaction, which is
+// listed in the receipt of the transaction.
+type BatchResult struct {
+	Recipient string          `json:"recipient"`
+	Status    string          `json:"status"`
+	Ret       json.RawMessage `json:"ret,omitempty"`
+}
+
+func executeBatchTx(sdb *state.ChainStateDB, bs *types.BlockState, tx *types.Tx, dbTx db.Transaction,
+	blockNo uint64, ts int64) error {
+	txBody := tx.GetBody()
+
+	batch := newBatchState(sdb, bs)
+	if err := chargeTx(batch.StateSet, txBody); err != nil {
+		return err
+	}
+
+	results, err := batch.execute(tx, dbTx, blockNo, ts)
+	status := "SUCCESS"
+	if err == nil {
+		if err = settleTx(batch.StateSet, bs, txBody, batch.gasUsed); err == nil {
+			err = batch.Apply()
+		}
+	} else {
+		// revert every operation. only the fee of the gas used and the
+		// nonce remain.
+		logger.Debug().Str("hash", types.EncodeB64(tx.GetHash())).Err(err).Msg("batch tx reverted")
+		status = fmt.Sprintf("REVERTED: %s", err.Error())
+		err = revertTx(sdb, bs, txBody, batch.gasUsed)
+	}
+	if err != nil {
+		return err
+	}
+
+	ret, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	receipt := types.NewReceipt(txBody.Account, status, string(ret))
+	receipt.GasUsed = batch.gasUsed
+	if status == "SUCCESS" {
+		receipt.Events = batch.Events()
+	}
+	dbTx.Set(tx.GetHash(), receipt.Bytes())
+
+	return nil
+}
+
+func (b *batchState) execute(tx *types.Tx, dbTx db.Transaction, blockNo uint64, ts int64) ([]*BatchResult, error) {
+	txBody := tx.GetBody()
+	var ops types.Batch
+	if err := proto.Unmarshal(txBody.Payload, &ops); err != nil {
+		return nil, err
+	}
+	if len(ops.Operations) == 0 {
+		return nil, errBatchEmpty
+	}
+
+	results := make([]*BatchResult, 0, len(ops.Operations))
+	for i, op := range ops.Operations {
+		result := &BatchResult{Recipient: base58.Encode(op.Recipient), Status: "SUCCESS"}
+		results = append(results, result)
+
+		ret, err := b.executeOperation(tx, op, dbTx, blockNo, ts)
+		if err != nil {
+			result.Status = err.Error()
+			return results, fmt.Errorf("operation %d: %s", i, err.Error())
+		}
+		if len(ret) > 0 {
+			result.Ret = json.RawMessage(ret)
+		}
+	}
+	return results, nil
+}
+
+func (b *batchState) executeOperation(tx *types.Tx, op *types.BatchOperation, dbTx db.Transaction,
+	blockNo uint64, ts int64) (string, error) {
+	txBody := tx.GetBody()
+	if len(op.Recipient) == 0 {
+		return "", errBatchNoRecipient
+	}
+	sender, err := b.get(txBody.Account)
+	if err != nil {
+		return "", err
+	}
+	receiver, err := b.get(op.Recipient)
+	if err != nil {
+		return "", err
+	}
+	if sender != receiver {
+		if sender.Balance < op.Amount {
+			return "", message.ErrInsufficientBalance
+		}
+		sender.Balance = sender.Balance - op.Amount
+		receiver.Balance = receiver.Balance + op.Amount
+	}
+	if op.Payload == nil {
+		return "", nil
+	}
+
+	contractState, err := b.OpenContract(types.ToAccountID(op.Recipient))
+	if err != nil {
+		return "", err
+	}
+	// every operation shares the gas limit of the transaction
+	gasLimit := txBody.Limit
+	if gasLimit > contract.MaxGasLimit {
+		gasLimit = contract.MaxGasLimit
+	}
+	if b.gasUsed >= gasLimit {
+		return "", contract.ErrOutOfGas
+	}
+	bcCtx := contract.NewContext(b.StateSet, contractState, txBody.GetAccount(), tx.GetHash(),
+		blockNo, ts, "", false, op.Recipient, false, gasLimit-b.gasUsed, op.Amount)
+	ret, gasUsed, err := contract.Execute(contractState, op.Payload, op.Recipient, bcCtx)
+	b.gasUsed += gasUsed
+	return ret, err
+}
@@ -15,7 +15,8 @@ type BlockValidator struct {
 }
 
 var (
-	ErrorBlockVerifySign = errors.New("Block verify failed, because Tx sign is invalid")
+	ErrorBlockVerifySign   = errors.New("Block verify failed, because Tx sign is invalid")
+	ErrorBlockVerifyWindow = errors.New("Block verify failed, because Tx is out of its validity window")
 )
 
 func NewBlockValidator() *BlockValidator {
@@ -60,6 +61,14 @@ func (bv *BlockValidator) ValidateBody(block *types.Block) error {
 		return nil
 	}
 
+	for _, tx := range txs {
+		if !tx.GetBody().IsValidAt(block.BlockNo()) {
+			logger.Error().Str("block", block.ID()).Str("tx", types.EncodeB64(tx.GetHash())).
+				Msg("tx is out of its validity window")
+			return ErrorBlockVerifyWindow
+		}
+	}
+
 	failed, _ := bv.signVerifier.VerifyTxs(&types.TxList{Txs: txs})
 
 	if failed {
@@ -263,6 +263,16 @@ func (cdb *ChainDB) addBlock(dbtx *db.Transaction, block *types.Block, isMainCha
 func (cdb *ChainDB) getBestBlockNo() types.BlockNo {
 	return cdb.latest
 }
+
+// loadBestBlockNo reads the number of the best block stored in the db. Unlike
+// getBestBlockNo, it can be called outside of the actor of the chain service.
+func (cdb *ChainDB) loadBestBlockNo() types.BlockNo {
+	latestBytes := cdb.store.Get(latestKey)
+	if len(latestBytes) == 0 {
+		return 0
+	}
+	return types.BlockNoFromBytes(latestBytes)
+}
 func (cdb *ChainDB) getBlockByNo(blockNo types.BlockNo) (*types.Block, error) {
 	blockHash, err := cdb.getHashByNo(blockNo)
 	if err != nil {
@@ -9,9 +9,6 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"strconv"
-
-	sha256 "github.com/minio/sha256-simd"
 
 	"github.com/aergoio/aergo-lib/db"
 	"github.com/aergoio/aergo/contract"
@@ -119,6 +116,9 @@ func (cs *ChainService) addBlock(nblock *types.Block, usedBstate *types.BlockSta
 				return err
 			}
 			processedTxn = len(tblock.GetBody().GetTxs())
+		} else {
+			// the received bloom is not covered by the block hash
+			tblock.ClearEventBloom()
 		}
 
 		if err = cs.cdb.addBlock(&dbtx, tblock, isMainChain, true); err != nil {
@@ -186,6 +186,7 @@ type executor struct {
 	sdb        *state.ChainStateDB
 	blockState *types.BlockState
 	execTx     txExecFn
+	block      *types.Block
 	txs        []*types.Tx
 }
 
@@ -202,6 +203,7 @@ func newExecutor(sdb *state.ChainStateDB, bState *types.BlockState, block *types
 			return executeTx(sdb, bState, tx, dbTx, block.BlockNo(), block.GetHeader().GetTimestamp())
 		}
 	}
+	bState.Coinbase = block.GetHeader().GetCoinbaseAccount()
 
 	txs := block.GetBody().GetTxs()
 
@@ -209,6 +211,7 @@ func newExecutor(sdb *state.ChainStateDB, bState *types.BlockState, block *types
 		sdb:        sdb,
 		blockState: bState,
 		execTx:     exec,
+		block:      block,
 		txs:        txs,
 	}
 }
@@ -224,6 +227,8 @@ func (e *executor) execute() error {
 		}
 	}
 	dbTx.Commit()
+	// the block is stored with the event bloom of its execution
+	e.block.SetEventBloom(&e.blockState.EventBloom)
 
 	// TODO: sync status of bstate and cdb what to do if cdb.commit fails after
 	// sdb.Apply() succeeds
@@ -249,11 +254,14 @@ func (cs *ChainService) executeBlock(bstate *types.BlockState, block *types.Bloc
 
 func executeTx(sdb *state.ChainStateDB, bs *types.BlockState, tx *types.Tx, dbTx db.Transaction, blockNo uint64, ts int64) error {
 	txBody := tx.GetBody()
-	senderID := types.ToAccountID(txBody.Account)
-	senderState, err := sdb.GetBlockAccountClone(bs, senderID)
-	if err != nil {
-		return err
+	if txBody.RunsContract() && txBody.Limit == 0 {
+		// the contracts would run for free
+		return types.ErrNoGasLimit
 	}
+	if txBody.Type == types.TxType_BATCH {
+		return executeBatchTx(sdb, bs, tx, dbTx, blockNo, ts)
+	}
+	senderID := types.ToAccountID(txBody.Account)
 	recipient := txBody.Recipient
 	var receiverID types.AccountID
 	var createContract bool
@@ -261,65 +269,157 @@ func executeTx(sdb *state.ChainStateDB, bs *types.BlockState, tx *types.Tx, dbTx
 		receiverID = types.ToAccountID(recipient)
 	} else {
 		createContract = true
-		h := sha256.New()
-		h.Write(txBody.Account)
-		h.Write([]byte(strconv.FormatUint(txBody.Nonce, 10)))
-		recipient = h.Sum(nil)[:20]
+		recipient = types.ContractAddress(txBody.Account, txBody.Nonce)
 		receiverID = types.ToAccountID(recipient)
 	}
-	receiverState, err := sdb.GetBlockAccountClone(bs, receiverID)
+
+	// every account changed by the tx, including the ones changed by
+	// contracts, is put to the block state together at the end
+	stateSet := contract.NewStateSet(sdb, bs)
+	if err := chargeTx(stateSet, txBody); err != nil {
+		return err
+	}
+	senderChange, err := stateSet.GetAccount(senderID)
+	if err != nil {
+		return err
+	}
+	receiverChange, err := stateSet.GetAccount(receiverID)
 	if err != nil {
 		return err
 	}
 
-	senderChange := types.Clone(*senderState).(types.State)
-	receiverChange := types.Clone(*receiverState).(types.State)
-
+	var gasUsed uint64
 	switch txBody.Type {
 	case types.TxType_NORMAL:
 		if senderID != receiverID {
 			if senderChange.Balance < txBody.Amount {
 				senderChange.Balance = 0 // FIXME: reject insufficient tx.
 			} else {
-				senderChange.Balance = senderState.Balance - txBody.Amount
+				senderChange.Balance = senderChange.Balance - txBody.Amount
 			}
 			receiverChange.Balance = receiverChange.Balance + txBody.Amount
 		}
 		if txBody.Payload != nil {
-			contractState, err := sdb.OpenContractState(&receiverChange)
+			contractState, err := stateSet.OpenContract(receiverID)
 			if err != nil {
 				return err
 			}
 
+			bcCtx := contract.NewContext(stateSet, contractState, txBody.GetAccount(), tx.GetHash(),
+				blockNo, ts, "", false, recipient, false, txBody.GetLimit(), txBody.GetAmount())
 			if createContract {
-				err = contract.Create(contractState, txBody.Payload, recipient, tx.Hash, dbTx)
+				err = contract.Create(contractState, txBody.Payload, recipient, tx.Hash, bcCtx, dbTx)
 			} else {
-				bcCtx := contract.NewContext(contractState, txBody.GetAccount(), tx.GetHash(),
-					blockNo, ts, "", false, recipient, false)
-
 				err = contract.Call(contractState, txBody.Payload, recipient, tx.Hash, bcCtx, dbTx)
-				if err != nil {
-					return err
-				}
-				err = sdb.CommitContractState(contractState)
 			}
+			gasUsed = contract.GasUsed(bcCtx)
 			if err != nil {
-				return err
+				// the tx is consumed with the fee of the gas used, but the
+				// transfer and the changes made by the contract are
+				// discarded. the receipt tells the reason.
+				logger.Debug().Str("hash", types.EncodeB64(tx.GetHash())).Err(err).Msg("contract execution reverted")
+				return revertTx(sdb, bs, txBody, gasUsed)
 			}
 		}
+	case types.TxType_UPGRADE:
+		contractState, err := stateSet.OpenContract(receiverID)
+		if err != nil {
+			return err
+		}
+		err = contract.Upgrade(stateSet, contractState, txBody.Payload, recipient, txBody.Account, tx.GetHash(),
+			blockNo, dbTx)
+		if err != nil {
+			logger.Debug().Str("hash", types.EncodeB64(tx.GetHash())).Err(err).Msg("contract upgrade reverted")
+			return revertTx(sdb, bs, txBody, 0)
+		}
 	case types.TxType_GOVERNANCE:
-		err = executeGovernanceTx(sdb, txBody, &senderChange, &receiverChange, blockNo)
+		err = executeGovernanceTx(sdb, txBody, senderChange, receiverChange, blockNo)
+		if err != nil {
+			return err
+		}
 	default:
 		logger.Warn().Str("tx", tx.String()).Msg("unknown type of transaction")
 	}
 
-	senderChange.Nonce = txBody.Nonce
-	bs.PutAccount(senderID, senderState, &senderChange)
-	if senderID != receiverID {
-		bs.PutAccount(receiverID, receiverState, &receiverChange)
+	if err := settleTx(stateSet, bs, txBody, gasUsed); err != nil {
+		return err
+	}
+	return stateSet.Apply()
+}
+
+// ExecuteTx executes tx in the block state bs of the block blockNo made at
+// ts, and writes its receipt to dbTx. It is the execution of the txs of a
+// block, which the dummy chain of the contract tests uses without a chain
+// service.
+func ExecuteTx(sdb *state.ChainStateDB, bs *types.BlockState, tx *types.Tx, dbTx db.Transaction, blockNo uint64,
+	ts int64) error {
+	return executeTx(sdb, bs, tx, dbTx, blockNo, ts)
+}
+
+// revertTx discards every change made by the tx except the fee of gasUsed and
+// the nonce.
+func revertTx(sdb *state.ChainStateDB, bs *types.BlockState, txBody *types.TxBody, gasUsed uint64) error {
+	reverted := contract.NewStateSet(sdb, bs)
+	if err := chargeTx(reverted, txBody); err != nil {
+		return err
+	}
+	if err := settleTx(reverted, bs, txBody, gasUsed); err != nil {
+		return err
+	}
+	return reverted.Apply()
+}
+
+// chargeTx debits the greatest fee of the tx from the payer of the tx and
+// updates the nonce of the sender. They are applied even if the execution of
+// the tx is reverted. The fee of the gas not used is refunded by settleTx.
+func chargeTx(stateSet *contract.StateSet, txBody *types.TxBody) error {
+	fee, err := txBody.MaxFee()
+	if err != nil {
+		return err
+	}
+	// the fee is charged to the fee payer if the tx has one, otherwise to the sender
+	payer, err := stateSet.GetAccount(types.ToAccountID(txBody.Payer()))
+	if err != nil {
+		return err
+	}
+	if payer.Balance < fee {
+		return message.ErrInsufficientBalance
+	}
+	payer.Balance = payer.Balance - fee
+
+	sender, err := stateSet.GetAccount(types.ToAccountID(txBody.Account))
+	if err != nil {
+		return err
 	}
+	sender.Nonce = txBody.Nonce
+	return nil
+}
 
-	return err
+// settleTx credits the fee of gasUsed to the coinbase account of the block,
+// and refunds the rest of the fee charged by chargeTx to the payer of the tx.
+// The fee is entirely refunded in a block without a coinbase account.
+func settleTx(stateSet *contract.StateSet, bs *types.BlockState, txBody *types.TxBody, gasUsed uint64) error {
+	maxFee, err := txBody.MaxFee()
+	if err != nil {
+		return err
+	}
+	var fee uint64
+	if len(bs.Coinbase) > 0 {
+		if fee, err = txBody.GasFee(gasUsed); err != nil {
+			return err
+		}
+		coinbase, err := stateSet.GetAccount(types.ToAccountID(bs.Coinbase))
+		if err != nil {
+			return err
+		}
+		coinbase.Balance = coinbase.Balance + fee
+	}
+	payer, err := stateSet.GetAccount(types.ToAccountID(txBody.Payer()))
+	if err != nil {
+		return err
+	}
+	payer.Balance = payer.Balance + maxFee - fee
+	return nil
 }
 
 // find an orphan block which is the child of the added block
@@ -0,0 +1,82 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/aergoio/aergo/contract"
+	"github.com/aergoio/aergo/message"
+	"github.com/aergoio/aergo/types"
+)
+
+func TestChargeTx(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+	sender := []byte("feeSender")
+	payer := []byte("feePayer")
+	coinbase := []byte("feeCoinbase")
+
+	bs := types.NewBlockState(types.NewBlockInfo(1, types.ToBlockID([]byte("block1")), types.BlockID{}))
+	bs.PutAccount(types.ToAccountID(sender), nil, &types.State{Balance: 1000})
+	bs.PutAccount(types.ToAccountID(payer), nil, &types.State{Balance: 1000})
+	if err := sdb.Apply(bs); err != nil {
+		t.Fatalf("failed to apply block state: %s", err.Error())
+	}
+	balance := func(stateSet *contract.StateSet, account []byte) uint64 {
+		st, err := stateSet.GetAccount(types.ToAccountID(account))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return st.Balance
+	}
+
+	// the payer is charged the fee of the gas used, which is credited to the
+	// coinbase account
+	next := types.NewBlockState(types.NewBlockInfo(2, types.BlockID{}, bs.BlockHash))
+	next.Coinbase = coinbase
+	txBody := &types.TxBody{Nonce: 1, Account: sender, FeePayer: payer, Limit: 100, Price: 3}
+	stateSet := contract.NewStateSet(sdb, next)
+	if err := chargeTx(stateSet, txBody); err != nil {
+		t.Fatal(err)
+	}
+	if b := balance(stateSet, payer); b != 700 {
+		t.Errorf("the greatest fee is not charged: %d", b)
+	}
+	if err := settleTx(stateSet, next, txBody, 30); err != nil {
+		t.Fatal(err)
+	}
+	if b := balance(stateSet, payer); b != 910 {
+		t.Errorf("wrong balance of payer: %d", b)
+	}
+	if b := balance(stateSet, coinbase); b != 90 {
+		t.Errorf("wrong balance of coinbase: %d", b)
+	}
+	if b := balance(stateSet, sender); b != 1000 {
+		t.Errorf("the sender is charged instead of the fee payer: %d", b)
+	}
+
+	// a block without a coinbase account charges no fee
+	free := types.NewBlockState(types.NewBlockInfo(2, types.BlockID{}, bs.BlockHash))
+	stateSet = contract.NewStateSet(sdb, free)
+	if err := chargeTx(stateSet, txBody); err != nil {
+		t.Fatal(err)
+	}
+	if err := settleTx(stateSet, free, txBody, 30); err != nil {
+		t.Fatal(err)
+	}
+	if b := balance(stateSet, payer); b != 1000 {
+		t.Errorf("a block without coinbase charged a fee: %d", b)
+	}
+
+	stateSet = contract.NewStateSet(sdb, next)
+	if err := chargeTx(stateSet, &types.TxBody{Nonce: 1, Account: sender, Limit: 1001, Price: 1}); err != message.ErrInsufficientBalance {
+		t.Errorf("expected insufficient balance, got %v", err)
+	}
+	if err := chargeTx(stateSet, &types.TxBody{Nonce: 1, Account: sender, Limit: 1 << 32, Price: 1 << 32}); err != types.ErrFeeOverflow {
+		t.Errorf("expected the overflow of the fee, got %v", err)
+	}
+}
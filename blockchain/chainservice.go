@@ -8,6 +8,7 @@ package blockchain
 import (
 	"os"
 	"path"
+	"time"
 
 	"github.com/aergoio/aergo-actor/actor"
 	"github.com/aergoio/aergo-lib/db"
@@ -48,6 +49,7 @@ func NewChainService(cfg *cfg.Config, cc consensus.ChainConsensus) *ChainService
 		op:             NewOrphanPool(),
 	}
 	Init(cfg.Blockchain.MaxBlockSize)
+	contract.SetQueryTimeout(time.Duration(cfg.Blockchain.QueryTimeout) * time.Millisecond)
 	if cc != nil {
 		cc.SetStateDB(actor.sdb)
 	}
@@ -273,15 +275,19 @@ func (cs *ChainService) Receive(context actor.Context) {
 				Err: err,
 			})
 		}
-	case *message.GetQuery:
-
-		state, err := cs.sdb.OpenContractStateAccount(types.ToAccountID(msg.Contract))
-		if err != nil {
-			logger.Error().Str("hash", enc.ToString(msg.Contract)).Err(err).Msg("failed to get state for contract")
-			context.Respond(message.GetQueryRsp{Result: nil, Err: err})
+	case *message.GetContractInfo:
+		contractState, err := cs.sdb.OpenContractStateAccount(types.ToAccountID(msg.Contract))
+		if err == nil {
+			info, err := contract.GetContractInfo(contractState, msg.Contract)
+			context.Respond(message.GetContractInfoRsp{
+				Info: info,
+				Err:  err,
+			})
 		} else {
-			ret, err := contract.Query(msg.Contract, state, msg.Queryinfo)
-			context.Respond(message.GetQueryRsp{Result: ret, Err: err})
+			context.Respond(message.GetContractInfoRsp{
+				Info: nil,
+				Err:  err,
+			})
 		}
 	case *message.SyncBlockState:
 		cs.checkBlockHandshake(msg.PeerID, msg.BlockNo, msg.BlockHash)
@@ -311,3 +317,11 @@ func (cs *ChainService) Statics() *map[string]interface{} {
 func (cs *ChainService) GetChainTree() ([]byte, error) {
 	return cs.cdb.GetChainTree()
 }
+
+// QueryContract runs a view function of a contract on a snapshot of the
+// latest state. It is called by many goroutines at the same time, outside of
+// the actor of the chain service, so that the queries never wait for the
+// blocks being executed.
+func (cs *ChainService) QueryContract(contractAddress, queryInfo []byte) ([]byte, error) {
+	return contract.Query(contractAddress, cs.sdb.GetSnapshot(), queryInfo)
+}
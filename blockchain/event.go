@@ -0,0 +1,67 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aergoio/aergo/contract"
+	"github.com/aergoio/aergo/types"
+)
+
+// maxEventBlockRange bounds the number of blocks searched by a single event
+// query
+const maxEventBlockRange = 10000
+
+var errEventBlockRange = errors.New("blockfrom is greater than blockto")
+
+// ListEvents returns the contract events matching the filter. A zero blockto
+// means the best block. Like QueryContract, it is called outside of the actor
+// of the chain service, and reads only the chain db and contract.DB. Blocks whose event bloom in their header does not match
+// the filter are skipped without reading their receipts. A block executed by a
+// reorganization is stored without a bloom, so its receipts are always read.
+func (cs *ChainService) ListEvents(filter *types.FilterInfo) ([]*types.Event, error) {
+	from, to := filter.GetBlockfrom(), filter.GetBlockto()
+	best := cs.cdb.loadBestBlockNo()
+	if to == 0 || to > best {
+		to = best
+	}
+	if from > to {
+		return nil, errEventBlockRange
+	}
+	if to-from >= maxEventBlockRange {
+		return nil, fmt.Errorf("too many blocks to search (max %d)", maxEventBlockRange)
+	}
+
+	var events []*types.Event
+	for blockNo := from; blockNo <= to; blockNo++ {
+		block, err := cs.getBlockByNo(blockNo)
+		if err != nil {
+			return nil, err
+		}
+		bloom := block.EventBloom()
+		if bloom != nil && !bloom.MatchFilter(filter) {
+			continue
+		}
+		for idx, tx := range block.GetBody().GetTxs() {
+			receipt, err := contract.GetReceipt(tx.GetHash())
+			if err != nil {
+				continue
+			}
+			for _, ev := range receipt.Events {
+				if !ev.MatchFilter(filter) {
+					continue
+				}
+				ev.BlockHash = block.BlockHash()
+				ev.BlockNo = blockNo
+				ev.TxIndex = int32(idx)
+				events = append(events, ev)
+			}
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,194 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/aergoio/aergo/contract"
+	"github.com/aergoio/aergo/state"
+	"github.com/aergoio/aergo/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/mr-tron/base58/base58"
+)
+
+var (
+	errSimulateNoTx         = errors.New("no tx to simulate")
+	errSimulateGovernance   = errors.New("governance tx cannot be simulated")
+	errSimulateNoReceipt    = errors.New("simulated tx has no receipt")
+	errSimulateInvalidNonce = errors.New("nonce of the simulated tx is not the next one")
+	errSimulateBusy         = errors.New("too many txs are being simulated")
+)
+
+// maxSimulations is the number of the txs simulated at the same time. The
+// requests over it fail at once instead of waiting.
+const maxSimulations = 4
+
+var simulations = make(chan struct{}, maxSimulations)
+
+// simulationTx keeps the receipts written by a simulated tx in memory
+// instead of contract.DB.
+type simulationTx struct {
+	values map[string][]byte
+}
+
+func newSimulationTx() *simulationTx {
+	return &simulationTx{values: map[string][]byte{}}
+}
+
+func (t *simulationTx) Set(key, value []byte) {
+	t.values[string(key)] = value
+}
+
+func (t *simulationTx) Delete(key []byte) {
+	delete(t.values, string(key))
+}
+
+func (t *simulationTx) Commit()  {}
+func (t *simulationTx) Discard() {}
+
+// SimulateTx runs tx on a snapshot of the latest state. Like QueryContract,
+// it is called outside of the actor of the chain service, and at most
+// maxSimulations txs are simulated at once.
+func (cs *ChainService) SimulateTx(tx *types.Tx) (*types.SimulateResult, error) {
+	select {
+	case simulations <- struct{}{}:
+		defer func() { <-simulations }()
+	default:
+		return nil, errSimulateBusy
+	}
+	snapshot := cs.sdb.GetSnapshot()
+	latest := snapshot.BlockInfo()
+	best, err := cs.getBlock(latest.BlockHash[:])
+	if err != nil {
+		return nil, err
+	}
+	// the producer of the next block is not known, so the fee is credited as
+	// in the latest block
+	return simulateTx(cs.sdb, snapshot, best.GetHeader().GetCoinbaseAccount(), tx)
+}
+
+// simulateTx runs tx on snapshot as if it were the only tx of the next block.
+// The block state of the run is thrown away, so neither the state db nor
+// contract.DB is changed. The tx does not need to be signed, and its
+// contracts fail after QueryTimeout like the queries.
+func simulateTx(sdb *state.ChainStateDB, snapshot *state.Snapshot, coinbase []byte,
+	tx *types.Tx) (*types.SimulateResult, error) {
+	txBody := tx.GetBody()
+	if txBody == nil {
+		return nil, errSimulateNoTx
+	}
+	if txBody.Type == types.TxType_GOVERNANCE {
+		// the votes are committed to the state db while they are executed
+		return nil, errSimulateGovernance
+	}
+	sender, err := snapshot.GetAccountStateClone(types.ToAccountID(txBody.Account))
+	if err != nil {
+		return nil, err
+	}
+	if txBody.Nonce != sender.Nonce+1 {
+		return nil, errSimulateInvalidNonce
+	}
+	if len(tx.Hash) == 0 {
+		tx.Hash = tx.CalculateTxHash()
+	}
+
+	latest := snapshot.BlockInfo()
+	blockNo := latest.BlockNo + 1
+	bs := snapshot.NewBlockState(types.NewBlockInfo(blockNo, types.BlockID{}, latest.BlockHash))
+	bs.DryRun = true
+	bs.Coinbase = coinbase
+	if contract.QueryTimeout > 0 {
+		bs.Deadline = time.Now().Add(contract.QueryTimeout).UnixNano()
+	}
+
+	dbTx := newSimulationTx()
+	if err := executeTx(sdb, bs, tx, dbTx, blockNo, time.Now().UnixNano()); err != nil {
+		return nil, err
+	}
+	receipt := &types.Receipt{Status: "SUCCESS"}
+	if data, ok := dbTx.values[string(tx.Hash)]; ok {
+		receipt = types.NewReceiptFromBytes(data)
+	} else if len(txBody.Payload) > 0 {
+		return nil, errSimulateNoReceipt
+	}
+	if receipt.Status == contract.ErrQueryTimeout.Error() {
+		// the tx would not time out in a block
+		return nil, contract.ErrQueryTimeout
+	}
+
+	fee, err := txBody.GasFee(receipt.GasUsed)
+	if err != nil {
+		return nil, err
+	}
+	var charged uint64
+	if len(bs.Coinbase) > 0 {
+		charged = fee
+	}
+	return &types.SimulateResult{
+		Receipt:        receipt,
+		Fee:            fee,
+		Charged:        charged,
+		BalanceChanges: balanceChanges(bs, simulatedAddresses(tx, receipt)),
+	}, nil
+}
+
+// simulatedAddresses returns the addresses which a tx may change, keyed by
+// their account ids.
+func simulatedAddresses(tx *types.Tx, receipt *types.Receipt) map[types.AccountID][]byte {
+	txBody := tx.GetBody()
+	addresses := [][]byte{txBody.Account, txBody.Recipient, txBody.Payer(), receipt.ContractAddress}
+	if txBody.Type == types.TxType_BATCH {
+		var ops types.Batch
+		if err := proto.Unmarshal(txBody.Payload, &ops); err == nil {
+			for _, op := range ops.Operations {
+				addresses = append(addresses, op.Recipient)
+			}
+		}
+	}
+	for _, trace := range receipt.CallTrace {
+		if address, err := base58.Decode(trace.Contract); err == nil {
+			addresses = append(addresses, address)
+		}
+	}
+	for _, ev := range receipt.Events {
+		addresses = append(addresses, ev.ContractAddress)
+	}
+
+	ids := make(map[types.AccountID][]byte)
+	for _, address := range addresses {
+		if len(address) > 0 {
+			ids[types.ToAccountID(address)] = address
+		}
+	}
+	return ids
+}
+
+// balanceChanges returns the accounts of bs whose balance is changed, sorted
+// by their account ids.
+func balanceChanges(bs *types.BlockState, addresses map[types.AccountID][]byte) []*types.BalanceChange {
+	var changes []*types.BalanceChange
+	for aid, st := range bs.GetAccountStates() {
+		before := bs.Undo.Accounts[aid].GetBalance()
+		if before == st.GetBalance() {
+			continue
+		}
+		id := aid
+		changes = append(changes, &types.BalanceChange{
+			AccountID: id[:],
+			Address:   addresses[aid],
+			Before:    before,
+			After:     st.GetBalance(),
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return bytes.Compare(changes[i].AccountID, changes[j].AccountID) < 0
+	})
+	return changes
+}
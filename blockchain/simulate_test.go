@@ -0,0 +1,143 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package blockchain
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/aergoio/aergo-lib/db"
+	"github.com/aergoio/aergo/cmd/aergoluac/luac"
+	"github.com/aergoio/aergo/contract"
+	"github.com/aergoio/aergo/types"
+)
+
+func TestSimulateTransfer(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+	sender := []byte("simulateSender")
+	recipient := []byte("simulateRecipient")
+
+	bs := types.NewBlockState(types.NewBlockInfo(1, types.ToBlockID([]byte("block1")), types.BlockID{}))
+	bs.PutAccount(types.ToAccountID(sender), nil, &types.State{Balance: 1000})
+	if err := sdb.Apply(bs); err != nil {
+		t.Fatalf("failed to apply block state: %s", err.Error())
+	}
+
+	tx := &types.Tx{
+		Body: &types.TxBody{
+			Nonce:     1,
+			Account:   sender,
+			Recipient: recipient,
+			Amount:    100,
+		},
+	}
+	tx.Hash = tx.CalculateTxHash()
+	snapshot := sdb.GetSnapshot()
+
+	// a block applied after the snapshot does not change the simulation
+	next := types.NewBlockState(types.NewBlockInfo(2, types.ToBlockID([]byte("block2")), bs.BlockHash))
+	next.PutAccount(types.ToAccountID(sender), nil, &types.State{Balance: 5000})
+	if err := sdb.Apply(next); err != nil {
+		t.Fatalf("failed to apply block state: %s", err.Error())
+	}
+
+	result, err := simulateTx(sdb, snapshot, nil, tx)
+	if err != nil {
+		t.Fatalf("failed to simulate tx: %s", err.Error())
+	}
+	changes := result.BalanceChanges
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 balance changes, got %d", len(changes))
+	}
+	for _, c := range changes {
+		switch {
+		case bytes.Equal(c.Address, sender):
+			if c.Before != 1000 || c.After != 900 {
+				t.Errorf("wrong balance change of sender: %d -> %d", c.Before, c.After)
+			}
+		case bytes.Equal(c.Address, recipient):
+			if c.Before != 0 || c.After != 100 {
+				t.Errorf("wrong balance change of recipient: %d -> %d", c.Before, c.After)
+			}
+		default:
+			t.Errorf("unknown account changed: %v", c.AccountID)
+		}
+	}
+
+	st, err := sdb.GetAccountStateClone(types.ToAccountID(sender))
+	if err != nil {
+		t.Fatalf("failed to get sender state: %s", err.Error())
+	}
+	if st.Balance != 5000 || st.Nonce != 0 {
+		t.Errorf("simulation changed the state db: balance %d, nonce %d", st.Balance, st.Nonce)
+	}
+}
+
+func TestSimulateLimits(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+	prevDB := contract.DB
+	contract.DB = db.NewDB(db.MemoryImpl, "")
+	defer func() { contract.DB = prevDB }()
+	defer contract.SetQueryTimeout(contract.QueryTimeout)
+	contract.SetQueryTimeout(100 * time.Millisecond)
+
+	sender := []byte("simulateSender")
+	address := []byte("simulateLoop")
+	code, err := luac.Payload([]byte(`function loop() while true do end end abi.register(loop)`))
+	if err != nil {
+		t.Fatalf("failed to compile: %s", err.Error())
+	}
+	contractState, err := sdb.OpenContractState(types.NewState())
+	if err != nil {
+		t.Fatalf("failed to open contract state: %s", err.Error())
+	}
+	if err := contractState.SetCode(code); err != nil {
+		t.Fatalf("failed to set code: %s", err.Error())
+	}
+	if err := sdb.CommitContractState(contractState); err != nil {
+		t.Fatalf("failed to commit contract state: %s", err.Error())
+	}
+	bs := types.NewBlockState(types.NewBlockInfo(1, types.ToBlockID([]byte("block1")), types.BlockID{}))
+	bs.PutAccount(types.ToAccountID(sender), nil, &types.State{Balance: 1000})
+	bs.PutAccount(types.ToAccountID(address), nil, contractState.State)
+	if err := sdb.Apply(bs); err != nil {
+		t.Fatalf("failed to apply block state: %s", err.Error())
+	}
+
+	// the tx runs out of time long before it runs out of gas
+	tx := &types.Tx{
+		Body: &types.TxBody{
+			Nonce:     1,
+			Account:   sender,
+			Recipient: address,
+			Payload:   []byte(`{"Name":"loop", "Args":[]}`),
+			Limit:     contract.MaxGasLimit,
+		},
+	}
+	start := time.Now()
+	if _, err := simulateTx(sdb, sdb.GetSnapshot(), nil, tx); err != contract.ErrQueryTimeout {
+		t.Errorf("expected the query timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("simulation ran for %v", elapsed)
+	}
+
+	cs := &ChainService{sdb: sdb}
+	for i := 0; i < maxSimulations; i++ {
+		simulations <- struct{}{}
+	}
+	defer func() {
+		for i := 0; i < maxSimulations; i++ {
+			<-simulations
+		}
+	}()
+	if _, err := cs.SimulateTx(tx); err != errSimulateBusy {
+		t.Errorf("expected busy simulations, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package blockchain
+
+import (
+	"errors"
+
+	"github.com/aergoio/aergo/contract/token"
+	"github.com/aergoio/aergo/state"
+	"github.com/aergoio/aergo/types"
+)
+
+var errNotToken = errors.New("contract is not a token")
+
+// TokenBalance returns the balance of account in the token contract in
+// snapshot. The balance is read from the state variable of the token without
+// running the contract.
+func TokenBalance(snapshot *state.Snapshot, tokenAddress, account []byte) (*types.TokenBalance, error) {
+	contractState, err := snapshot.OpenContractStateAccount(types.ToAccountID(tokenAddress))
+	if err != nil {
+		return nil, err
+	}
+	if !token.IsToken(contractState.GetCodeHash()) {
+		return nil, errNotToken
+	}
+	data, err := contractState.GetData(token.BalanceKey(tokenAddress, account))
+	if err != nil {
+		return nil, err
+	}
+	balance, err := token.ParseAmount(data)
+	if err != nil {
+		return nil, err
+	}
+	return &types.TokenBalance{Token: tokenAddress, Account: account, Balance: balance.String()}, nil
+}
+
+// GetTokenBalance returns the balance of account in a token contract on a
+// snapshot of the latest state, outside of the actor of the chain service.
+func (cs *ChainService) GetTokenBalance(tokenAddress, account []byte) (*types.TokenBalance, error) {
+	return TokenBalance(cs.sdb.GetSnapshot(), tokenAddress, account)
+}
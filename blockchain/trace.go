@@ -0,0 +1,95 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package blockchain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aergoio/aergo/contract"
+	"github.com/aergoio/aergo/types"
+)
+
+var (
+	errTraceNoReceipt  = errors.New("traced tx has no receipt")
+	errTraceGovernance = errors.New("cannot trace a tx of a block with governance txs before it")
+	errTraceBusy       = errors.New("too many txs are being traced")
+)
+
+// maxTraces is the number of the txs traced at the same time. The requests
+// over it fail at once instead of waiting.
+const maxTraces = 2
+
+var traces = make(chan struct{}, maxTraces)
+
+// TraceTx traces a tx outside of the actor of the chain service, like
+// QueryContract. The block of the tx is executed on the state root before it,
+// which is not changed by the blocks applied meanwhile. At most maxTraces txs
+// are traced at once.
+func (cs *ChainService) TraceTx(txHash []byte) (*types.Receipt, error) {
+	select {
+	case traces <- struct{}{}:
+		defer func() { <-traces }()
+	default:
+		return nil, errTraceBusy
+	}
+	return cs.traceTx(txHash)
+}
+
+// traceTx executes a tx of the main chain again and returns its receipt with
+// the steps of its contract executions. The txs are executed as a dry run on
+// the state root before the block of the tx, and the txs before it in the
+// block are executed first without tracing. The block state is thrown away and
+// the receipts are kept in memory. The governance txs commit their votes while
+// they are executed, so a tx with a governance tx at or before it in its block
+// is not traced. The whole execution fails after QueryTimeout like a query.
+func (cs *ChainService) traceTx(txHash []byte) (*types.Receipt, error) {
+	tx, txIdx, err := cs.getTx(txHash)
+	if err != nil {
+		return nil, err
+	}
+	block, err := cs.getBlock(txIdx.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	txs := block.GetBody().GetTxs()[:txIdx.Idx+1]
+	for _, t := range txs {
+		if t.GetBody().GetType() == types.TxType_GOVERNANCE {
+			return nil, errTraceGovernance
+		}
+	}
+	bs, err := cs.sdb.RewindBlockState(block.BlockID())
+	if err != nil {
+		return nil, err
+	}
+	bs.DryRun = true
+	bs.Coinbase = block.GetHeader().GetCoinbaseAccount()
+	if contract.QueryTimeout > 0 {
+		bs.Deadline = time.Now().Add(contract.QueryTimeout).UnixNano()
+	}
+
+	blockNo := block.BlockNo()
+	ts := block.GetHeader().GetTimestamp()
+	dbTx := newSimulationTx()
+	for _, prev := range txs[:txIdx.Idx] {
+		if err := executeTx(cs.sdb, bs, prev, dbTx, blockNo, ts); err != nil {
+			return nil, err
+		}
+	}
+	bs.Trace = true
+	if err := executeTx(cs.sdb, bs, tx, dbTx, blockNo, ts); err != nil {
+		return nil, err
+	}
+	// a tx before the traced one may have failed by the deadline instead
+	if bs.Deadline > 0 && time.Now().UnixNano() > bs.Deadline {
+		return nil, contract.ErrQueryTimeout
+	}
+	data, ok := dbTx.values[string(tx.GetHash())]
+	if !ok {
+		return nil, errTraceNoReceipt
+	}
+	return types.NewReceiptFromBytes(data), nil
+}
@@ -0,0 +1,83 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/aergoio/aergo/cmd/aergoluac/luac"
+	"github.com/aergoio/aergo/contract"
+	"github.com/aergoio/aergo/state"
+	"github.com/aergoio/aergo/types"
+)
+
+var (
+	errVerifyNoContract   = errors.New("cannot find contract")
+	errVerifyNoCompiler   = errors.New("contract is not compiled by a compiler recorded in its abi")
+	errVerifyCodeMismatch = errors.New("source is not compiled to the code of the contract")
+	errVerifyBusy         = errors.New("too many sources are being verified")
+)
+
+// maxVerifications is the number of the sources compiled at the same time.
+// The requests over it fail at once instead of waiting.
+const maxVerifications = 4
+
+var verifications = make(chan struct{}, maxVerifications)
+
+// VerifySource compiles the Lua source of a contract and compares the result
+// with the code of the contract in snapshot. The code must be compiled by the
+// same compiler, which is recorded in the ABI of the code. The verified
+// source is stored with the ABI, which GetABI returns afterward.
+func VerifySource(snapshot *state.Snapshot, contractAddress []byte, source string) (*types.ABI, error) {
+	contractState, err := snapshot.OpenContractStateAccount(types.ToAccountID(contractAddress))
+	if err != nil {
+		return nil, err
+	}
+	code, err := contractState.GetCode()
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return nil, errVerifyNoContract
+	}
+	abi, err := contract.GetABI(contractState, contractAddress)
+	if err != nil {
+		return nil, err
+	}
+	if abi.Compiler == "" {
+		return nil, errVerifyNoCompiler
+	}
+	if abi.Compiler != luac.Compiler() {
+		return nil, fmt.Errorf("contract is compiled by %s, but the node has %s", abi.Compiler, luac.Compiler())
+	}
+	compiled, err := luac.Payload([]byte(source))
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(compiled, code) {
+		return nil, errVerifyCodeMismatch
+	}
+	abi.Source = source
+	if err := contract.SetVerifiedABI(contractState.GetCodeHash(), abi); err != nil {
+		return nil, err
+	}
+	return abi, nil
+}
+
+// VerifyContractSource verifies the source of a contract on a snapshot of the
+// latest state. Like QueryContract, it is called outside of the actor of the
+// chain service, and at most maxVerifications sources are compiled at once.
+func (cs *ChainService) VerifyContractSource(contractAddress []byte, source string) (*types.ABI, error) {
+	select {
+	case verifications <- struct{}{}:
+		defer func() { <-verifications }()
+	default:
+		return nil, errVerifyBusy
+	}
+	return VerifySource(cs.sdb.GetSnapshot(), contractAddress, source)
+}
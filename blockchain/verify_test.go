@@ -0,0 +1,81 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/aergoio/aergo-lib/db"
+	"github.com/aergoio/aergo/cmd/aergoluac/luac"
+	"github.com/aergoio/aergo/contract"
+	"github.com/aergoio/aergo/types"
+)
+
+func TestVerifySource(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+	prevDB := contract.DB
+	contract.DB = db.NewDB(db.MemoryImpl, "")
+	defer func() { contract.DB = prevDB }()
+
+	const src = `function hello(say) return "Hello " .. say end abi.register(hello)`
+	address := []byte("verifiedContract")
+	code, err := luac.Payload([]byte(src))
+	if err != nil {
+		t.Fatalf("failed to compile: %s", err.Error())
+	}
+	contractState, err := sdb.OpenContractState(types.NewState())
+	if err != nil {
+		t.Fatalf("failed to open contract state: %s", err.Error())
+	}
+	if err := contractState.SetCode(code); err != nil {
+		t.Fatalf("failed to set code: %s", err.Error())
+	}
+	if err := sdb.CommitContractState(contractState); err != nil {
+		t.Fatalf("failed to commit contract state: %s", err.Error())
+	}
+	bs := types.NewBlockState(types.NewBlockInfo(1, types.ToBlockID([]byte("block1")), types.BlockID{}))
+	bs.PutAccount(types.ToAccountID(address), nil, contractState.State)
+	if err := sdb.Apply(bs); err != nil {
+		t.Fatalf("failed to apply block state: %s", err.Error())
+	}
+	snapshot := sdb.GetSnapshot()
+
+	other := `function hello(say) return "Bye " .. say end abi.register(hello)`
+	if _, err := VerifySource(snapshot, address, other); err != errVerifyCodeMismatch {
+		t.Errorf("expected the mismatch of the other source, got %v", err)
+	}
+	if _, err := VerifySource(snapshot, []byte("noContract"), src); err != errVerifyNoContract {
+		t.Errorf("expected no contract, got %v", err)
+	}
+
+	deployed, err := snapshot.OpenContractStateAccount(types.ToAccountID(address))
+	if err != nil {
+		t.Fatalf("failed to open contract state: %s", err.Error())
+	}
+	abi, err := contract.GetABI(deployed, address)
+	if err != nil {
+		t.Fatalf("failed to get abi: %s", err.Error())
+	}
+	if abi.Source != "" {
+		t.Errorf("unverified abi has the source %q", abi.Source)
+	}
+
+	abi, err = VerifySource(snapshot, address, src)
+	if err != nil {
+		t.Fatalf("failed to verify the source: %s", err.Error())
+	}
+	if abi.Source != src {
+		t.Errorf("verified abi has the source %q", abi.Source)
+	}
+	abi, err = contract.GetABI(deployed, address)
+	if err != nil {
+		t.Fatalf("failed to get abi: %s", err.Error())
+	}
+	if abi.Source != src || abi.Compiler != luac.Compiler() {
+		t.Errorf("abi of the verified contract: source %q, compiler %q", abi.Source, abi.Compiler)
+	}
+}
@@ -35,11 +35,19 @@ func init() {
 	lockAccountCmd.MarkFlagRequired("address")
 	lockAccountCmd.Flags().StringVar(&pw, "password", "", "password")
 	lockAccountCmd.MarkFlagRequired("password")
+	rootCmd.AddCommand(getPubKeyCmd)
+	getPubKeyCmd.Flags().StringVar(&address, "address", "", "address of account")
+	getPubKeyCmd.MarkFlagRequired("address")
+	getPubKeyCmd.Flags().StringVar(&pw, "password", "", "password")
+	getPubKeyCmd.Flags().StringVar(&dataDir, "path", "$HOME/.aergo/data/cli", "path to data directory")
+	rootCmd.AddCommand(multiSigAddressCmd)
+	multiSigAddressCmd.Flags().Uint32Var(&threshold, "threshold", 1, "number of signatures required")
 }
 
 var pw string
 var remote bool
 var dataDir string
+var threshold uint32
 var newAccountCmd = &cobra.Command{
 	Use:     "newaccount",
 	Short:   "Create new account in the node or cli",
@@ -171,6 +179,56 @@ var unlockAccountCmd = &cobra.Command{
 	},
 }
 
+var getPubKeyCmd = &cobra.Command{
+	Use:   "getpubkey",
+	Short: "Get public key of the account in cli",
+	Run: func(cmd *cobra.Command, args []string) {
+		addr, err := base58.Decode(address)
+		if err != nil {
+			fmt.Printf("Failed: %s\n", err.Error())
+			return
+		}
+		if pw == "" {
+			pw, err = getPasswd()
+			if err != nil {
+				fmt.Printf("Failed: %s\n", err.Error())
+				return
+			}
+		}
+		dataEnvPath := os.ExpandEnv(dataDir)
+		ks := key.NewStore(dataEnvPath)
+		pubKey, err := ks.GetPubKey(addr, pw)
+		if err != nil {
+			fmt.Printf("Failed: %s\n", err.Error())
+			return
+		}
+		fmt.Println(base58.Encode(pubKey))
+	},
+}
+
+var multiSigAddressCmd = &cobra.Command{
+	Use:   "multisigaddress [flags] pubkey...",
+	Short: "Generate address of multi-signature account from public keys of members",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pubKeys := make([][]byte, len(args))
+		for i, arg := range args {
+			pubKey, err := base58.Decode(arg)
+			if err != nil {
+				fmt.Printf("Failed: %s\n", err.Error())
+				return
+			}
+			pubKeys[i] = pubKey
+		}
+		addr, err := key.GenerateMultiSigAddress(threshold, pubKeys)
+		if err != nil {
+			fmt.Printf("Failed: %s\n", err.Error())
+			return
+		}
+		fmt.Println(base58.Encode(addr))
+	},
+}
+
 func parsePersonalParam() (*types.Personal, error) {
 	var err error
 	param := &types.Personal{Account: &types.Account{}}
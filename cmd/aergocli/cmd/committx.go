@@ -29,7 +29,7 @@ var committxCmd = &cobra.Command{
 
 var nonce uint64
 var recipient string
-var price int64
+var price uint64
 
 //var script string
 var jsonTx string
@@ -10,6 +10,7 @@ import (
 	"os"
 
 	"github.com/aergoio/aergo/cmd/aergocli/util"
+	"github.com/aergoio/aergo/contract/token"
 	"github.com/aergoio/aergo/types"
 	"github.com/mr-tron/base58/base58"
 	"github.com/spf13/cobra"
@@ -17,8 +18,12 @@ import (
 )
 
 var (
-	client *util.ConnClient
-	data   string
+	client       *util.ConnClient
+	data         string
+	addressNonce uint64
+	salt         string
+	decimals     uint32
+	gasLimit     uint64
 )
 
 func init() {
@@ -30,20 +35,67 @@ func init() {
 	}
 
 	deployCmd := &cobra.Command{
-		Use:   "deploy [flags] creator [bcfile] [abifile]",
+		Use:   "deploy [flags] creator [bcfile] [abifile] [args]",
 		Short: "deploy a contract",
 		Args:  cobra.MinimumNArgs(1),
 		Run:   runDeployCmd,
 	}
 	deployCmd.PersistentFlags().StringVar(&data, "payload", "", "result of compiling a contract")
 
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade [flags] owner contract [bcfile] [abifile]",
+		Short: "replace the code of an upgradable contract",
+		Args:  cobra.MinimumNArgs(2),
+		Run:   runUpgradeCmd,
+	}
+	upgradeCmd.PersistentFlags().StringVar(&data, "payload", "", "result of compiling a contract")
+
+	addressCmd := &cobra.Command{
+		Use:   "address [flags] creator [bcfile] [abifile]",
+		Short: "compute the address of a contract before it is deployed",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   runGetContractAddressCmd,
+	}
+	addressCmd.Flags().Uint64Var(&addressNonce, "nonce", 0, "nonce of the deploy tx, the next nonce of the creator if 0")
+	addressCmd.Flags().StringVar(&salt, "salt", "", "salt of a contract deployed by a factory, which needs the code")
+	addressCmd.Flags().StringVar(&data, "payload", "", "result of compiling a contract")
+
+	deployTokenCmd := &cobra.Command{
+		Use:   "deploy-token [flags] creator name symbol supply",
+		Short: "deploy a token of the token standard, whose supply in the smallest unit is given to the creator",
+		Args:  cobra.MinimumNArgs(4),
+		Run:   runDeployTokenCmd,
+	}
+	deployTokenCmd.Flags().Uint32Var(&decimals, "decimals", token.DefaultDecimals, "decimals of the token")
+
+	callCmd := &cobra.Command{
+		Use:   "call [flags] sender contract name [args]",
+		Short: "call a contract function",
+		Args:  cobra.MinimumNArgs(3),
+		Run:   runCallCmd,
+	}
+	simulateCmd := &cobra.Command{
+		Use:   "simulate [flags] sender contract name [args]",
+		Short: "run a contract call without sending it, showing its receipt and balance changes",
+		Args:  cobra.MinimumNArgs(3),
+		Run:   runSimulateCmd,
+	}
+	for _, c := range []*cobra.Command{deployCmd, deployTokenCmd, callCmd, simulateCmd} {
+		addGasFlags(c)
+	}
+
 	contractCmd.AddCommand(
 		deployCmd,
+		deployTokenCmd,
+		upgradeCmd,
+		addressCmd,
+		callCmd,
+		simulateCmd,
 		&cobra.Command{
-			Use:   "call [flags] sender contract name [args]",
-			Short: "call a contract function",
-			Args:  cobra.MinimumNArgs(3),
-			Run:   runCallCmd,
+			Use:   "trace [flags] tx_hash",
+			Short: "execute a tx again and show the steps of its contract executions",
+			Args:  cobra.MinimumNArgs(1),
+			Run:   runTraceCmd,
 		},
 		&cobra.Command{
 			Use:   "abi [flags] contract",
@@ -51,6 +103,24 @@ func init() {
 			Args:  cobra.MinimumNArgs(1),
 			Run:   runGetABICmd,
 		},
+		&cobra.Command{
+			Use:   "verify [flags] contract srcfile",
+			Short: "check that the contract is compiled from the lua source, which is recorded with its ABI. A source with imports is given as printed by aergoluac --bundle",
+			Args:  cobra.MinimumNArgs(2),
+			Run:   runVerifySourceCmd,
+		},
+		&cobra.Command{
+			Use:   "info [flags] contract",
+			Short: "get the owner and the versions of the contract",
+			Args:  cobra.MinimumNArgs(1),
+			Run:   runGetContractInfoCmd,
+		},
+		&cobra.Command{
+			Use:   "token-balance [flags] token account",
+			Short: "get the balance of the account in the token contract",
+			Args:  cobra.MinimumNArgs(2),
+			Run:   runGetTokenBalanceCmd,
+		},
 		&cobra.Command{
 			Use:   "query [flags] contract fname [args]",
 			Short: "query contract by executing read-only function",
@@ -61,7 +131,23 @@ func init() {
 	rootCmd.AddCommand(contractCmd)
 }
 
+// addGasFlags adds the flags of the gas of the tx sent by cmd, which runs a
+// contract
+func addGasFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64Var(&gasLimit, "gaslimit", 0, "gas limit of the tx, which a contract tx must have")
+	cmd.Flags().Uint64Var(&price, "price", 0, "gas price of the tx")
+}
+
+// checkGasLimit exits unless the gas limit of the contract tx is given
+func checkGasLimit() {
+	if gasLimit == 0 {
+		fmt.Fprint(os.Stderr, "a contract tx needs --gaslimit")
+		os.Exit(1)
+	}
+}
+
 func runDeployCmd(cmd *cobra.Command, args []string) {
+	checkGasLimit()
 	var err error
 	creator, err := base58.Decode(args[0])
 	if err != nil {
@@ -72,38 +158,45 @@ func runDeployCmd(cmd *cobra.Command, args []string) {
 		log.Fatal(err)
 	}
 	var payload []byte
+	var ctorArgs string
 	if len(data) == 0 {
-		if len(args) != 3 {
-			fmt.Fprint(os.Stderr, "Usage: aergocli contract deploy <creator> <bcfile> <abifile>")
+		if len(args) != 3 && len(args) != 4 {
+			fmt.Fprint(os.Stderr, "Usage: aergocli contract deploy <creator> <bcfile> <abifile> [args]")
 			os.Exit(1)
 		}
-		var code []byte
-		code, err = ioutil.ReadFile(args[1])
-		if err != nil {
-			log.Fatal(err)
-		}
-		var abi []byte
-		abi, err = ioutil.ReadFile(args[2])
-		if err != nil {
-			log.Fatal(err)
+		if len(args) == 4 {
+			ctorArgs = args[3]
 		}
-		payload = make([]byte, 4+len(code)+len(abi))
-
-		binary.LittleEndian.PutUint32(payload[0:], uint32(len(code)))
-		copy(payload[4:], code)
-		copy(payload[4+len(code):], abi)
+		payload = readCode(args[1], args[2])
 	} else {
+		if len(args) > 2 {
+			fmt.Fprint(os.Stderr, "Usage: aergocli contract deploy --payload <payload> <creator> [args]")
+			os.Exit(1)
+		}
+		if len(args) == 2 {
+			ctorArgs = args[1]
+		}
 		payload, err = base58.Decode(data)
 		if err != nil {
 			fmt.Fprint(os.Stderr, err)
 			os.Exit(1)
 		}
 	}
+	if len(ctorArgs) > 0 {
+		// the constructor arguments must be a JSON array
+		var ci types.CallInfo
+		if err = json.Unmarshal([]byte(ctorArgs), &ci.Args); err != nil {
+			log.Fatal(err)
+		}
+	}
+	payload = types.EncodeDeployPayload(payload, []byte(ctorArgs))
 	tx := &types.Tx{
 		Body: &types.TxBody{
 			Nonce:   state.GetNonce() + 1,
 			Account: creator,
 			Payload: payload,
+			Limit:   gasLimit,
+			Price:   price,
 		},
 	}
 
@@ -122,7 +215,262 @@ func runDeployCmd(cmd *cobra.Command, args []string) {
 	}
 }
 
+func runDeployTokenCmd(cmd *cobra.Command, args []string) {
+	checkGasLimit()
+	creator, err := base58.Decode(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	supply, err := types.ParseBignum(args[3])
+	if err != nil {
+		log.Fatal(err)
+	}
+	payload, err := token.DeployPayload(args[1], args[2], decimals, supply)
+	if err != nil {
+		log.Fatal(err)
+	}
+	state, err := client.GetState(context.Background(), &types.SingleBytes{Value: creator})
+	if err != nil {
+		log.Fatal(err)
+	}
+	tx := &types.Tx{
+		Body: &types.TxBody{
+			Nonce:   state.GetNonce() + 1,
+			Account: creator,
+			Payload: payload,
+			Limit:   gasLimit,
+			Price:   price,
+		},
+	}
+
+	sign, err := client.SignTX(context.Background(), tx)
+	if err != nil || sign == nil {
+		log.Fatal(err)
+	}
+	commit, err := client.CommitTX(context.Background(), &types.TxList{Txs: []*types.Tx{sign}})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i, r := range commit.Results {
+		fmt.Println(i+1, ":", util.EncodeB64(r.Hash), r.Error)
+	}
+	// the token is deployed at the address only if the tx is accepted
+	if len(commit.Results) == 1 && commit.Results[0].Error == types.CommitStatus_TX_OK {
+		fmt.Println("token:", base58.Encode(types.ContractAddress(creator, tx.Body.Nonce)))
+	}
+}
+
+// readCode returns the code of a contract made of the compiled bytecode and
+// abi files.
+func readCode(bcFile, abiFile string) []byte {
+	code, err := ioutil.ReadFile(bcFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	abi, err := ioutil.ReadFile(abiFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	payload := make([]byte, 4+len(code)+len(abi))
+
+	binary.LittleEndian.PutUint32(payload[0:], uint32(len(code)))
+	copy(payload[4:], code)
+	copy(payload[4+len(code):], abi)
+	return payload
+}
+
+func runGetContractAddressCmd(cmd *cobra.Command, args []string) {
+	creator, err := base58.Decode(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	in := &types.ContractAddressRequest{Account: creator, Nonce: addressNonce}
+	if len(salt) > 0 {
+		var code []byte
+		if len(data) > 0 {
+			if code, err = base58.Decode(data); err != nil {
+				log.Fatal(err)
+			}
+		} else if len(args) == 3 {
+			code = readCode(args[1], args[2])
+		} else {
+			fmt.Fprint(os.Stderr, "Usage: aergocli contract address --salt <salt> <creator> <bcfile> <abifile>")
+			os.Exit(1)
+		}
+		in.Salt = []byte(salt)
+		in.CodeHash = types.CodeHash(code)
+	}
+	address, err := client.GetContractAddress(context.Background(), in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(base58.Encode(address.Value))
+}
+
+func runUpgradeCmd(cmd *cobra.Command, args []string) {
+	owner, err := base58.Decode(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	contract, err := base58.Decode(args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	state, err := client.GetState(context.Background(), &types.SingleBytes{Value: owner})
+	if err != nil {
+		log.Fatal(err)
+	}
+	var payload []byte
+	if len(data) == 0 {
+		if len(args) != 4 {
+			fmt.Fprint(os.Stderr, "Usage: aergocli contract upgrade <owner> <contract> <bcfile> <abifile>")
+			os.Exit(1)
+		}
+		payload = readCode(args[2], args[3])
+	} else {
+		payload, err = base58.Decode(data)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	tx := &types.Tx{
+		Body: &types.TxBody{
+			Nonce:     state.GetNonce() + 1,
+			Account:   owner,
+			Recipient: contract,
+			Payload:   payload,
+			Type:      types.TxType_UPGRADE,
+		},
+	}
+
+	sign, err := client.SignTX(context.Background(), tx)
+	if err != nil || sign == nil {
+		log.Fatal(err)
+	}
+	commit, err := client.CommitTX(context.Background(), &types.TxList{Txs: []*types.Tx{sign}})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i, r := range commit.Results {
+		fmt.Println(i+1, ":", util.EncodeB64(r.Hash), r.Error)
+	}
+}
+
+func runGetContractInfoCmd(cmd *cobra.Command, args []string) {
+	contract, err := base58.Decode(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	info, err := client.GetContractInfo(context.Background(), &types.SingleBytes{Value: contract})
+	if err != nil {
+		log.Fatal(err)
+	}
+	type version struct {
+		Version  uint32 `json:"version"`
+		CodeHash string `json:"codeHash"`
+		BlockNo  uint64 `json:"blockNo"`
+		TxHash   string `json:"txHash"`
+	}
+	out := struct {
+		Address    string     `json:"address"`
+		Owner      string     `json:"owner"`
+		Upgradable bool       `json:"upgradable"`
+		Destructed bool       `json:"destructed"`
+		Versions   []*version `json:"versions"`
+	}{
+		Address:    base58.Encode(info.Address),
+		Owner:      base58.Encode(info.Owner),
+		Upgradable: info.Upgradable,
+		Destructed: info.Destructed,
+	}
+	for _, v := range info.Versions {
+		out.Versions = append(out.Versions, &version{
+			Version:  v.Version,
+			CodeHash: base58.Encode(v.CodeHash),
+			BlockNo:  v.BlockNo,
+			TxHash:   util.EncodeB64(v.TxHash),
+		})
+	}
+	b, err := json.MarshalIndent(out, "", "\t")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(b))
+}
+
 func runCallCmd(cmd *cobra.Command, args []string) {
+	tx := newCallTx(args)
+
+	sign, err := client.SignTX(context.Background(), tx)
+	if err != nil || sign == nil {
+		log.Fatal(err)
+	}
+	txs := []*types.Tx{sign}
+	commit, err := client.CommitTX(context.Background(), &types.TxList{Txs: txs})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for i, r := range commit.Results {
+		fmt.Println(i+1, ":", util.EncodeB64(r.Hash), r.Error)
+	}
+}
+
+func runSimulateCmd(cmd *cobra.Command, args []string) {
+	result, err := client.SimulateTX(context.Background(), newCallTx(args))
+	if err != nil {
+		log.Fatal(err)
+	}
+	type balanceChange struct {
+		Account string `json:"account"`
+		Before  uint64 `json:"before"`
+		After   uint64 `json:"after"`
+	}
+	out := struct {
+		Receipt        *types.Receipt   `json:"receipt"`
+		Fee            uint64           `json:"fee"`
+		Charged        uint64           `json:"charged"`
+		BalanceChanges []*balanceChange `json:"balanceChanges"`
+	}{
+		Receipt: result.Receipt,
+		Fee:     result.Fee,
+		Charged: result.Charged,
+	}
+	for _, c := range result.BalanceChanges {
+		// the account id is shown if the address is not known
+		account := base58.Encode(c.Address)
+		if len(c.Address) == 0 {
+			account = util.EncodeB64(c.AccountID)
+		}
+		out.BalanceChanges = append(out.BalanceChanges, &balanceChange{
+			Account: account,
+			Before:  c.Before,
+			After:   c.After,
+		})
+	}
+	b, err := json.MarshalIndent(out, "", "\t")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(b))
+}
+
+func runTraceCmd(cmd *cobra.Command, args []string) {
+	txHash, err := util.DecodeB64(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	receipt, err := client.TraceTX(context.Background(), &types.SingleBytes{Value: txHash})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(util.JSON(receipt))
+}
+
+// newCallTx returns an unsigned tx calling a contract function from the
+// arguments of the call command.
+func newCallTx(args []string) *types.Tx {
+	checkGasLimit()
 	caller, err := base58.Decode(args[0])
 	if err != nil {
 		log.Fatal(err)
@@ -147,42 +495,64 @@ func runCallCmd(cmd *cobra.Command, args []string) {
 	if err != nil {
 		log.Fatal(err)
 	}
-	tx := &types.Tx{
+	return &types.Tx{
 		Body: &types.TxBody{
 			Nonce:     state.GetNonce() + 1,
 			Account:   caller,
 			Recipient: contract,
 			Payload:   payload,
+			Limit:     gasLimit,
+			Price:     price,
 		},
 	}
+}
 
-	sign, err := client.SignTX(context.Background(), tx)
-	if err != nil || sign == nil {
+func runGetABICmd(cmd *cobra.Command, args []string) {
+	contract, err := base58.Decode(args[0])
+	if err != nil {
 		log.Fatal(err)
 	}
-	txs := []*types.Tx{sign}
-	commit, err := client.CommitTX(context.Background(), &types.TxList{Txs: txs})
+	abi, err := client.GetABI(context.Background(), &types.SingleBytes{Value: contract})
 	if err != nil {
 		log.Fatal(err)
 	}
-
-	for i, r := range commit.Results {
-		fmt.Println(i+1, ":", util.EncodeB64(r.Hash), r.Error)
-	}
+	fmt.Println(util.JSON(abi))
 }
 
-func runGetABICmd(cmd *cobra.Command, args []string) {
+func runVerifySourceCmd(cmd *cobra.Command, args []string) {
 	contract, err := base58.Decode(args[0])
 	if err != nil {
 		log.Fatal(err)
 	}
-	abi, err := client.GetABI(context.Background(), &types.SingleBytes{Value: contract})
+	source, err := ioutil.ReadFile(args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	abi, err := client.VerifyContractSource(context.Background(),
+		&types.SourceVerification{ContractAddress: contract, Source: string(source)})
 	if err != nil {
 		log.Fatal(err)
 	}
 	fmt.Println(util.JSON(abi))
 }
 
+func runGetTokenBalanceCmd(cmd *cobra.Command, args []string) {
+	tokenAddress, err := base58.Decode(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	account, err := base58.Decode(args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	balance, err := client.GetTokenBalance(context.Background(),
+		&types.TokenBalanceRequest{Token: tokenAddress, Account: account})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(balance.Balance)
+}
+
 func runQueryCmd(cmd *cobra.Command, args []string) {
 	contract, err := base58.Decode(args[0])
 	if err != nil {
@@ -25,12 +25,21 @@ var sendtxCmd = &cobra.Command{
 }
 
 var amount uint64
+var limit uint64
+var feePayer string
+var validAfter uint64
+var validUntil uint64
 
 func init() {
 	rootCmd.AddCommand(sendtxCmd)
 	sendtxCmd.Flags().StringVar(&from, "from", "", "")
 	sendtxCmd.Flags().StringVar(&to, "to", "", "")
 	sendtxCmd.Flags().Uint64Var(&amount, "amount", 0, "")
+	sendtxCmd.Flags().Uint64Var(&limit, "limit", 0, "")
+	sendtxCmd.Flags().Uint64Var(&price, "price", 0, "")
+	sendtxCmd.Flags().StringVar(&feePayer, "feepayer", "", "base58 address of account paying the fee instead of sender")
+	sendtxCmd.Flags().Uint64Var(&validAfter, "validafter", 0, "block number after which the transaction can be included")
+	sendtxCmd.Flags().Uint64Var(&validUntil, "validuntil", 0, "last block number in which the transaction can be included")
 }
 
 func execSendTX(cmd *cobra.Command, args []string) {
@@ -42,7 +51,15 @@ func execSendTX(cmd *cobra.Command, args []string) {
 	if err != nil {
 		fmt.Printf("Failed: %s\n", err.Error())
 	}
-	tx := &types.Tx{Body: &types.TxBody{Account: account, Recipient: recipient, Amount: amount}}
+	tx := &types.Tx{Body: &types.TxBody{Account: account, Recipient: recipient, Amount: amount, Limit: limit, Price: price,
+		ValidAfter: validAfter, ValidUntil: validUntil}}
+	if feePayer != "" {
+		tx.Body.FeePayer, err = base58.Decode(feePayer)
+		if err != nil {
+			fmt.Printf("Failed: %s\n", err.Error())
+			return
+		}
+	}
 	msg, err := client.SendTX(context.Background(), tx)
 	if err != nil {
 		fmt.Printf("Failed: %s\n", err.Error())
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 
 	"github.com/mr-tron/base58/base58"
@@ -17,6 +18,7 @@ import (
 func init() {
 	rootCmd.AddCommand(signCmd)
 	signCmd.Flags().StringVar(&jsonTx, "jsontx", "", "transaction json to sign")
+	signCmd.Flags().StringVar(&jsonPath, "jsontxpath", "", "transaction json file to sign. signed result is written back to the file, so members of multi-signature account can add their signs in turn")
 	signCmd.Flags().StringVar(&dataDir, "path", "$HOME/.aergo/data/cli", "path to data directory")
 	signCmd.Flags().StringVar(&address, "address", "1", "address of account to use for signing")
 	signCmd.Flags().BoolVar(&remote, "remote", true, "choose account in the remote node or not")
@@ -32,15 +34,24 @@ var signCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 
 		var err error
+		if jsonPath != "" {
+			b, readerr := ioutil.ReadFile(jsonPath)
+			if readerr != nil {
+				fmt.Printf("Failed: %s\n", readerr.Error())
+				return
+			}
+			jsonTx = string(b)
+		}
 		if jsonTx == "" {
 			fmt.Printf("need to transaction json input")
 			return
 		}
-		param, err := util.ParseBase58TxBody([]byte(jsonTx))
+		unsigned, err := util.ParseBase58SingleTx([]byte(jsonTx))
 		if err != nil {
 			fmt.Printf("Failed: %s\n", err.Error())
 			return
 		}
+		param := unsigned.Body
 		var msg *types.Tx
 		if remote {
 
@@ -61,8 +72,6 @@ var signCmd = &cobra.Command{
 			if tx.Body.Sign != nil {
 				tx.Body.Sign = nil
 			}
-			hash := key.CalculateHashWithoutSign(param)
-
 			dataEnvPath := os.ExpandEnv(dataDir)
 			ks := key.NewStore(dataEnvPath)
 			addr, err := base58.Decode(address)
@@ -70,17 +79,34 @@ var signCmd = &cobra.Command{
 				fmt.Printf("Failed: %s\n", err.Error())
 				return
 			}
-			tx.Body.Sign, err = ks.Sign(addr, pw, hash)
-			if err != nil {
-				fmt.Printf("Failed: %s\n", err.Error())
-				return
+			if tx.Body.MultiSig != nil {
+				// rejects the sign of a non-member or of a member who signed already
+				if err = ks.SignMultiSig(addr, pw, tx); err != nil {
+					fmt.Printf("Failed: %s\n", err.Error())
+					return
+				}
+			} else {
+				hash := key.CalculateHashWithoutSign(param)
+				sign, err := ks.Sign(addr, pw, hash)
+				if err != nil {
+					fmt.Printf("Failed: %s\n", err.Error())
+					return
+				}
+				tx.Body.Sign = sign
+				tx.Hash = tx.CalculateTxHash()
 			}
-			tx.Hash = tx.CalculateTxHash()
 			msg = tx
 		}
 
 		if nil == err && msg != nil {
-			fmt.Println(util.ConvBase58Addr(msg))
+			out := util.ConvBase58Addr(msg)
+			if jsonPath != "" {
+				if err = ioutil.WriteFile(jsonPath, []byte(out), 0644); err != nil {
+					fmt.Printf("Failed: %s\n", err.Error())
+					return
+				}
+			}
+			fmt.Println(out)
 		} else {
 			fmt.Printf("Failed: %s\n", err.Error())
 		}
@@ -13,15 +13,68 @@ type InOutTx struct {
 	Body *InOutTxBody
 }
 type InOutTxBody struct {
-	Nonce     uint64
-	Account   string
-	Recipient string
-	Amount    uint64
-	Payload   string
-	Limit     uint64
-	Price     uint64
-	Sign      string
-	Type      types.TxType
+	Nonce        uint64
+	Account      string
+	Recipient    string
+	Amount       uint64
+	Payload      string
+	Limit        uint64
+	Price        uint64
+	Sign         string
+	Type         types.TxType
+	MultiSig     *InOutMultiSig `json:",omitempty"`
+	FeePayer     string         `json:",omitempty"`
+	FeePayerSign string         `json:",omitempty"`
+	ValidAfter   uint64         `json:",omitempty"`
+	ValidUntil   uint64         `json:",omitempty"`
+}
+type InOutMultiSig struct {
+	Threshold uint32
+	PubKeys   []string
+	Signs     []string
+}
+
+func parseBase58MultiSig(in *InOutMultiSig) (*types.MultiSig, error) {
+	if in == nil {
+		return nil, nil
+	}
+	var err error
+	ms := &types.MultiSig{
+		Threshold: in.Threshold,
+		PubKeys:   make([][]byte, len(in.PubKeys)),
+		Signs:     make([][]byte, len(in.Signs)),
+	}
+	for i, pubKey := range in.PubKeys {
+		ms.PubKeys[i], err = base58.Decode(pubKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i, sign := range in.Signs {
+		ms.Signs[i], err = base58.Decode(sign)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ms, nil
+}
+
+func convBase58MultiSig(ms *types.MultiSig) *InOutMultiSig {
+	if ms == nil {
+		return nil
+	}
+	out := &InOutMultiSig{
+		Threshold: ms.Threshold,
+		PubKeys:   make([]string, len(ms.PubKeys)),
+		Signs:     make([]string, len(ms.Signs)),
+	}
+	for i, pubKey := range ms.PubKeys {
+		out.PubKeys[i] = base58.Encode(pubKey)
+	}
+	for i, sign := range ms.Signs {
+		out.Signs[i] = base58.Encode(sign)
+	}
+	return out
 }
 
 func ParseBase58Tx(jsonTx []byte) ([]*types.Tx, error) {
@@ -70,6 +123,24 @@ func ParseBase58Tx(jsonTx []byte) ([]*types.Tx, error) {
 			}
 		}
 		tx.Body.Type = in.Body.Type
+		tx.Body.ValidAfter = in.Body.ValidAfter
+		tx.Body.ValidUntil = in.Body.ValidUntil
+		tx.Body.MultiSig, err = parseBase58MultiSig(in.Body.MultiSig)
+		if err != nil {
+			return nil, err
+		}
+		if in.Body.FeePayer != "" {
+			tx.Body.FeePayer, err = base58.Decode(in.Body.FeePayer)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if in.Body.FeePayerSign != "" {
+			tx.Body.FeePayerSign, err = base58.Decode(in.Body.FeePayerSign)
+			if err != nil {
+				return nil, err
+			}
+		}
 		txs[i] = tx
 	}
 
@@ -114,10 +185,54 @@ func ParseBase58TxBody(jsonTx []byte) (*types.TxBody, error) {
 		}
 	}
 	body.Type = in.Type
+	body.ValidAfter = in.ValidAfter
+	body.ValidUntil = in.ValidUntil
+	body.MultiSig, err = parseBase58MultiSig(in.MultiSig)
+	if err != nil {
+		return nil, err
+	}
+	if in.FeePayer != "" {
+		body.FeePayer, err = base58.Decode(in.FeePayer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if in.FeePayerSign != "" {
+		body.FeePayerSign, err = base58.Decode(in.FeePayerSign)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return body, nil
 }
 
+// ParseBase58SingleTx parses a transaction in the form printed by ConvBase58Addr.
+// A bare transaction body is accepted as well.
+func ParseBase58SingleTx(jsonTx []byte) (*types.Tx, error) {
+	in := &InOutTx{}
+	err := json.Unmarshal(jsonTx, in)
+	if err != nil {
+		return nil, err
+	}
+	if in.Body == nil {
+		body, err := ParseBase58TxBody(jsonTx)
+		if err != nil {
+			return nil, err
+		}
+		return &types.Tx{Body: body}, nil
+	}
+	jsonBody, err := json.Marshal(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ParseBase58TxBody(jsonBody)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Tx{Body: body}, nil
+}
+
 func ConvBase58Addr(tx *types.Tx) string {
 	out := &InOutTx{Body: &InOutTxBody{}}
 	out.Hash = base58.Encode(tx.Hash)
@@ -130,6 +245,11 @@ func ConvBase58Addr(tx *types.Tx) string {
 	out.Body.Price = tx.Body.Price
 	out.Body.Sign = base58.Encode(tx.Body.Sign)
 	out.Body.Type = tx.Body.Type
+	out.Body.ValidAfter = tx.Body.ValidAfter
+	out.Body.ValidUntil = tx.Body.ValidUntil
+	out.Body.MultiSig = convBase58MultiSig(tx.Body.MultiSig)
+	out.Body.FeePayer = base58.Encode(tx.Body.FeePayer)
+	out.Body.FeePayerSign = base58.Encode(tx.Body.FeePayerSign)
 	jsonout, err := json.MarshalIndent(out, "", " ")
 	if err != nil {
 		return ""
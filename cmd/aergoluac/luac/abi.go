@@ -0,0 +1,122 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package luac
+
+import "C"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aergoio/aergo/types"
+)
+
+var errLibraryStateVar = errors.New("a library cannot declare state variables")
+
+// abiDecl is the declaration of a function made with abi.view, abi.payable
+// and abi.types in a contract
+type abiDecl struct {
+	View    bool     `json:"view"`
+	Payable bool     `json:"payable"`
+	Args    []string `json:"args"`
+	Rets    []string `json:"rets"`
+}
+
+// abiDecls is the declarations made in a contract
+type abiDecls struct {
+	Upgradable     bool                `json:"upgradable"`
+	Library        bool                `json:"library"`
+	Functions      map[string]*abiDecl `json:"functions"`
+	StateVariables []*types.StateVar   `json:"stateVariables"`
+}
+
+// mergeABI adds the declarations to the abi generated by abi.generate. The abi
+// is left untyped if the contract declares no function, but it has the state
+// variables of the contract. Every abi records the compiler. A library cannot
+// declare state variables, because it runs on the storage of its callers.
+func mergeABI(abiJSON, declJSON string) (string, error) {
+	var all abiDecls
+	if err := json.Unmarshal([]byte(declJSON), &all); err != nil {
+		return "", err
+	}
+	abi := new(types.ABI)
+	if err := json.Unmarshal([]byte(abiJSON), abi); err != nil {
+		return "", err
+	}
+	abi.Compiler = Compiler()
+	if len(all.Functions) == 0 && !all.Upgradable && !all.Library && len(all.StateVariables) == 0 {
+		return marshalABI(abi)
+	}
+	if all.Library && len(all.StateVariables) > 0 {
+		return "", errLibraryStateVar
+	}
+	abi.Upgradable = all.Upgradable
+	abi.Library = all.Library
+	for _, sv := range all.StateVariables {
+		if !types.IsStateVarType(sv.Type) {
+			return "", fmt.Errorf("unknown type %s of state variable %s", sv.Type, sv.Name)
+		}
+	}
+	abi.StateVariables = all.StateVariables
+	decls := all.Functions
+	if len(decls) == 0 {
+		return marshalABI(abi)
+	}
+	for name := range decls {
+		if abi.GetFunction(name) == nil {
+			return "", fmt.Errorf("%s is declared but not registered to the abi", name)
+		}
+	}
+	for _, fn := range abi.Functions {
+		decl, ok := decls[fn.Name]
+		if !ok {
+			continue
+		}
+		if decl.View && decl.Payable {
+			return "", fmt.Errorf("%s cannot be both view and payable", fn.Name)
+		}
+		fn.View = decl.View
+		fn.Payable = decl.Payable
+		if decl.Args != nil {
+			if len(decl.Args) != len(fn.Arguments) {
+				return "", fmt.Errorf("%s has %d arguments, but %d types are declared", fn.Name,
+					len(fn.Arguments), len(decl.Args))
+			}
+			for i, typ := range decl.Args {
+				if !types.IsABIType(typ) {
+					return "", fmt.Errorf("unknown type %s of %s", typ, fn.Name)
+				}
+				fn.Arguments[i].Type = typ
+			}
+		}
+		for _, typ := range decl.Rets {
+			if !types.IsABIType(typ) {
+				return "", fmt.Errorf("unknown type %s of %s", typ, fn.Name)
+			}
+		}
+		fn.Returns = decl.Rets
+	}
+	abi.Version = types.TypedABIVersion
+	return marshalABI(abi)
+}
+
+func marshalABI(abi *types.ABI) (string, error) {
+	out, err := json.Marshal(abi)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+//export typedABI
+func typedABI(abi *C.char, decls *C.char, errMsg **C.char) *C.char {
+	typed, err := mergeABI(C.GoString(abi), C.GoString(decls))
+	if err != nil {
+		*errMsg = C.CString(err.Error())
+		return nil
+	}
+	return C.CString(typed)
+}
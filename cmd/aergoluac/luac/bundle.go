@@ -0,0 +1,207 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package luac
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// importRe matches an import at the beginning of a line, which is either a
+// statement or the value of a local variable:
+//
+//	import "safemath.lua"
+//	local ownable = import "lib/ownable.lua"
+var importRe = regexp.MustCompile(`(?m)^([ \t]*(?:local[ \t]+[A-Za-z_][A-Za-z0-9_]*[ \t]*=[ \t]*)?)import[ \t]*\(?[ \t]*["']([^"'\n]+)["'][ \t]*\)?`)
+
+// importsName is the local table of the modules in a bundle
+const importsName = "__imports"
+
+type bundler struct {
+	root    string
+	modules map[string]int
+	loading []string
+	out     bytes.Buffer
+}
+
+// Bundle resolves the imports of src into a single source. The paths of the
+// imports are relative to the importing file, and the imports of src are
+// relative to dir. Every module is run once, before the modules and the source
+// importing it, and an import is replaced by the value the module returns.
+//
+// The bundle does not depend on where the files are, so it is compiled to the
+// same code everywhere. A source without imports is left as it is.
+func Bundle(src []byte, dir string) ([]byte, error) {
+	if len(findImports(src)) == 0 {
+		return src, nil
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	b := &bundler{root: root, modules: make(map[string]int)}
+	b.out.WriteString("local " + importsName + " = {}\n")
+	main, err := b.resolve(src, root)
+	if err != nil {
+		return nil, err
+	}
+	b.out.Write(main)
+	return b.out.Bytes(), nil
+}
+
+// BundleFile reads the source file and resolves its imports.
+func BundleFile(fileName string) ([]byte, error) {
+	src, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return Bundle(src, filepath.Dir(fileName))
+}
+
+// resolve adds the modules imported by src to the bundle and returns src
+// with its imports replaced
+func (b *bundler) resolve(src []byte, dir string) ([]byte, error) {
+	var resolved bytes.Buffer
+	last := 0
+	for _, m := range findImports(src) {
+		prefix, path := string(src[m[2]:m[3]]), string(src[m[4]:m[5]])
+		n, err := b.load(filepath.Join(dir, filepath.FromSlash(path)))
+		if err != nil {
+			return nil, err
+		}
+		resolved.Write(src[last:m[0]])
+		resolved.WriteString(prefix)
+		if strings.Contains(prefix, "=") {
+			fmt.Fprintf(&resolved, "%s[%d]", importsName, n)
+		}
+		// else the module is already run by the bundle
+		last = m[1]
+	}
+	resolved.Write(src[last:])
+	return resolved.Bytes(), nil
+}
+
+// findImports returns the submatch indexes of the imports in src, leaving
+// out the ones in the long strings and in the comments.
+func findImports(src []byte) [][]int {
+	var imports [][]int
+	spans := skippedSpans(src)
+	for _, m := range importRe.FindAllSubmatchIndex(src, -1) {
+		for len(spans) > 0 && spans[0][1] <= m[0] {
+			spans = spans[1:]
+		}
+		if len(spans) > 0 && spans[0][0] <= m[0] {
+			continue
+		}
+		imports = append(imports, m)
+	}
+	return imports
+}
+
+// skippedSpans returns the spans of the long strings and of the comments of
+// src, in order.
+func skippedSpans(src []byte) [][2]int {
+	var spans [][2]int
+	for i := 0; i < len(src); {
+		start := i
+		switch {
+		case src[i] == '"' || src[i] == '\'':
+			i = skipString(src, i)
+		case src[i] == '-' && i+1 < len(src) && src[i+1] == '-':
+			if level, ok := longBracket(src, i+2); ok {
+				i = skipLong(src, i+2, level)
+			} else if end := bytes.IndexByte(src[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(src)
+			}
+			spans = append(spans, [2]int{start, i})
+		case src[i] == '[':
+			if level, ok := longBracket(src, i); ok {
+				i = skipLong(src, i, level)
+				spans = append(spans, [2]int{start, i})
+			} else {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+	return spans
+}
+
+// skipString returns the end of the short string starting at i
+func skipString(src []byte, i int) int {
+	quote := src[i]
+	for i++; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case quote, '\n':
+			return i + 1
+		}
+	}
+	return len(src)
+}
+
+// longBracket returns the level of the opening long bracket at i
+func longBracket(src []byte, i int) (int, bool) {
+	if i >= len(src) || src[i] != '[' {
+		return 0, false
+	}
+	level := 0
+	for i++; i < len(src) && src[i] == '='; i++ {
+		level++
+	}
+	return level, i < len(src) && src[i] == '['
+}
+
+// skipLong returns the end of the long string or comment starting at i
+func skipLong(src []byte, i, level int) int {
+	closing := "]" + strings.Repeat("=", level) + "]"
+	if end := bytes.Index(src[i+level+2:], []byte(closing)); end >= 0 {
+		return i + level + 2 + end + len(closing)
+	}
+	return len(src)
+}
+
+// load adds the module of the file to the bundle, unless it is added before,
+// and returns its index
+func (b *bundler) load(fileName string) (int, error) {
+	rel, err := filepath.Rel(b.root, fileName)
+	if err != nil {
+		return 0, err
+	}
+	name := filepath.ToSlash(rel)
+	if n, ok := b.modules[name]; ok {
+		return n, nil
+	}
+	for _, loading := range b.loading {
+		if loading == name {
+			return 0, fmt.Errorf("import cycle: %s -> %s", strings.Join(b.loading, " -> "), name)
+		}
+	}
+	src, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return 0, fmt.Errorf("cannot import %s: %s", name, err.Error())
+	}
+	b.loading = append(b.loading, name)
+	module, err := b.resolve(src, filepath.Dir(fileName))
+	b.loading = b.loading[:len(b.loading)-1]
+	if err != nil {
+		return 0, err
+	}
+	n := len(b.modules) + 1
+	b.modules[name] = n
+	fmt.Fprintf(&b.out, "-- %s\n%s[%d] = (function()\n", name, importsName, n)
+	b.out.Write(module)
+	b.out.WriteString("\nend)()\n")
+	return n, nil
+}
@@ -0,0 +1,110 @@
+package luac
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSources(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, src := range files {
+		fileName := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(fileName, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestBundle(t *testing.T) {
+	dir := writeSources(t, map[string]string{
+		"main.lua": `local safemath = import "lib/safemath.lua"
+import "lib/ownable.lua"
+function add(a, b) return safemath.add(a, b) end
+abi.register(add)`,
+		"lib/safemath.lua": `local M = {}
+function M.add(a, b) local c = a + b; assert(c >= a); return c end
+return M`,
+		"lib/ownable.lua": `local safemath = import("safemath.lua")
+function owner() return system.getItem("owner") end
+abi.register(owner)`,
+	})
+	defer os.RemoveAll(dir)
+
+	bundle, err := BundleFile(filepath.Join(dir, "main.lua"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(bundle)
+	if strings.Contains(src, "import \"") || strings.Count(src, "-- lib/safemath.lua") != 1 {
+		t.Errorf("unexpected bundle:\n%s", src)
+	}
+	if !strings.Contains(src, "local safemath = __imports[1]") {
+		t.Errorf("the import is not replaced:\n%s", src)
+	}
+	if _, _, err := Compile(bundle); err != nil {
+		t.Error(err)
+	}
+
+	// the bundle does not depend on the directory
+	other := writeSources(t, map[string]string{
+		"main.lua":         "local safemath = import 'lib/safemath.lua'",
+		"lib/safemath.lua": "return {}",
+	})
+	defer os.RemoveAll(other)
+	b1, err := BundleFile(filepath.Join(other, "main.lua"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b2, err := Bundle([]byte("local safemath = import 'lib/safemath.lua'"), other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b1) != string(b2) {
+		t.Errorf("different bundles:\n%s\n%s", b1, b2)
+	}
+
+	plain := []byte("function f() end")
+	if b, _ := Bundle(plain, dir); string(b) != string(plain) {
+		t.Error("a source without imports is changed")
+	}
+
+	// the imports in the long strings and comments are not resolved
+	quoted := []byte("--[[\nimport \"none.lua\"\n]]\nlocal s = [==[\nimport \"none.lua\"\n]] ]==]\n--[=[\n]]\nimport 'none.lua'\n]=]")
+	if b, err := Bundle(quoted, dir); err != nil || string(b) != string(quoted) {
+		t.Errorf("a quoted import is resolved: %v\n%s", err, b)
+	}
+	mixed := []byte("local s = [[\nimport \"none.lua\"\n]]\nimport \"main.lua\"")
+	b, err := Bundle(mixed, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "[[\nimport \"none.lua\"\n]]") || strings.Count(string(b), "import \"") != 1 {
+		t.Errorf("unexpected bundle:\n%s", b)
+	}
+}
+
+func TestBundleErrors(t *testing.T) {
+	dir := writeSources(t, map[string]string{
+		"a.lua": `import "b.lua"`,
+		"b.lua": `import "a.lua"`,
+		"c.lua": `import "none.lua"`,
+	})
+	defer os.RemoveAll(dir)
+
+	if _, err := BundleFile(filepath.Join(dir, "a.lua")); err == nil || !strings.Contains(err.Error(), "import cycle") {
+		t.Errorf("expected an import cycle, got %v", err)
+	}
+	if _, err := BundleFile(filepath.Join(dir, "c.lua")); err == nil {
+		t.Error("imported a missing file")
+	}
+}
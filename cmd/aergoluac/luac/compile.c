@@ -0,0 +1,288 @@
+#include <stdlib.h>
+#include <string.h>
+#include <lualib.h>
+#include <lauxlib.h>
+#include <luajit.h>
+#include "compile.h"
+#include "_cgo_export.h"
+
+/* the chunk name of every contract. The bytecode has the chunk name, so a
+   source must not be named after its file to be compiled to the same
+   bytecode everywhere */
+#define CHUNK_NAME "=contract"
+
+/* the instructions allowed to the top level code of a contract, which is run
+   to generate the abi. The nodes compile the sources submitted for
+   verification, so the top level code must end */
+#define MAX_TOPLEVEL_INSTRUCTIONS 10000000
+
+/* the globals removed from the compiler, as the top level code of a contract
+   cannot use them in the contract vm either */
+static const char *unsafe_globals[] = {
+	"os", "io", "debug", "package", "require", "module", "dofile", "loadfile",
+	"load", "loadstring", "jit", "collectgarbage", "getfenv", "setfenv",
+	"coroutine", "newproxy", NULL
+};
+
+/* the memory of the compiler is limited like the memory of a contract
+   execution while the source is loaded and its top level code runs. A memory
+   error must not be raised out of a protected call, so the other allocations
+   are not limited */
+typedef struct {
+	lua_Alloc alloc;
+	void *ud;
+	long long used;
+	long long limit;
+	int running;
+	int exceeded;
+} luac_memory_t;
+
+static void *limited_alloc(void *ud, void *ptr, size_t osize, size_t nsize)
+{
+	luac_memory_t *m = (luac_memory_t *)ud;
+
+	if (nsize > osize && m->running && m->used + (long long)(nsize - osize) > m->limit) {
+		m->exceeded = 1;
+		return NULL;
+	}
+	ptr = m->alloc(m->ud, ptr, osize, nsize);
+	if (ptr != NULL || nsize == 0)
+		m->used += (long long)nsize - (long long)osize;
+	return ptr;
+}
+
+static luac_memory_t *get_memory(lua_State *L)
+{
+	void *ud;
+
+	if (lua_getallocf(L, &ud) != limited_alloc)
+		return NULL;
+	return (luac_memory_t *)ud;
+}
+
+static void set_running(lua_State *L, int running)
+{
+	luac_memory_t *m = get_memory(L);
+	if (m != NULL)
+		m->running = running;
+}
+
+/* abi.view, abi.payable, abi.types, abi.upgradable and abi.library record
+   the declarations of a typed abi, which abi.typed returns in JSON with the
+   state variables declared by state.var. They do nothing in the contract vm,
+   and state.var only records the names and the types of the variables here.
+   The functions of bignum return their argument, so that the top level code
+   can make the constants. contract.library returns a library whose functions
+   do nothing, as the libraries are not on chain here. */
+static const char *abi_declarations =
+	"local decls, statevars = {}, {}\n"
+	"local upgradable, library = false, false\n"
+	"local function get(f)\n"
+	"  local d = decls[f]\n"
+	"  if d == nil then d = {}; decls[f] = d end\n"
+	"  return d\n"
+	"end\n"
+	"local function flag(name)\n"
+	"  return function(...) for _, f in ipairs({...}) do get(f)[name] = true end end\n"
+	"end\n"
+	"abi.view = flag('view')\n"
+	"abi.payable = flag('payable')\n"
+	"function abi.upgradable() upgradable = true end\n"
+	"function abi.library() library = true end\n"
+	"contract = contract or {}\n"
+	"function contract.library()\n"
+	"  return setmetatable({}, {__index = function() return function() end end})\n"
+	"end\n"
+	"bignum = setmetatable({}, {__index = function() return function(v) return v end end})\n"
+	"state = {}\n"
+	"local function descriptor(kind) return function() return {kind = kind} end end\n"
+	"state.value, state.map, state.array = descriptor('value'), descriptor('map'), descriptor('array')\n"
+	"function state.var(vars)\n"
+	"  for name, d in pairs(vars) do\n"
+	"    if type(d) ~= 'table' or d.kind == nil then\n"
+	"      error('invalid type of state variable ' .. tostring(name), 2)\n"
+	"    end\n"
+	"    statevars[name] = d.kind\n"
+	"    _G[name] = d\n"
+	"  end\n"
+	"end\n"
+	"function abi.types(f, args, rets)\n"
+	"  local d = get(f)\n"
+	"  d.args, d.rets = args or {}, rets or {}\n"
+	"end\n"
+	"local function list(t)\n"
+	"  if t == nil then return 'null' end\n"
+	"  local s = {}\n"
+	"  for i, v in ipairs(t) do s[i] = string.format('%q', tostring(v)) end\n"
+	"  return '[' .. table.concat(s, ',') .. ']'\n"
+	"end\n"
+	"function abi.typed()\n"
+	"  local out = {}\n"
+	"  for name, f in pairs(_G) do\n"
+	"    local d = decls[f]\n"
+	"    if d ~= nil and type(name) == 'string' then\n"
+	"      out[#out + 1] = string.format('%q:{\"view\":%s,\"payable\":%s,\"args\":%s,\"rets\":%s}',\n"
+	"        name, tostring(d.view == true), tostring(d.payable == true), list(d.args), list(d.rets))\n"
+	"    end\n"
+	"  end\n"
+	"  local names = {}\n"
+	"  for name in pairs(statevars) do names[#names + 1] = name end\n"
+	"  table.sort(names)\n"
+	"  for i, name in ipairs(names) do\n"
+	"    names[i] = string.format('{\"name\":%q,\"type\":%q}', name, statevars[name])\n"
+	"  end\n"
+	"  return string.format('{\"upgradable\":%s,\"library\":%s,\"functions\":{%s},\"stateVariables\":[%s]}',\n"
+	"    tostring(upgradable), tostring(library), table.concat(out, ','), table.concat(names, ','))\n"
+	"end\n";
+
+lua_State *luac_vm_newstate(size_t memory_limit)
+{
+	int i;
+	luac_memory_t *m;
+	lua_State *L = luaL_newstate();
+
+	if (L == NULL)
+		return NULL;
+	m = (luac_memory_t *)calloc(1, sizeof(luac_memory_t));
+	if (m == NULL) {
+		lua_close(L);
+		return NULL;
+	}
+	m->alloc = lua_getallocf(L, &m->ud);
+	m->used = (long long)lua_gc(L, LUA_GCCOUNT, 0) * 1024 + lua_gc(L, LUA_GCCOUNTB, 0);
+	m->limit = (long long)memory_limit;
+	lua_setallocf(L, limited_alloc, m);
+	luaL_openlibs(L);
+	luaL_dostring(L, abi_declarations);
+	for (i = 0; unsafe_globals[i] != NULL; i++) {
+		lua_pushnil(L);
+		lua_setglobal(L, unsafe_globals[i]);
+	}
+	/* the count hook is not called in the compiled traces */
+	luaJIT_setmode(L, 0, LUAJIT_MODE_ENGINE|LUAJIT_MODE_OFF);
+	return L;
+}
+
+void luac_vm_close(lua_State *L)
+{
+	luac_memory_t *m;
+
+	if (L == NULL)
+		return;
+	m = get_memory(L);
+	lua_close(L);
+	free(m);
+}
+
+const char *luac_version()
+{
+	return LUAJIT_VERSION;
+}
+
+static const char *error_message(lua_State *L)
+{
+	const char *msg = lua_tostring(L, -1);
+	luac_memory_t *m = get_memory(L);
+
+	if (m != NULL && m->exceeded)
+		return strdup("memory limit exceeded");
+	return strdup(msg != NULL ? msg : "unknown error");
+}
+
+const char *luac_vm_loadbuffer(lua_State *L, const char *src, size_t len)
+{
+	int err;
+
+	set_running(L, 1);
+	err = luaL_loadbuffer(L, src, len, CHUNK_NAME);
+	set_running(L, 0);
+	if (err != 0)
+		return error_message(L);
+	return NULL;
+}
+
+static int writer_buf(lua_State *L, const void *p, size_t size, void *b)
+{
+	luaL_addlstring((luaL_Buffer *)b, (const char *)p, size);
+	return 0;
+}
+
+static void toplevel_hook(lua_State *L, lua_Debug *ar)
+{
+	luaL_error(L, "the top level code runs too long");
+}
+
+/* generate_abi returns the abi of the contract loaded in L, which the caller
+   frees. The contract must be run before. */
+static const char *generate_abi(lua_State *L, char **abi)
+{
+	const char *ext;
+	char *errMsg = NULL;
+
+	int err;
+
+	lua_getfield(L, LUA_GLOBALSINDEX, "abi");
+	lua_getfield(L, -1, "generate");
+	set_running(L, 1);
+	err = lua_pcall(L, 0, 1, 0);
+	set_running(L, 0);
+	if (err != 0) {
+		return error_message(L);
+	}
+	if (!lua_isstring(L, -1)) {
+		return strdup("abi generation is failed");
+	}
+	lua_getfield(L, -2, "typed");
+	set_running(L, 1);
+	err = lua_pcall(L, 0, 1, 0);
+	set_running(L, 0);
+	if (err != 0) {
+		return error_message(L);
+	}
+	ext = lua_tostring(L, -1);
+	*abi = typedABI((char *)lua_tostring(L, -2), (char *)ext, &errMsg);
+	lua_pop(L, 3);
+	return errMsg;
+}
+
+/* luac_vm_dump dumps the bytecode of the contract loaded by
+   luac_vm_loadbuffer. If abi is not NULL, the contract is run to generate the
+   abi. The results are freed by the caller */
+const char *luac_vm_dump(lua_State *L, char **code, size_t *len, char **abi)
+{
+	int err;
+	const char *errMsg;
+	luaL_Buffer b;
+
+	luaL_buffinit(L, &b);
+	if (lua_dump(L, writer_buf, &b) != 0) {
+		return error_message(L);
+	}
+	luaL_pushresult(&b);
+	if (!lua_isstring(L, -1)) {
+		return strdup("compile failed");
+	}
+	*len = lua_strlen(L, -1);
+	*code = malloc(*len);
+	memcpy(*code, lua_tostring(L, -1), *len);
+	lua_pop(L, 1);
+	if (abi == NULL) {
+		return NULL;
+	}
+
+	lua_sethook(L, toplevel_hook, LUA_MASKCOUNT, MAX_TOPLEVEL_INSTRUCTIONS);
+	set_running(L, 1);
+	err = lua_pcall(L, 0, 0, 0);
+	set_running(L, 0);
+	if (err != 0) {
+		errMsg = error_message(L);
+	} else {
+		errMsg = generate_abi(L, abi);
+	}
+	lua_sethook(L, NULL, 0, 0);
+	if (errMsg != NULL) {
+		free(*code);
+		*code = NULL;
+	}
+	return errMsg;
+}
@@ -0,0 +1,14 @@
+#ifndef _COMPILE_H
+#define _COMPILE_H
+
+#include <stddef.h>
+
+typedef struct lua_State lua_State;
+
+lua_State *luac_vm_newstate(size_t memory_limit);
+void luac_vm_close(lua_State *L);
+const char *luac_version();
+const char *luac_vm_loadbuffer(lua_State *L, const char *src, size_t len);
+const char *luac_vm_dump(lua_State *L, char **code, size_t *len, char **abi);
+
+#endif /* _COMPILE_H */
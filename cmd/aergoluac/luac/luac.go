@@ -0,0 +1,107 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+// Package luac compiles the Lua contracts for the contract vm. A source is
+// compiled to the same bytecode and ABI by the same compiler wherever it is
+// compiled, so that the code of a deployed contract can be checked against
+// its source.
+package luac
+
+/*
+#cgo CFLAGS: -I${SRCDIR}/../../../libtool/include/luajit-2.0
+#cgo LDFLAGS: ${SRCDIR}/../../../libtool/lib/libluajit-5.1.a -lm
+
+#include <stdlib.h>
+#include "compile.h"
+*/
+import "C"
+import (
+	"encoding/binary"
+	"errors"
+	"unsafe"
+
+	"github.com/aergoio/aergo/types"
+)
+
+// Version is the version of the compiler. It must be changed with any change
+// of the bytecode or the ABI compiled from a source.
+const Version = "1.0.0"
+
+// MaxMemory is the maximum bytes of the memory used to compile a source,
+// which is the limit of a contract execution.
+const MaxMemory = types.MaxContractMemory
+
+var errNewState = errors.New("cannot create a lua state")
+
+// Compiler returns the compiler recorded in the ABIs, with the versions of
+// aergoluac and LuaJIT.
+func Compiler() string {
+	return "aergoluac " + Version + " (" + C.GoString(C.luac_version()) + ")"
+}
+
+// Compile compiles src to the bytecode and the ABI. The top level code of src
+// is run to generate the ABI.
+func Compile(src []byte) (bytecode, abi []byte, err error) {
+	return compile(src, true)
+}
+
+// Bytecode compiles src to the bytecode only, without running it.
+func Bytecode(src []byte) ([]byte, error) {
+	bytecode, _, err := compile(src, false)
+	return bytecode, err
+}
+
+// Payload compiles src to the code of a contract, which is the length of the
+// bytecode followed by the bytecode and the ABI.
+func Payload(src []byte) ([]byte, error) {
+	bytecode, abi, err := Compile(src)
+	if err != nil {
+		return nil, err
+	}
+	payload := make([]byte, 4+len(bytecode)+len(abi))
+	binary.LittleEndian.PutUint32(payload[0:], uint32(len(bytecode)))
+	copy(payload[4:], bytecode)
+	copy(payload[4+len(bytecode):], abi)
+	return payload, nil
+}
+
+func compile(src []byte, withABI bool) ([]byte, []byte, error) {
+	L := C.luac_vm_newstate(C.size_t(MaxMemory))
+	if L == nil {
+		return nil, nil, errNewState
+	}
+	defer C.luac_vm_close(L)
+
+	cSrc := C.CString(string(src))
+	defer C.free(unsafe.Pointer(cSrc))
+	if errMsg := C.luac_vm_loadbuffer(L, cSrc, C.size_t(len(src))); errMsg != nil {
+		return nil, nil, cError(errMsg)
+	}
+
+	var code, cABI *C.char
+	var codeLen C.size_t
+	var abiOut **C.char
+	if withABI {
+		abiOut = &cABI
+	}
+	if errMsg := C.luac_vm_dump(L, &code, &codeLen, abiOut); errMsg != nil {
+		return nil, nil, cError(errMsg)
+	}
+	bytecode := C.GoBytes(unsafe.Pointer(code), C.int(codeLen))
+	C.free(unsafe.Pointer(code))
+	var abi []byte
+	if cABI != nil {
+		abi = []byte(C.GoString(cABI))
+		C.free(unsafe.Pointer(cABI))
+	}
+	return bytecode, abi, nil
+}
+
+// cError returns the error of a message allocated by compile.c
+func cError(errMsg *C.char) error {
+	err := errors.New(C.GoString(errMsg))
+	C.free(unsafe.Pointer(errMsg))
+	return err
+}
@@ -0,0 +1,69 @@
+package luac
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aergoio/aergo/types"
+)
+
+const helloSrc = `function hello(say) return "Hello " .. say end abi.register(hello)`
+
+func TestCompileReproducible(t *testing.T) {
+	code1, err := Payload([]byte(helloSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code2, err := Payload([]byte(helloSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(code1, code2) {
+		t.Error("the same source is compiled to different codes")
+	}
+
+	bytecode, abi, err := Compile([]byte(helloSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	onlyBytecode, err := Bytecode([]byte(helloSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bytecode, onlyBytecode) {
+		t.Error("the bytecode differs from the bytecode compiled with the abi")
+	}
+	var a types.ABI
+	if err := json.Unmarshal(abi, &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Compiler != Compiler() {
+		t.Errorf("compiler: expected %s, got %s", Compiler(), a.Compiler)
+	}
+}
+
+func TestCompileSandbox(t *testing.T) {
+	_, _, err := Compile([]byte(`os.exit(1)`))
+	if err == nil {
+		t.Error("the top level code uses os")
+	}
+	_, _, err = Compile([]byte(`while true do end`))
+	if err == nil || !strings.Contains(err.Error(), "runs too long") {
+		t.Errorf("expected the error of the loop, got %v", err)
+	}
+	if _, err := Bytecode([]byte(`while true do end`)); err != nil {
+		t.Error(err)
+	}
+	for _, g := range []string{"collectgarbage", "getfenv", "setfenv", "coroutine", "newproxy"} {
+		_, _, err = Compile([]byte(`assert(` + g + ` == nil, "` + g + `")`))
+		if err != nil {
+			t.Errorf("%s is not removed: %v", g, err)
+		}
+	}
+	_, _, err = Compile([]byte(`local s = string.rep("x", 100 * 1024 * 1024)`))
+	if err == nil || !strings.Contains(err.Error(), "memory limit exceeded") {
+		t.Errorf("expected the error of the memory limit, got %v", err)
+	}
+}
@@ -5,24 +5,15 @@
 
 package main
 
-/*
-#cgo CFLAGS: -I${SRCDIR}/../../libtool/include/luajit-2.0
-#cgo LDFLAGS: ${SRCDIR}/../../libtool/lib/libluajit-5.1.a -lm
-
-#include <stdlib.h>
-#include "compile.h"
-*/
-import "C"
 import (
 	"bufio"
 	"bytes"
-	"encoding/binary"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
-	"unsafe"
 
+	"github.com/aergoio/aergo/cmd/aergoluac/luac"
 	"github.com/mr-tron/base58/base58"
 	"github.com/spf13/cobra"
 )
@@ -31,7 +22,8 @@ var (
 	rootCmd *cobra.Command
 	abiFile string
 	payload bool
-	b       bytes.Buffer
+	version bool
+	bundle  bool
 )
 
 func init() {
@@ -40,7 +32,14 @@ func init() {
 		Use:   "aergoluac [flags] srcfile bcfile",
 		Short: "compile a contract",
 		Run: func(cmd *cobra.Command, args []string) {
-			if payload {
+			if version {
+				fmt.Println(luac.Compiler())
+			} else if bundle {
+				if len(args) < 1 {
+					log.Fatal(cmd.UsageString())
+				}
+				printBundle(args[0])
+			} else if payload {
 				if len(args) == 0 {
 					dumpFromStdin()
 				} else {
@@ -56,6 +55,8 @@ func init() {
 	}
 	rootCmd.PersistentFlags().StringVarP(&abiFile, "abi", "a", "", "abi filename")
 	rootCmd.PersistentFlags().BoolVar(&payload, "payload", false, "print the compilation result consisting of bytecode and abi")
+	rootCmd.PersistentFlags().BoolVar(&version, "version", false, "print the version of the compiler, which is recorded in the abi")
+	rootCmd.PersistentFlags().BoolVar(&bundle, "bundle", false, "print the source with its imports resolved, which is verified against the contract")
 }
 
 func main() {
@@ -65,33 +66,43 @@ func main() {
 }
 
 func compile(srcFileName, outFileName, abiFileName string) {
-	cSrcFileName := C.CString(srcFileName)
-	cOutFileName := C.CString(outFileName)
-	cAbiFileName := C.CString(abiFileName)
-	L := C.vm_newstate()
-	defer C.free(unsafe.Pointer(cSrcFileName))
-	defer C.free(unsafe.Pointer(cOutFileName))
-	defer C.free(unsafe.Pointer(cAbiFileName))
-	defer C.vm_close(L)
-
-	if errMsg := C.vm_compile(L, cSrcFileName, cOutFileName, cAbiFileName); errMsg != nil {
-		log.Fatal(C.GoString(errMsg))
+	src, err := luac.BundleFile(srcFileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var bytecode, abi []byte
+	if len(abiFileName) > 0 {
+		bytecode, abi, err = luac.Compile(src)
+	} else {
+		bytecode, err = luac.Bytecode(src)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(outFileName, bytecode, 0644); err != nil {
+		log.Fatal(err)
+	}
+	if len(abiFileName) > 0 {
+		if err := ioutil.WriteFile(abiFileName, abi, 0644); err != nil {
+			log.Fatal(err)
+		}
 	}
 }
 
 func dumpFromFile(srcFileName string) {
-	cSrcFileName := C.CString(srcFileName)
-	L := C.vm_newstate()
-	defer C.free(unsafe.Pointer(cSrcFileName))
-	defer C.vm_close(L)
-
-	if errMsg := C.vm_loadfile(L, cSrcFileName); errMsg != nil {
-		log.Fatal(C.GoString(errMsg))
+	src, err := luac.BundleFile(srcFileName)
+	if err != nil {
+		log.Fatal(err)
 	}
-	if errMsg := C.vm_stringdump(L); errMsg != nil {
-		log.Fatal(C.GoString(errMsg))
+	dump(src)
+}
+
+func printBundle(srcFileName string) {
+	src, err := luac.BundleFile(srcFileName)
+	if err != nil {
+		log.Fatal(err)
 	}
-	fmt.Print(base58.Encode(b.Bytes()))
+	os.Stdout.Write(src)
 }
 
 func dumpFromStdin() {
@@ -116,29 +127,18 @@ func dumpFromStdin() {
 		}
 		buf = bBuf.Bytes()
 	}
-	srcCode := C.CString(string(buf))
-	L := C.vm_newstate()
-	defer C.free(unsafe.Pointer(srcCode))
-	defer C.vm_close(L)
-
-	if errMsg := C.vm_loadstring(L, srcCode); errMsg != nil {
-		log.Fatal(C.GoString(errMsg))
-	}
-	if errMsg := C.vm_stringdump(L); errMsg != nil {
-		log.Fatal(C.GoString(errMsg))
+	// the imports of the standard input are relative to the working directory
+	src, err := luac.Bundle(buf, ".")
+	if err != nil {
+		log.Fatal(err)
 	}
-	fmt.Print(base58.Encode(b.Bytes()))
-}
-
-//export addLen
-func addLen(length C.int) {
-	var l [4]byte
-	binary.LittleEndian.PutUint32(l[:], uint32(length))
-	b.Write(l[:])
+	dump(src)
 }
 
-//export addByteN
-func addByteN(p *C.char, length C.int) {
-	s := C.GoStringN(p, length)
-	b.WriteString(s)
+func dump(src []byte) {
+	code, err := luac.Payload(src)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(base58.Encode(code))
 }
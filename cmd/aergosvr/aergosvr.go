@@ -92,7 +92,7 @@ func rootRun(cmd *cobra.Command, args []string) {
 	compMng.Register(mpoolSvc)
 	accountsvc := account.NewAccountService(cfg)
 	compMng.Register(accountsvc)
-	rpcSvc := rpc.NewRPC(compMng, cfg)
+	rpcSvc := rpc.NewRPC(compMng, cfg, chainSvc)
 	compMng.Register(rpcSvc)
 	p2pSvc := p2p.NewP2P(compMng, cfg, chainSvc)
 	compMng.Register(p2pSvc)
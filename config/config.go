@@ -85,7 +85,10 @@ func (ctx *ServerContext) GetDefaultP2PConfig() *P2PConfig {
 }
 
 func (ctx *ServerContext) GetDefaultBlockchainConfig() *BlockchainConfig {
-	return &BlockchainConfig{MaxBlockSize: types.DefaultMaxBlockSize}
+	return &BlockchainConfig{
+		MaxBlockSize: types.DefaultMaxBlockSize,
+		QueryTimeout: types.DefaultQueryTimeout,
+	}
 }
 
 func (ctx *ServerContext) GetDefaultMempoolConfig() *MempoolConfig {
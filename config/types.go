@@ -65,6 +65,7 @@ type P2PConfig struct {
 // BlockchainConfig defines configurations for blockchain service
 type BlockchainConfig struct {
 	MaxBlockSize uint32 `mapstructure:"maxblocksize"  description:"maximum block size in bytes"`
+	QueryTimeout int64  `mapstructure:"querytimeout" description:"timeout of a contract query in milliseconds"`
 }
 
 // MempoolConfig defines configurations for mempool service
@@ -80,6 +81,7 @@ type ConsensusConfig struct {
 	BlockInterval int64    `mapstructure:"blockinterval" description:"block production interval (sec)"`
 	DposBpNumber  uint16   `mapstructure:"dposbps" description:"the number of DPoS block producers"`
 	BpIds         []string `mapstructure:"bpids" description:"the IDs of the block producers"`
+	Coinbase      string   `mapstructure:"coinbase" description:"base58 address of the account credited with the fees of the produced blocks"`
 }
 
 /*
@@ -131,6 +133,7 @@ nppeerpool = "{{.P2P.NPPeerPool}}"
 [blockchain]
 # blockchain configurations
 maxblocksize = {{.Blockchain.MaxBlockSize}}
+querytimeout = {{.Blockchain.QueryTimeout}}
 
 [mempool]
 showmetrics = {{.Mempool.ShowMetrics}}
@@ -144,4 +147,5 @@ dposbps = {{.Consensus.DposBpNumber}}
 bpids = [{{range .Consensus.BpIds}}
 "{{.}}", {{end}}
 ]
+coinbase = "{{.Consensus.Coinbase}}"
 `
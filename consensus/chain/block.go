@@ -6,10 +6,12 @@ import (
 	"time"
 
 	"github.com/aergoio/aergo/blockchain"
+	"github.com/aergoio/aergo/config"
 	"github.com/aergoio/aergo/message"
 	"github.com/aergoio/aergo/pkg/component"
 	"github.com/aergoio/aergo/types"
 	"github.com/golang/protobuf/proto"
+	"github.com/mr-tron/base58/base58"
 )
 
 var (
@@ -17,6 +19,7 @@ var (
 	ErrQuit = errors.New("shutdown initiated")
 
 	errBlockSizeLimit = errors.New("the transactions included exceeded the block size limit")
+	errTxNotValid     = errors.New("the transaction is not valid at the block being generated")
 )
 
 // ErrTimeout can be used to indicatefor any kind of timeout.
@@ -55,21 +58,35 @@ func GetBestBlock(hs component.ICompSyncRequester) *types.Block {
 	return result.(message.GetBestBlockRsp).Block
 }
 
-// MaxBlockBodySize returns the maximum block body size.
+// MaxBlockBodySize returns the maximum block body size. It leaves room for the
+// event bloom put into the header when the block is executed.
 //
 // TODO: This is not an exact size. Let's make it exact!
 func MaxBlockBodySize() uint32 {
-	return blockchain.MaxBlockSize - uint32(proto.Size(&types.BlockHeader{}))
+	header := &types.BlockHeader{EventsBloom: make([]byte, types.BloomByteLength)}
+	return blockchain.MaxBlockSize - uint32(proto.Size(header))
 }
 
-// GenerateBlock generate & return a new block
-func GenerateBlock(hs component.ICompSyncRequester, prevBlock *types.Block, txOp TxOp, ts int64) (*types.Block, *types.BlockState, error) {
-	txs, blockState, err := GatherTXs(hs, txOp, MaxBlockBodySize())
+// Coinbase returns the coinbase account of the blocks produced with cfg, which
+// is credited with the fees of their txs. It is nil if cfg has none.
+func Coinbase(cfg *config.ConsensusConfig) ([]byte, error) {
+	if len(cfg.Coinbase) == 0 {
+		return nil, nil
+	}
+	return base58.Decode(cfg.Coinbase)
+}
+
+// GenerateBlock generate & return a new block, whose txs pay their fees to
+// coinbase.
+func GenerateBlock(hs component.ICompSyncRequester, prevBlock *types.Block, txOp TxOp, ts int64,
+	coinbase []byte) (*types.Block, *types.BlockState, error) {
+	txs, blockState, err := GatherTXs(hs, txOp, prevBlock.BlockNo()+1, MaxBlockBodySize())
 	if err != nil {
 		return nil, nil, err
 	}
 
 	block := types.NewBlock(prevBlock, txs, ts)
+	block.SetCoinbaseAccount(coinbase)
 
 	return block, blockState, nil
 }
@@ -77,12 +77,21 @@ func newBlockLimitOp(maxBlockBodySize uint32) TxOpFn {
 	})
 }
 
+func newTxWindowOp(blockNo types.BlockNo) TxOpFn {
+	return TxOpFn(func(tx *types.Tx) (*types.BlockState, error) {
+		if !tx.GetBody().IsValidAt(blockNo) {
+			return nil, errTxNotValid
+		}
+		return nil, nil
+	})
+}
+
 // GatherTXs returns transactions from txIn. The selection is done by applying
-// txDo.
-func GatherTXs(hs component.ICompSyncRequester, txOp TxOp, maxBlockBodySize uint32) ([]*types.Tx, *types.BlockState, error) {
+// txDo. The transactions which are not valid at blockNo are skipped together
+// with the following transactions of the same account.
+func GatherTXs(hs component.ICompSyncRequester, txOp TxOp, blockNo types.BlockNo, maxBlockBodySize uint32) ([]*types.Tx, *types.BlockState, error) {
 	var (
 		nCollected int
-		last       int
 		nCand      int
 	)
 
@@ -96,16 +105,28 @@ func GatherTXs(hs component.ICompSyncRequester, txOp TxOp, maxBlockBodySize uint
 		logger.Debug().Int("candidates", nCand).Int("collected", nCollected).Msg("transactions collected")
 	}()
 
-	op := NewCompTxOp(newBlockLimitOp(maxBlockBodySize), txOp)
+	op := NewCompTxOp(newTxWindowOp(blockNo), newBlockLimitOp(maxBlockBodySize), txOp)
 	var blockState *types.BlockState
-	for i, tx := range txIn {
-		last = i
+	txRes := make([]*types.Tx, 0, nCand)
+	skipped := map[types.AccountID]bool{}
+	for _, tx := range txIn {
+		// The transactions after a skipped one have a gap in their nonces.
+		accID := types.ToAccountID(tx.GetBody().GetAccount())
+		if skipped[accID] {
+			continue
+		}
 
 		curState, err := op.Apply(tx)
 		if curState != nil {
 			blockState = curState
 		}
 
+		if err == errTxNotValid {
+			skipped[accID] = true
+			continue
+		}
+		txRes = append(txRes, tx)
+
 		if e, ok := err.(ErrTimeout); ok {
 			err = e
 			break
@@ -116,7 +137,7 @@ func GatherTXs(hs component.ICompSyncRequester, txOp TxOp, maxBlockBodySize uint
 		}
 	}
 
-	nCollected = last + 1
+	nCollected = len(txRes)
 
-	return txIn[0 : last+1], blockState, nil
+	return txRes, blockState, nil
 }
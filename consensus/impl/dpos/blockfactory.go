@@ -51,10 +51,12 @@ type BlockFactory struct {
 	privKey          crypto.PrivKey
 	txOp             chain.TxOp
 	sdb              *state.ChainStateDB
+	coinbase         []byte
 }
 
-// NewBlockFactory returns a new BlockFactory
-func NewBlockFactory(hub *component.ComponentHub, quitC <-chan interface{}) *BlockFactory {
+// NewBlockFactory returns a new BlockFactory, whose blocks credit the fees of
+// their txs to coinbase.
+func NewBlockFactory(hub *component.ComponentHub, quitC <-chan interface{}, coinbase []byte) *BlockFactory {
 	bf := &BlockFactory{
 		ComponentHub:     hub,
 		jobQueue:         make(chan interface{}, slotQueueMax),
@@ -64,6 +66,7 @@ func NewBlockFactory(hub *component.ComponentHub, quitC <-chan interface{}) *Blo
 		quit:             quitC,
 		ID:               p2p.NodeSID(),
 		privKey:          p2p.NodePrivKey(),
+		coinbase:         coinbase,
 	}
 
 	bf.txOp = chain.NewCompTxOp(
@@ -189,7 +192,7 @@ func (bf *BlockFactory) generateBlock(bpi *bpInfo, lpbNo types.BlockNo) (*types.
 	*/
 	txOp := bf.txOp
 
-	block, blockState, err := chain.GenerateBlock(bf, bpi.bestBlock, txOp, bpi.slot.UnixNano())
+	block, blockState, err := chain.GenerateBlock(bf, bpi.bestBlock, txOp, bpi.slot.UnixNano(), bf.coinbase)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -57,13 +57,18 @@ func New(cfg *config.Config, hub *component.ComponentHub) (consensus.Consensus,
 		return nil, err
 	}
 
+	coinbase, err := chain.Coinbase(cfg.Consensus)
+	if err != nil {
+		return nil, err
+	}
+
 	quitC := make(chan interface{})
 
 	return &DPoS{
 		Status:       NewStatus(bpConsensusCount),
 		ComponentHub: hub,
 		bpc:          bpc,
-		bf:           NewBlockFactory(hub, quitC),
+		bf:           NewBlockFactory(hub, quitC, coinbase),
 		quit:         quitC,
 	}, nil
 }
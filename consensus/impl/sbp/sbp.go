@@ -32,18 +32,25 @@ type SimpleBlockFactory struct {
 	maxBlockBodySize uint32
 	txOp             chain.TxOp
 	quit             chan interface{}
+	coinbase         []byte
 }
 
 // New returns a SimpleBlockFactory.
 func New(cfg *config.Config, hub *component.ComponentHub) (*SimpleBlockFactory, error) {
 	consensus.InitBlockInterval(cfg.Consensus.BlockInterval)
 
+	coinbase, err := chain.Coinbase(cfg.Consensus)
+	if err != nil {
+		return nil, err
+	}
+
 	s := &SimpleBlockFactory{
 		ComponentHub:     hub,
 		jobQueue:         make(chan interface{}, slotQueueMax),
 		blockInterval:    consensus.BlockInterval,
 		maxBlockBodySize: chain.MaxBlockBodySize(),
 		quit:             make(chan interface{}),
+		coinbase:         coinbase,
 	}
 
 	s.txOp = chain.NewCompTxOp(
@@ -112,7 +119,7 @@ func (s *SimpleBlockFactory) Start() {
 		select {
 		case e := <-s.jobQueue:
 			if prevBlock, ok := e.(*types.Block); ok {
-				block, _, err := chain.GenerateBlock(s, prevBlock, s.txOp, time.Now().UnixNano())
+				block, _, err := chain.GenerateBlock(s, prevBlock, s.txOp, time.Now().UnixNano(), s.coinbase)
 				if err == chain.ErrQuit {
 					return
 				} else if err != nil {
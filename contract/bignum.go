@@ -0,0 +1,122 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package contract
+
+/*
+#include <stdlib.h>
+#include "vm.h"
+#include "bignum_module.h"
+*/
+import "C"
+import (
+	"errors"
+	"math/big"
+	"unsafe"
+
+	"github.com/aergoio/aergo/types"
+)
+
+// the operations of the bignum module, which must be matched to
+// bignum_module.c
+const (
+	bignumAdd = iota
+	bignumSub
+	bignumMul
+	bignumDiv
+	bignumMod
+	bignumPow
+	bignumNeg
+	bignumAbs
+)
+
+var (
+	errBignumDivZero  = errors.New("bignum divide by zero")
+	errBignumNegPower = errors.New("bignum negative exponent")
+	errBignumOverflow = errors.New("bignum overflow")
+)
+
+// bignumArith returns the result of op. The division and the modulo are
+// truncated toward zero like big.Int.Quo and big.Int.Rem.
+func bignumArith(op int, x, y *big.Int) (*big.Int, error) {
+	z := new(big.Int)
+	switch op {
+	case bignumAdd:
+		z.Add(x, y)
+	case bignumSub:
+		z.Sub(x, y)
+	case bignumMul:
+		z.Mul(x, y)
+	case bignumDiv, bignumMod:
+		if y.Sign() == 0 {
+			return nil, errBignumDivZero
+		}
+		if op == bignumDiv {
+			z.Quo(x, y)
+		} else {
+			z.Rem(x, y)
+		}
+	case bignumPow:
+		if y.Sign() < 0 {
+			return nil, errBignumNegPower
+		}
+		// the size of the result is checked before it is computed
+		if x.CmpAbs(big.NewInt(1)) > 0 &&
+			(y.BitLen() > 32 || y.Int64()*int64(x.BitLen()-1) > types.BignumMaxBits) {
+			return nil, errBignumOverflow
+		}
+		z.Exp(x, y, nil)
+	case bignumNeg:
+		z.Neg(x)
+	case bignumAbs:
+		z.Abs(x)
+	}
+	if types.CheckBignum(z) != nil {
+		return nil, errBignumOverflow
+	}
+	return z, nil
+}
+
+func luaPushBignum(L *LState, n *big.Int) {
+	s := C.CString(n.String())
+	C.vm_memory_unlimited(L, 1)
+	C.bignum_push(L, s)
+	C.vm_memory_unlimited(L, 0)
+	C.free(unsafe.Pointer(s))
+}
+
+// bignumOperand parses the decimal string of a bignum userdata, which has been
+// checked by ParseBignum when it was created.
+func bignumOperand(s *C.char) *big.Int {
+	n, _ := new(big.Int).SetString(C.GoString(s), 10)
+	return n
+}
+
+//export LuaBignumArith
+func LuaBignumArith(L *LState, op C.int, x *C.char, y *C.char) C.int {
+	z, err := bignumArith(int(op), bignumOperand(x), bignumOperand(y))
+	if err != nil {
+		luaPushError(L, err.Error())
+		return -1
+	}
+	luaPushBignum(L, z)
+	return 0
+}
+
+//export LuaBignumCompare
+func LuaBignumCompare(x *C.char, y *C.char) C.int {
+	return C.int(bignumOperand(x).Cmp(bignumOperand(y)))
+}
+
+//export LuaBignumParse
+func LuaBignumParse(L *LState, s *C.char) C.int {
+	n, err := types.ParseBignum(C.GoString(s))
+	if err != nil {
+		luaPushError(L, err.Error()+": "+C.GoString(s))
+		return -1
+	}
+	luaPushBignum(L, n)
+	return 0
+}
@@ -0,0 +1,292 @@
+#include <string.h>
+#include <stdlib.h>
+#include <stdio.h>
+#include <math.h>
+#include "vm.h"
+#include "bignum_module.h"
+#include "_cgo_export.h"
+
+/* a bignum is a userdata holding the decimal string of an integer, which is
+   immutable. The arithmetic is done by big.Int in bignum.go */
+static const char *mt_bignum = "_bignum";
+
+/* the operations of LuaBignumArith, which must be matched to bignum.go */
+#define BIGNUM_ADD  0
+#define BIGNUM_SUB  1
+#define BIGNUM_MUL  2
+#define BIGNUM_DIV  3
+#define BIGNUM_MOD  4
+#define BIGNUM_POW  5
+#define BIGNUM_NEG  6
+#define BIGNUM_ABS  7
+
+void bignum_push(lua_State *L, const char *n)
+{
+	size_t len = strlen(n);
+	char *ud = (char *)lua_newuserdata(L, len + 1);
+
+	memcpy(ud, n, len + 1);
+	luaL_getmetatable(L, mt_bignum);
+	lua_setmetatable(L, -2);
+}
+
+/* bignum_get returns the decimal string of the bignum at idx, or NULL if it
+   is not a bignum */
+const char *bignum_get(lua_State *L, int idx)
+{
+	const char *n = (const char *)lua_touserdata(L, idx);
+
+	if (n == NULL || !lua_getmetatable(L, idx))
+		return NULL;
+	luaL_getmetatable(L, mt_bignum);
+	if (!lua_rawequal(L, -1, -2))
+		n = NULL;
+	lua_pop(L, 2);
+	return n;
+}
+
+/* check_bignum returns the decimal string of the argument at idx, which is a
+   bignum or an integral number. A number is replaced by a bignum */
+static const char *check_bignum(lua_State *L, int idx)
+{
+	const char *n;
+	lua_Number d;
+	char buf[512];
+
+	if ((n = bignum_get(L, idx)) != NULL)
+		return n;
+	if (lua_type(L, idx) != LUA_TNUMBER)
+		luaL_typerror(L, idx, "bignum");
+	d = lua_tonumber(L, idx);
+	if (isinf(d) || isnan(d) || d != floor(d))
+		luaL_argerror(L, idx, "not an integer");
+	if (d == 0)
+		d = 0; /* no negative zero */
+	snprintf(buf, sizeof(buf), "%.0f", d);
+	bignum_push(L, buf);
+	lua_replace(L, idx);
+	return (const char *)lua_touserdata(L, idx);
+}
+
+/* words returns the number of the 64 bit words of a decimal string */
+static unsigned long long words(const char *n)
+{
+	return strlen(n) / BIGNUM_WORD_DIGITS + 1;
+}
+
+/* the gas of an operation grows with the words of its operands: linearly for
+   the additions and the comparisons, and with their product for the
+   multiplications and the divisions. A power is charged by the square of its
+   result, whose size is bounded before it is computed */
+static int arith(lua_State *L, int op)
+{
+	const char *x = check_bignum(L, 1);
+	const char *y = x;
+	unsigned long long n;
+
+	if (op != BIGNUM_NEG && op != BIGNUM_ABS)
+		y = check_bignum(L, 2);
+	switch (op) {
+	case BIGNUM_MUL:
+	case BIGNUM_DIV:
+	case BIGNUM_MOD:
+		n = words(x) * words(y);
+		break;
+	case BIGNUM_POW:
+		n = 0;
+		break;
+	default:
+		n = words(x) + words(y);
+	}
+	vm_use_gas(L, GAS_BIGNUM + n * GAS_BIGNUM_WORD);
+	if (LuaBignumArith(L, op, (char *)x, (char *)y) < 0) {
+		lua_error(L);
+	}
+	if (op == BIGNUM_POW) {
+		n = words(bignum_get(L, -1));
+		vm_use_gas(L, n * n * GAS_BIGNUM_WORD);
+	}
+	return 1;
+}
+
+static int bignum_add(lua_State *L)
+{
+	return arith(L, BIGNUM_ADD);
+}
+
+static int bignum_sub(lua_State *L)
+{
+	return arith(L, BIGNUM_SUB);
+}
+
+static int bignum_mul(lua_State *L)
+{
+	return arith(L, BIGNUM_MUL);
+}
+
+static int bignum_div(lua_State *L)
+{
+	return arith(L, BIGNUM_DIV);
+}
+
+static int bignum_mod(lua_State *L)
+{
+	return arith(L, BIGNUM_MOD);
+}
+
+static int bignum_pow(lua_State *L)
+{
+	return arith(L, BIGNUM_POW);
+}
+
+static int bignum_neg(lua_State *L)
+{
+	return arith(L, BIGNUM_NEG);
+}
+
+static int bignum_abs(lua_State *L)
+{
+	return arith(L, BIGNUM_ABS);
+}
+
+/* the comparison operators of lua 5.1 compare a bignum with a bignum only:
+   == is false and < raises an error between a bignum and a number, before
+   the metamethods are called. bignum.compare converts its numbers instead */
+static int compare(lua_State *L)
+{
+	const char *x = check_bignum(L, 1);
+	const char *y = check_bignum(L, 2);
+
+	vm_use_gas(L, (words(x) + words(y)) * GAS_BIGNUM_WORD);
+	return LuaBignumCompare((char *)x, (char *)y);
+}
+
+static int bignum_eq(lua_State *L)
+{
+	lua_pushboolean(L, compare(L) == 0);
+	return 1;
+}
+
+static int bignum_lt(lua_State *L)
+{
+	lua_pushboolean(L, compare(L) < 0);
+	return 1;
+}
+
+static int bignum_le(lua_State *L)
+{
+	lua_pushboolean(L, compare(L) <= 0);
+	return 1;
+}
+
+static int bignum_compare(lua_State *L)
+{
+	lua_pushinteger(L, compare(L));
+	return 1;
+}
+
+static int bignum_tostring(lua_State *L)
+{
+	lua_pushstring(L, check_bignum(L, 1));
+	return 1;
+}
+
+static int bignum_concat(lua_State *L)
+{
+	lua_getglobal(L, "tostring");
+	lua_pushvalue(L, 1);
+	lua_call(L, 1, 1);
+	lua_getglobal(L, "tostring");
+	lua_pushvalue(L, 2);
+	lua_call(L, 1, 1);
+	lua_concat(L, 2);
+	return 1;
+}
+
+/* bignum.number converts a decimal string or an integral number to a
+   bignum */
+static int bignum_number(lua_State *L)
+{
+	if (lua_type(L, 1) != LUA_TSTRING) {
+		check_bignum(L, 1);
+		lua_settop(L, 1);
+		return 1;
+	}
+	vm_use_gas(L, GAS_BIGNUM + words(lua_tostring(L, 1)) * GAS_BIGNUM_WORD);
+	if (LuaBignumParse(L, (char *)lua_tostring(L, 1)) < 0) {
+		lua_error(L);
+	}
+	return 1;
+}
+
+static int bignum_isbignum(lua_State *L)
+{
+	lua_pushboolean(L, bignum_get(L, 1) != NULL);
+	return 1;
+}
+
+/* bignum.tonumber converts a bignum to a number, which loses the precision
+   above 2^53 */
+static int bignum_tonumber(lua_State *L)
+{
+	lua_pushnumber(L, strtod(check_bignum(L, 1), NULL));
+	return 1;
+}
+
+static int bignum_iszero(lua_State *L)
+{
+	lua_pushboolean(L, strcmp(check_bignum(L, 1), "0") == 0);
+	return 1;
+}
+
+static int bignum_isneg(lua_State *L)
+{
+	lua_pushboolean(L, check_bignum(L, 1)[0] == '-');
+	return 1;
+}
+
+static const luaL_Reg bignum_meta[] = {
+	{"__add", bignum_add},
+	{"__sub", bignum_sub},
+	{"__mul", bignum_mul},
+	{"__div", bignum_div},
+	{"__mod", bignum_mod},
+	{"__pow", bignum_pow},
+	{"__unm", bignum_neg},
+	{"__eq", bignum_eq},
+	{"__lt", bignum_lt},
+	{"__le", bignum_le},
+	{"__tostring", bignum_tostring},
+	{"__concat", bignum_concat},
+	{NULL, NULL}
+};
+
+static const luaL_Reg bignum_lib[] = {
+	{"number", bignum_number},
+	{"isbignum", bignum_isbignum},
+	{"tostring", bignum_tostring},
+	{"tonumber", bignum_tonumber},
+	{"compare", bignum_compare},
+	{"iszero", bignum_iszero},
+	{"isneg", bignum_isneg},
+	{"neg", bignum_neg},
+	{"abs", bignum_abs},
+	{"pow", bignum_pow},
+	{"div", bignum_div},
+	{"mod", bignum_mod},
+	{NULL, NULL}
+};
+
+int luaopen_bignum(lua_State *L)
+{
+	luaL_newmetatable(L, mt_bignum);
+	luaL_register(L, NULL, bignum_meta);
+	/* getmetatable returns the name, so that a contract cannot change the
+	   operators */
+	lua_pushstring(L, "bignum");
+	lua_setfield(L, -2, "__metatable");
+	lua_pop(L, 1);
+
+	luaL_register(L, "bignum", bignum_lib);
+	return 1;
+}
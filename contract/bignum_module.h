@@ -0,0 +1,12 @@
+#ifndef _BIGNUM_MODULE_H
+#define _BIGNUM_MODULE_H
+
+typedef struct lua_State lua_State;
+/* the start of the JSON object of a bignum, {"_bignum":"<decimal>"} */
+#define BIGNUM_JSON_PREFIX "{\"_bignum\":\""
+
+extern int luaopen_bignum(lua_State *L);
+extern void bignum_push(lua_State *L, const char *n);
+extern const char *bignum_get(lua_State *L, int idx);
+
+#endif /* _BIGNUM_MODULE_H */
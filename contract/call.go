@@ -0,0 +1,335 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package contract
+
+/*
+#include <stdlib.h>
+#include "vm.h"
+#include "util.h"
+*/
+import "C"
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/aergoio/aergo/state"
+	"github.com/aergoio/aergo/types"
+	"github.com/mr-tron/base58/base58"
+)
+
+// maxCallDepth bounds the nesting of contract calls
+const maxCallDepth = 16
+
+var errCallNotPermitted = errors.New("cannot call a contract in this context")
+
+// newCallContext returns the context of a contract called by another one.
+// It shares the tx and the block of the caller and uses the rest of its gas
+// and of its memory. The caller must have gas left, see checkCallGas.
+func newCallContext(caller *LBlockchainCtx, stateSet *StateSet, contractState *state.ContractState,
+	sender, contractID string) *LBlockchainCtx {
+
+	depth := caller.callDepth + 1
+	// the memory of the caller is kept while the callee runs. A zero limit
+	// is no limit, so the callee of a caller using up its memory gets a
+	// limit it exceeds at once
+	memoryLimit := caller.memoryLimit
+	if used := C.ulonglong(caller.memoryUsed); caller.memoryUsed > 0 {
+		if used < memoryLimit {
+			memoryLimit -= used
+		} else {
+			memoryLimit = 1
+		}
+	}
+	txHash := C.GoString(caller.txHash)
+	// the key of the caller tells apart the calls of the queries, which have
+	// no tx hash
+	stateKey := fmt.Sprintf("%s>%s:%d", C.GoString(caller.stateKey), contractID, depth)
+	contractMap.register(stateKey, contractState, stateSet)
+
+	return &LBlockchainCtx{
+		stateKey:    C.CString(stateKey),
+		sender:      C.CString(sender),
+		txHash:      C.CString(txHash),
+		blockHeight: caller.blockHeight,
+		timestamp:   caller.timestamp,
+		node:        C.CString(C.GoString(caller.node)),
+		confirmed:   caller.confirmed,
+		contractId:  C.CString(contractID),
+		isQuery:     caller.isQuery,
+		gasLimit:    caller.gasLimit - caller.gasUsed,
+		callDepth:   depth,
+		trace:       caller.trace,
+		memoryLimit: memoryLimit,
+		deadline:    caller.deadline,
+	}
+}
+
+// checkCallGas fails a call or a deployment of a caller having no gas left,
+// which the memory it allocates may use up after the gas of the call is
+// charged.
+func checkCallGas(caller *LBlockchainCtx) error {
+	if caller.gasUsed >= caller.gasLimit {
+		return ErrOutOfGas
+	}
+	return nil
+}
+
+func freeCallContext(ctx *LBlockchainCtx) {
+	contractMap.unregister(C.GoString(ctx.stateKey))
+
+	C.free(unsafe.Pointer(ctx.stateKey))
+	C.free(unsafe.Pointer(ctx.sender))
+	C.free(unsafe.Pointer(ctx.txHash))
+	C.free(unsafe.Pointer(ctx.node))
+	C.free(unsafe.Pointer(ctx.contractId))
+}
+
+// callContract runs a function of another contract on behalf of the caller.
+// No amount is sent to the callee. With delegate, the code of the callee runs
+// on the storage, the sender and the amount of the caller. A failed call
+// reverts the whole execution, even if the caller catches the error.
+func callContract(caller *LBlockchainCtx, contractID, fname, args string, delegate bool) (string, error) {
+	callerKey := C.GoString(caller.stateKey)
+	stateSet := contractMap.lookupSet(callerKey)
+	if stateSet == nil {
+		return "", errCallNotPermitted
+	}
+	if caller.callDepth >= maxCallDepth {
+		return "", fmt.Errorf("exceeded the maximum call depth (%d)", maxCallDepth)
+	}
+	if err := checkCallGas(caller); err != nil {
+		return "", err
+	}
+	address, err := base58.Decode(contractID)
+	if err != nil || len(address) == 0 {
+		return "", fmt.Errorf("invalid contract address %s", contractID)
+	}
+	calleeState, err := stateSet.OpenContract(types.ToAccountID(address))
+	if err != nil {
+		return "", err
+	}
+	contract := getContract(calleeState, address)
+	if contract == nil {
+		return "", fmt.Errorf("cannot find contract %s", contractID)
+	}
+	ci := types.CallInfo{Name: fname}
+	if err := json.Unmarshal([]byte(args), &ci.Args); err != nil {
+		return "", err
+	}
+	fn, err := contract.checkCall(&ci, 0)
+	if err != nil {
+		return "", err
+	}
+
+	callerID := C.GoString(caller.contractId)
+	trace := &types.CallTrace{
+		Caller:   callerID,
+		Contract: contractID,
+		Function: fname,
+		Args:     args,
+		Delegate: delegate,
+		Depth:    uint32(caller.callDepth + 1),
+	}
+	stateSet.trace = append(stateSet.trace, trace)
+	stateSet.traceCall(caller, fname, args)
+
+	var ctx *LBlockchainCtx
+	if delegate {
+		ctx = newCallContext(caller, stateSet, contractMap.lookup(callerKey), C.GoString(caller.sender), callerID)
+		// the callee runs as the caller, which is sent the amount
+		ctx.amount = caller.amount
+	} else {
+		ctx = newCallContext(caller, stateSet, calleeState, callerID, contractID)
+	}
+	defer freeCallContext(ctx)
+	if fn != nil && fn.View {
+		// a view function cannot change any state
+		ctx.isQuery = 1
+	}
+	if contract.isLibrary() {
+		ctx.library = 1
+	}
+
+	ctrLog.Debug().Str("caller", callerID).Str("function", fname).Msgf("contract %s", contractID)
+	ce := newExecutor(contract, ctx)
+	defer ce.close()
+	ce.call(&ci)
+	defer func() {
+		stateSet.traceReturn(ctx, fname, trace.Ret, err)
+	}()
+
+	caller.gasUsed += ctx.gasUsed
+	gasUsed, _ := usedGas(ctx)
+	trace.GasUsed = gasUsed
+	err = ce.err
+	if limitErr := ce.limitErr(ctx); limitErr != nil {
+		err = limitErr
+		if ctx.timedOut != 0 {
+			caller.timedOut = 1
+		}
+	} else if err == nil && fn != nil {
+		err = fn.CheckReturns(ce.jsonRet)
+	}
+	if err != nil {
+		trace.Status = err.Error()
+		if stateSet.err == nil {
+			stateSet.err = fmt.Errorf("contract call to %s failed: %s", contractID, err.Error())
+		}
+		return "", err
+	}
+	trace.Status = "SUCCESS"
+	trace.Ret = ce.jsonRet
+	return ce.jsonRet, nil
+}
+
+// checkLibrary returns an error if the contract is not a library, which
+// declares abi.library.
+func checkLibrary(caller *LBlockchainCtx, contractID string) error {
+	stateSet := contractMap.lookupSet(C.GoString(caller.stateKey))
+	if stateSet == nil {
+		return errCallNotPermitted
+	}
+	address, err := base58.Decode(contractID)
+	if err != nil || len(address) == 0 {
+		return fmt.Errorf("invalid contract address %s", contractID)
+	}
+	libState, err := stateSet.OpenContract(types.ToAccountID(address))
+	if err != nil {
+		return err
+	}
+	contract := getContract(libState, address)
+	if contract == nil {
+		return fmt.Errorf("cannot find contract %s", contractID)
+	}
+	if !contract.isLibrary() {
+		return fmt.Errorf("contract %s is not a library", contractID)
+	}
+	return nil
+}
+
+//export LuaCheckLibrary
+func LuaCheckLibrary(L *LState, bcCtx *LBlockchainCtx, contractID *C.char) C.int {
+	if err := checkLibrary(bcCtx, C.GoString(contractID)); err != nil {
+		luaPushError(L, err.Error())
+		return -1
+	}
+	return 0
+}
+
+// deployContract deploys code from the caller at the salted address of the
+// caller, salt and code, and runs its constructor with args. The caller is the
+// sender of the constructor and the owner of the new contract.
+func deployContract(caller *LBlockchainCtx, code, salt []byte, args string) (string, error) {
+	callerKey := C.GoString(caller.stateKey)
+	stateSet := contractMap.lookupSet(callerKey)
+	if stateSet == nil || caller.isQuery != 0 {
+		return "", errCallNotPermitted
+	}
+	if caller.callDepth >= maxCallDepth {
+		return "", fmt.Errorf("exceeded the maximum call depth (%d)", maxCallDepth)
+	}
+	if err := checkCallGas(caller); err != nil {
+		return "", err
+	}
+	callerID := C.GoString(caller.contractId)
+	deployer, err := base58.Decode(callerID)
+	if err != nil {
+		return "", err
+	}
+	if err := checkCode(code); err != nil {
+		return "", err
+	}
+	address := types.SaltedContractAddress(deployer, salt, types.CodeHash(code))
+	contractID := base58.Encode(address)
+	contractState, err := stateSet.OpenContract(types.ToAccountID(address))
+	if err != nil {
+		return "", err
+	}
+	// a destructed contract keeps its info, so its address is never reused
+	info, err := getContractInfo(contractState)
+	if err != nil {
+		return "", err
+	}
+	if len(contractState.GetCodeHash()) > 0 || len(info.Versions) > 0 {
+		return "", fmt.Errorf("contract %s is already deployed", contractID)
+	}
+	if err := contractState.SetCode(code); err != nil {
+		return "", err
+	}
+
+	trace := &types.CallTrace{
+		Caller:   callerID,
+		Contract: contractID,
+		Function: constructorName,
+		Args:     args,
+		Depth:    uint32(caller.callDepth + 1),
+	}
+	stateSet.trace = append(stateSet.trace, trace)
+
+	ctx := newCallContext(caller, stateSet, contractState, callerID, contractID)
+	defer freeCallContext(ctx)
+	txHash, _ := hex.DecodeString(C.GoString(caller.txHash))
+	if err = recordDeploy(contractState, ctx, txHash); err == nil {
+		var jsonRet string
+		jsonRet, trace.GasUsed, err = construct(contractState, []byte(args), address, ctx, stateSet)
+		trace.Ret = jsonRet
+	}
+	caller.gasUsed += ctx.gasUsed
+	if ctx.timedOut != 0 {
+		caller.timedOut = 1
+	}
+	if err != nil {
+		trace.Status = err.Error()
+		if stateSet.err == nil {
+			stateSet.err = fmt.Errorf("contract deploy to %s failed: %s", contractID, err.Error())
+		}
+		return "", err
+	}
+	trace.Status = "CREATED"
+	return contractID, nil
+}
+
+//export LuaDeployContract
+func LuaDeployContract(L *LState, bcCtx *LBlockchainCtx, code *C.char, codeLen C.int, salt *C.char, saltLen C.int,
+	args *C.char) C.int {
+	contractID, err := deployContract(bcCtx, C.GoBytes(unsafe.Pointer(code), codeLen),
+		C.GoBytes(unsafe.Pointer(salt), saltLen), C.GoString(args))
+	if err != nil {
+		luaPushError(L, err.Error())
+		return -1
+	}
+	luaPushString(L, contractID)
+	return 1
+}
+
+//export LuaCallContract
+func LuaCallContract(L *LState, bcCtx *LBlockchainCtx, contractID *C.char, fname *C.char, args *C.char, delegate C.int) C.int {
+	ret, err := callContract(bcCtx, C.GoString(contractID), C.GoString(fname), C.GoString(args), delegate != 0)
+	if err == nil {
+		var values []json.RawMessage
+		if err = json.Unmarshal([]byte(ret), &values); err == nil {
+			for _, v := range values {
+				value := C.CString(string(v))
+				C.vm_memory_unlimited(L, 1)
+				converted := C.lua_util_json_to_lua(L, value)
+				C.vm_memory_unlimited(L, 0)
+				C.free(unsafe.Pointer(value))
+				if converted != 0 {
+					err = fmt.Errorf("cannot convert the result %s", string(v))
+					break
+				}
+			}
+			if err == nil {
+				return C.int(len(values))
+			}
+		}
+	}
+	luaPushError(L, err.Error())
+	return -1
+}
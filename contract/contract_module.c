@@ -0,0 +1,253 @@
+#include <string.h>
+#include <stdlib.h>
+#include <stdio.h>
+#include "vm.h"
+#include "util.h"
+#include "bignum_module.h"
+#include "_cgo_export.h"
+
+#define EVENT_NAME_MAX  64
+
+extern const bc_ctx_t *getLuaExecContext(lua_State *L);
+
+static int callFunction(lua_State *L, char *contract, char *fname, int nargs, int delegate)
+{
+	char *jsonArgs;
+	sbuff_t sbuf;
+	int ret;
+	bc_ctx_t *exec = (bc_ctx_t *)getLuaExecContext(L);
+
+	if (exec == NULL) {
+		luaL_error(L, "cannot find execution context");
+	}
+	vm_use_gas(L, GAS_CONTRACT_CALL);
+
+	lua_util_sbuf_init(&sbuf, 64);
+	jsonArgs = lua_util_get_json_from_ret(L, nargs, &sbuf);
+
+	ret = LuaCallContract(L, exec, contract, fname, jsonArgs, delegate);
+	free(sbuf.buf);
+	if (ret < 0) {
+		lua_error(L);
+	}
+	return ret;
+}
+
+static int moduleCall(lua_State *L, int delegate)
+{
+	char *contract;
+	char *fname;
+
+	contract = (char *)luaL_checkstring(L, 1);
+	fname = (char *)luaL_checkstring(L, 2);
+	return callFunction(L, contract, fname, lua_gettop(L) - 2, delegate);
+}
+
+static int call(lua_State *L)
+{
+	return moduleCall(L, 0);
+}
+
+static int delegateCall(lua_State *L)
+{
+	return moduleCall(L, 1);
+}
+
+/* a function of a library delegates the call to the library, whose address
+   and function name are the upvalues */
+static int libraryFunction(lua_State *L)
+{
+	char *contract = (char *)lua_tostring(L, lua_upvalueindex(1));
+	char *fname = (char *)lua_tostring(L, lua_upvalueindex(2));
+
+	return callFunction(L, contract, fname, lua_gettop(L), 1);
+}
+
+static int libraryIndex(lua_State *L)
+{
+	luaL_checkstring(L, 2);
+	lua_pushvalue(L, lua_upvalueindex(1));
+	lua_pushvalue(L, 2);
+	lua_pushcclosure(L, libraryFunction, 2);
+	return 1;
+}
+
+/* library returns a table whose functions call the library contract at the
+   address with delegatecall, so they run on the storage of the caller */
+static int library(lua_State *L)
+{
+	char *contract;
+	bc_ctx_t *exec = (bc_ctx_t *)getLuaExecContext(L);
+
+	if (exec == NULL) {
+		luaL_error(L, "cannot find execution context");
+	}
+	contract = (char *)luaL_checkstring(L, 1);
+	vm_use_gas(L, GAS_CONTRACT_CALL);
+
+	if (LuaCheckLibrary(L, exec, contract) < 0) {
+		lua_error(L);
+	}
+	lua_newtable(L);
+	lua_newtable(L);
+	lua_pushvalue(L, 1);
+	lua_pushcclosure(L, libraryIndex, 1);
+	lua_setfield(L, -2, "__index");
+	lua_pushliteral(L, "library");
+	lua_setfield(L, -2, "__metatable");
+	lua_setmetatable(L, -2);
+	return 1;
+}
+
+static int getBalance(lua_State *L)
+{
+	char *account;
+	bc_ctx_t *exec = (bc_ctx_t *)getLuaExecContext(L);
+
+	if (exec == NULL) {
+		luaL_error(L, "cannot find execution context");
+	}
+	account = (char *)luaL_optstring(L, 1, exec->contractId);
+	vm_use_gas(L, GAS_GET_BALANCE);
+
+	if (LuaGetBalance(L, exec->stateKey, account) < 0) {
+		lua_error(L);
+	}
+	return 1;
+}
+
+/* send sends an amount, which is a bignum or an integer, to the account */
+static int sendAmount(lua_State *L)
+{
+	char *account;
+	const char *amount;
+	char buf[32];
+	lua_Integer n;
+	bc_ctx_t *exec = (bc_ctx_t *)getLuaExecContext(L);
+
+	if (exec == NULL) {
+		luaL_error(L, "cannot find execution context");
+	}
+	if (exec->isQuery) {
+	    luaL_error(L, "not permitted send in query");
+	}
+	account = (char *)luaL_checkstring(L, 1);
+	if ((amount = bignum_get(L, 2)) == NULL) {
+		n = luaL_checkinteger(L, 2);
+		if (n < 0) {
+			luaL_error(L, "invalid amount");
+		}
+		snprintf(buf, sizeof(buf), "%lld", (long long)n);
+		amount = buf;
+	}
+	vm_use_gas(L, GAS_SEND);
+
+	if (LuaSendAmount(L, exec->stateKey, exec->contractId, account, (char *)amount) < 0) {
+		lua_error(L);
+	}
+	return 0;
+}
+
+static int event(lua_State *L)
+{
+	char *name;
+	char *jsonArgs;
+	sbuff_t sbuf;
+	int ret;
+	bc_ctx_t *exec = (bc_ctx_t *)getLuaExecContext(L);
+
+	if (exec == NULL) {
+		luaL_error(L, "cannot find execution context");
+	}
+	if (exec->isQuery) {
+	    luaL_error(L, "not permitted event in query");
+	}
+	name = (char *)luaL_checkstring(L, 1);
+	if (strlen(name) > EVENT_NAME_MAX) {
+		luaL_error(L, "too long event name");
+	}
+
+	lua_util_sbuf_init(&sbuf, 64);
+	jsonArgs = lua_util_get_json_from_ret(L, lua_gettop(L) - 1, &sbuf);
+	vm_use_gas(L, GAS_EVENT + GAS_EVENT_BYTE * strlen(jsonArgs));
+
+	ret = LuaEvent(L, exec->stateKey, exec->contractId, exec->txHash, name, jsonArgs);
+	free(sbuf.buf);
+	if (ret < 0) {
+		lua_error(L);
+	}
+	return 0;
+}
+
+static int selfDestruct(lua_State *L)
+{
+	bc_ctx_t *exec = (bc_ctx_t *)getLuaExecContext(L);
+
+	if (exec == NULL) {
+		luaL_error(L, "cannot find execution context");
+	}
+	if (exec->isQuery) {
+	    luaL_error(L, "not permitted selfdestruct in query");
+	}
+	if (exec->library) {
+		luaL_error(L, "not permitted selfdestruct in library");
+	}
+	vm_use_gas(L, GAS_SEND);
+
+	if (LuaSelfDestruct(L, exec->stateKey, exec->contractId, exec->txHash) < 0) {
+		lua_error(L);
+	}
+	return 0;
+}
+
+/* deploy deploys a contract code at the address made from the caller, the
+   salt and the code, and returns the address. The rest of the arguments are
+   passed to the constructor */
+static int deploy(lua_State *L)
+{
+	const char *code;
+	const char *salt;
+	size_t codeLen, saltLen;
+	char *jsonArgs;
+	sbuff_t sbuf;
+	int ret;
+	bc_ctx_t *exec = (bc_ctx_t *)getLuaExecContext(L);
+
+	if (exec == NULL) {
+		luaL_error(L, "cannot find execution context");
+	}
+	if (exec->isQuery) {
+	    luaL_error(L, "not permitted deploy in query");
+	}
+	code = luaL_checklstring(L, 1, &codeLen);
+	salt = luaL_checklstring(L, 2, &saltLen);
+	vm_use_gas(L, GAS_DEPLOY + GAS_DEPLOY_BYTE * codeLen);
+
+	lua_util_sbuf_init(&sbuf, 64);
+	jsonArgs = lua_util_get_json_from_ret(L, lua_gettop(L) - 2, &sbuf);
+
+	ret = LuaDeployContract(L, exec, (char *)code, (int)codeLen, (char *)salt, (int)saltLen, jsonArgs);
+	free(sbuf.buf);
+	if (ret < 0) {
+		lua_error(L);
+	}
+	return ret;
+}
+
+static const luaL_Reg contract_lib[] = {
+	{"call", call},
+	{"deploy", deploy},
+	{"delegatecall", delegateCall},
+	{"library", library},
+	{"balance", getBalance},
+	{"send", sendAmount},
+	{"event", event},
+	{"selfdestruct", selfDestruct},
+	{NULL, NULL}
+};
+
+int luaopen_contract(lua_State *L)
+{
+	luaL_register(L, "contract", contract_lib);
+	return 1;
+}
@@ -0,0 +1,7 @@
+#ifndef _CONTRACT_MODULE_H
+#define _CONTRACT_MODULE_H
+
+typedef struct lua_State lua_State;
+extern int luaopen_contract(lua_State *L);
+
+#endif /* _CONTRACT_MODULE_H */
@@ -0,0 +1,143 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package contract
+
+/*
+#include <stdlib.h>
+#include "vm.h"
+*/
+import "C"
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"unsafe"
+
+	"github.com/aergoio/aergo/account/key"
+	"github.com/btcsuite/btcd/btcec"
+	sha256 "github.com/minio/sha256-simd"
+	"github.com/mr-tron/base58/base58"
+	"golang.org/x/crypto/sha3"
+)
+
+// the kinds of hash and encoding, which must be matched to crypto_module.c
+const (
+	cryptoSha256 = iota
+	cryptoKeccak256
+)
+
+const (
+	cryptoHex = iota
+	cryptoBase58
+)
+
+// luaPushBytes pushes b from a function called by a contract. The memory limit
+// is lifted, since a memory error cannot be raised in go.
+func luaPushBytes(L *LState, b []byte) {
+	C.vm_memory_unlimited(L, 1)
+	defer C.vm_memory_unlimited(L, 0)
+	if len(b) == 0 {
+		empty := C.CString("")
+		C.lua_pushstring(L, empty)
+		C.free(unsafe.Pointer(empty))
+		return
+	}
+	C.lua_pushlstring(L, (*C.char)(unsafe.Pointer(&b[0])), C.size_t(len(b)))
+}
+
+func luaPushString(L *LState, s string) {
+	luaPushBytes(L, []byte(s))
+}
+
+// cryptoHash returns the hash of data as a hex string.
+func cryptoHash(data []byte, kind int) string {
+	var h []byte
+	switch kind {
+	case cryptoKeccak256:
+		keccak := sha3.NewLegacyKeccak256()
+		keccak.Write(data)
+		h = keccak.Sum(nil)
+	default:
+		sum := sha256.Sum256(data)
+		h = sum[:]
+	}
+	return hex.EncodeToString(h)
+}
+
+// cryptoVerify checks that the compact signature sig of the hash msg is signed
+// by the account of address. msg and sig are hex strings and address is a
+// base58 account address as used in transactions.
+func cryptoVerify(msg, sig, address string) (bool, error) {
+	hash, err := hex.DecodeString(msg)
+	if err != nil {
+		return false, fmt.Errorf("invalid message: %s", err.Error())
+	}
+	sign, err := hex.DecodeString(sig)
+	if err != nil {
+		return false, fmt.Errorf("invalid signature: %s", err.Error())
+	}
+	account, err := base58.Decode(address)
+	if err != nil || len(account) == 0 {
+		return false, fmt.Errorf("invalid address %s", address)
+	}
+	pubkey, _, err := btcec.RecoverCompact(btcec.S256(), sign, hash)
+	if err != nil {
+		return false, nil
+	}
+	return bytes.Equal(key.GenerateAddress(pubkey.ToECDSA()), account), nil
+}
+
+func cryptoDecode(data string, kind int) ([]byte, error) {
+	switch kind {
+	case cryptoBase58:
+		return base58.Decode(data)
+	default:
+		return hex.DecodeString(data)
+	}
+}
+
+//export LuaCryptoHash
+func LuaCryptoHash(L *LState, data *C.char, length C.size_t, kind C.int) {
+	luaPushString(L, cryptoHash(C.GoBytes(unsafe.Pointer(data), C.int(length)), int(kind)))
+}
+
+//export LuaCryptoVerify
+func LuaCryptoVerify(L *LState, msg *C.char, msgLen C.size_t, sig *C.char, sigLen C.size_t,
+	address *C.char, addressLen C.size_t) C.int {
+	valid, err := cryptoVerify(C.GoStringN(msg, C.int(msgLen)), C.GoStringN(sig, C.int(sigLen)),
+		C.GoStringN(address, C.int(addressLen)))
+	if err != nil {
+		luaPushError(L, "[Contract.LuaCryptoVerify]"+err.Error())
+		return -1
+	}
+	if valid {
+		C.lua_pushboolean(L, 1)
+	} else {
+		C.lua_pushboolean(L, 0)
+	}
+	return 0
+}
+
+//export LuaCryptoEncode
+func LuaCryptoEncode(L *LState, data *C.char, length C.size_t, kind C.int) {
+	b := C.GoBytes(unsafe.Pointer(data), C.int(length))
+	if kind == cryptoBase58 {
+		luaPushString(L, base58.Encode(b))
+	} else {
+		luaPushString(L, hex.EncodeToString(b))
+	}
+}
+
+//export LuaCryptoDecode
+func LuaCryptoDecode(L *LState, data *C.char, length C.size_t, kind C.int) C.int {
+	b, err := cryptoDecode(C.GoStringN(data, C.int(length)), int(kind))
+	if err != nil {
+		luaPushError(L, "[Contract.LuaCryptoDecode]"+err.Error())
+		return -1
+	}
+	luaPushBytes(L, b)
+	return 0
+}
@@ -0,0 +1,125 @@
+#include <string.h>
+#include <stdlib.h>
+#include "vm.h"
+#include "util.h"
+#include "_cgo_export.h"
+
+#define CRYPTO_SHA256       0
+#define CRYPTO_KECCAK256    1
+
+#define CRYPTO_HEX          0
+#define CRYPTO_BASE58       1
+
+/* base58 takes time quadratic in the length, which the gas does not cover */
+#define BASE58_MAX_LEN      1024
+
+static int hash(lua_State *L, int kind)
+{
+	char *data;
+	size_t len;
+
+	data = (char *)luaL_checklstring(L, 1, &len);
+	vm_use_gas(L, GAS_HASH + GAS_HASH_BYTE * len);
+
+	LuaCryptoHash(L, data, len, kind);
+	return 1;
+}
+
+static int sha256(lua_State *L)
+{
+	return hash(L, CRYPTO_SHA256);
+}
+
+static int keccak256(lua_State *L)
+{
+	return hash(L, CRYPTO_KECCAK256);
+}
+
+static int ecverify(lua_State *L)
+{
+	char *msg;
+	char *sig;
+	char *address;
+	size_t msg_len;
+	size_t sig_len;
+	size_t address_len;
+
+	msg = (char *)luaL_checklstring(L, 1, &msg_len);
+	sig = (char *)luaL_checklstring(L, 2, &sig_len);
+	address = (char *)luaL_checklstring(L, 3, &address_len);
+	vm_use_gas(L, GAS_ECVERIFY);
+
+	if (LuaCryptoVerify(L, msg, msg_len, sig, sig_len, address, address_len) < 0) {
+		lua_error(L);
+	}
+	return 1;
+}
+
+static int encode(lua_State *L, int kind)
+{
+	char *data;
+	size_t len;
+
+	data = (char *)luaL_checklstring(L, 1, &len);
+	if (kind == CRYPTO_BASE58 && len > BASE58_MAX_LEN) {
+		luaL_error(L, "base58 input is longer than %d bytes", BASE58_MAX_LEN);
+	}
+	vm_use_gas(L, GAS_ENCODE + GAS_ENCODE_BYTE * len);
+
+	LuaCryptoEncode(L, data, len, kind);
+	return 1;
+}
+
+static int decode(lua_State *L, int kind)
+{
+	char *data;
+	size_t len;
+
+	data = (char *)luaL_checklstring(L, 1, &len);
+	if (kind == CRYPTO_BASE58 && len > BASE58_MAX_LEN) {
+		luaL_error(L, "base58 input is longer than %d bytes", BASE58_MAX_LEN);
+	}
+	vm_use_gas(L, GAS_ENCODE + GAS_ENCODE_BYTE * len);
+
+	if (LuaCryptoDecode(L, data, len, kind) < 0) {
+		lua_error(L);
+	}
+	return 1;
+}
+
+static int hexEncode(lua_State *L)
+{
+	return encode(L, CRYPTO_HEX);
+}
+
+static int hexDecode(lua_State *L)
+{
+	return decode(L, CRYPTO_HEX);
+}
+
+static int base58Encode(lua_State *L)
+{
+	return encode(L, CRYPTO_BASE58);
+}
+
+static int base58Decode(lua_State *L)
+{
+	return decode(L, CRYPTO_BASE58);
+}
+
+static const luaL_Reg crypto_lib[] = {
+	{"sha256", sha256},
+	{"keccak256", keccak256},
+	{"ecverify", ecverify},
+	{"hexencode", hexEncode},
+	{"hexdecode", hexDecode},
+	{"base58encode", base58Encode},
+	{"base58decode", base58Decode},
+	{NULL, NULL}
+};
+
+int luaopen_crypto(lua_State *L)
+{
+	luaL_register(L, "crypto", crypto_lib);
+	return 1;
+}
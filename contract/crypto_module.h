@@ -0,0 +1,7 @@
+#ifndef _CRYPTO_MODULE_H
+#define _CRYPTO_MODULE_H
+
+typedef struct lua_State lua_State;
+extern int luaopen_crypto(lua_State *L);
+
+#endif /* _CRYPTO_MODULE_H */
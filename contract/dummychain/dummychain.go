@@ -0,0 +1,476 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+// Package dummychain runs contracts on a chain in memory for the Go tests of
+// contracts. The blocks of a dummy chain are executed as soon as they are
+// connected, without p2p, consensus nor disk.
+package dummychain
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aergoio/aergo-lib/db"
+	"github.com/aergoio/aergo/account/key"
+	"github.com/aergoio/aergo/blockchain"
+	"github.com/aergoio/aergo/cmd/aergoluac/luac"
+	"github.com/aergoio/aergo/contract"
+	"github.com/aergoio/aergo/contract/token"
+	"github.com/aergoio/aergo/state"
+	"github.com/aergoio/aergo/types"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/golang/protobuf/proto"
+	sha256 "github.com/minio/sha256-simd"
+	"github.com/mr-tron/base58/base58"
+)
+
+// BlockInterval is the time between the blocks, which is added to the
+// timestamp of every connected block.
+const BlockInterval = time.Second
+
+// DefaultGasLimit is the gas limit of a tx unless it is set by
+// TxContract.GasLimit.
+const DefaultGasLimit = contract.MaxGasLimit
+
+// sourceABI is the ABI of a contract deployed as a source, which is not
+// typed
+const sourceABI = `{"version":"0.1","language":"lua"}`
+
+var (
+	errNoReceipt   = errors.New("tx has no receipt")
+	errNotExecuted = errors.New("tx is not executed")
+	errChainOpen   = errors.New("another dummy chain is open")
+)
+
+// openChain guards contract.DB, which is replaced by the open dummy chain
+var openChain struct {
+	sync.Mutex
+	prevDB db.DB
+	open   bool
+}
+
+// DummyChain is a chain in memory. The accounts and the contracts are named
+// in the txs and the queries, and their addresses are made from the names.
+//
+// The receipts are written to the global contract.DB, which a dummy chain
+// replaces with a db in memory until it is closed. Only one dummy chain of a
+// process is open at a time.
+type DummyChain struct {
+	sdb       *state.ChainStateDB
+	blockNo   types.BlockNo
+	blockHash types.BlockID
+	timestamp int64
+	addresses map[string][]byte
+	blooms    map[types.BlockNo]types.Bloom
+}
+
+// NewDummyChain returns a dummy chain with the genesis block only. It fails
+// if another dummy chain is open, and the chain must be closed after use.
+func NewDummyChain() (*DummyChain, error) {
+	openChain.Lock()
+	defer openChain.Unlock()
+	if openChain.open {
+		return nil, errChainOpen
+	}
+	sdb := state.NewStateDB()
+	if err := sdb.InitMemory(); err != nil {
+		return nil, err
+	}
+	if err := sdb.SetGenesis(&types.Genesis{Block: &types.Block{}}); err != nil {
+		return nil, err
+	}
+	openChain.open = true
+	openChain.prevDB = contract.DB
+	contract.DB = db.NewDB(db.MemoryImpl, "")
+	return &DummyChain{
+		sdb:       sdb,
+		timestamp: time.Now().UnixNano(),
+		addresses: map[string][]byte{},
+		blooms:    map[types.BlockNo]types.Bloom{},
+	}, nil
+}
+
+// Close closes the chain and restores contract.DB, so another dummy chain can
+// be opened.
+func (bc *DummyChain) Close() {
+	openChain.Lock()
+	defer openChain.Unlock()
+	if !openChain.open {
+		return
+	}
+	contract.DB.Close()
+	contract.DB = openChain.prevDB
+	openChain.prevDB = nil
+	openChain.open = false
+	bc.sdb.Close()
+}
+
+// BlockNo returns the number of the last connected block.
+func (bc *DummyChain) BlockNo() types.BlockNo {
+	return bc.blockNo
+}
+
+// Timestamp returns the timestamp in nanoseconds of the next block.
+func (bc *DummyChain) Timestamp() int64 {
+	return bc.timestamp
+}
+
+// SetTimestamp sets the timestamp in nanoseconds of the next block.
+func (bc *DummyChain) SetTimestamp(ts int64) {
+	bc.timestamp = ts
+}
+
+// AdvanceTime moves the timestamp of the next block forward by d.
+func (bc *DummyChain) AdvanceTime(d time.Duration) {
+	bc.timestamp += int64(d)
+}
+
+// Address returns the address of the account or the contract of name, which
+// is given to contracts as base58.
+func (bc *DummyChain) Address(name string) string {
+	return base58.Encode(bc.address(name))
+}
+
+func (bc *DummyChain) address(name string) []byte {
+	if address, ok := bc.addresses[name]; ok {
+		return address
+	}
+	// an address is made from a key of name like the ones of the accounts
+	h := sha256.Sum256([]byte(name))
+	_, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), h[:])
+	address := key.GenerateAddress(pubKey.ToECDSA())
+	bc.addresses[name] = address
+	return address
+}
+
+// ConnectBlock executes txs in a new block, and applies the block state if
+// every tx is executed. A tx reverted by its contract is executed with the
+// failure in its receipt.
+func (bc *DummyChain) ConnectBlock(txs ...Tx) error {
+	blockNo := bc.blockNo + 1
+	h := sha256.New()
+	h.Write(bc.blockHash[:])
+	h.Write([]byte(strconv.FormatUint(blockNo, 10)))
+	blockHash := types.ToBlockID(h.Sum(nil))
+	bs := types.NewBlockState(types.NewBlockInfo(blockNo, blockHash, bc.blockHash))
+
+	dbTx := contract.DB.NewTx(true)
+	for _, tx := range txs {
+		if err := tx.run(bc, bs, dbTx); err != nil {
+			dbTx.Discard()
+			return err
+		}
+	}
+	dbTx.Commit()
+	if err := bc.sdb.Apply(bs); err != nil {
+		return err
+	}
+	bc.blockNo = blockNo
+	bc.blockHash = blockHash
+	bc.blooms[blockNo] = bs.EventBloom
+	bc.timestamp += int64(BlockInterval)
+	return nil
+}
+
+// Balance returns the balance of the account or the contract of name.
+func (bc *DummyChain) Balance(name string) (uint64, error) {
+	st, err := bc.sdb.GetAccountStateClone(types.ToAccountID(bc.address(name)))
+	if err != nil {
+		return 0, err
+	}
+	return st.GetBalance(), nil
+}
+
+// TokenBalance returns the balance of the account of name in the token
+// contract, which is read from its state like the GetTokenBalance RPC.
+func (bc *DummyChain) TokenBalance(tokenName, name string) (*big.Int, error) {
+	balance, err := blockchain.TokenBalance(bc.sdb.GetSnapshot(), bc.address(tokenName), bc.address(name))
+	if err != nil {
+		return nil, err
+	}
+	return types.ParseBignum(balance.Balance)
+}
+
+// Query runs the view function fname of contract with args, and returns the
+// JSON array of the return values. It runs on a snapshot of the state, so it
+// can be called while a block is connected.
+func (bc *DummyChain) Query(contractName, fname string, args ...interface{}) (string, error) {
+	ci, err := callInfo(fname, args)
+	if err != nil {
+		return "", err
+	}
+	ret, err := contract.Query(bc.address(contractName), bc.sdb.GetSnapshot(), ci)
+	return string(ret), err
+}
+
+// GetData returns the value stored by contract with system.setItem under
+// key, which is a JSON value.
+func (bc *DummyChain) GetData(contractName, key string) ([]byte, error) {
+	address := bc.address(contractName)
+	contractState, err := bc.sdb.OpenContractStateAccount(types.ToAccountID(address))
+	if err != nil {
+		return nil, err
+	}
+	return contractState.GetData([]byte(base58.Encode(address) + "_" + key))
+}
+
+// GetStateVar returns the value of a state variable of contract, or of the
+// entry sub of a map or an array state variable.
+func (bc *DummyChain) GetStateVar(contractName, name, sub string) ([]byte, error) {
+	address := bc.address(contractName)
+	contractState, err := bc.sdb.OpenContractStateAccount(types.ToAccountID(address))
+	if err != nil {
+		return nil, err
+	}
+	return contractState.GetData(types.StateVarKey(address, name, sub))
+}
+
+// EventBloom returns the bloom of the events emitted in the block of blockNo,
+// as put into the header of an executed block.
+func (bc *DummyChain) EventBloom(blockNo types.BlockNo) *types.Bloom {
+	bloom, ok := bc.blooms[blockNo]
+	if !ok {
+		return nil
+	}
+	return &bloom
+}
+
+// Receipt returns the receipt of tx, which is executed in a connected block.
+func (bc *DummyChain) Receipt(tx *TxContract) (*types.Receipt, error) {
+	if tx.hash == nil {
+		return nil, errNotExecuted
+	}
+	receipt, err := contract.GetReceipt(tx.hash)
+	if err != nil {
+		return nil, errNoReceipt
+	}
+	return receipt, nil
+}
+
+// Tx is a tx in a block of a dummy chain.
+type Tx interface {
+	run(bc *DummyChain, bs *types.BlockState, dbTx db.Transaction) error
+}
+
+// TxAccount funds an account, which is made if it does not exist. It is not
+// a tx of the chain, but its state is changed in the block.
+type TxAccount struct {
+	name    string
+	balance uint64
+}
+
+// NewTxAccount returns a tx which adds balance to the account of name.
+func NewTxAccount(name string, balance uint64) *TxAccount {
+	return &TxAccount{name: name, balance: balance}
+}
+
+func (tx *TxAccount) run(bc *DummyChain, bs *types.BlockState, dbTx db.Transaction) error {
+	aid := types.ToAccountID(bc.address(tx.name))
+	before, err := bc.sdb.GetBlockAccountClone(bs, aid)
+	if err != nil {
+		return err
+	}
+	after := types.Clone(*before).(types.State)
+	after.Balance += tx.balance
+	bs.PutAccount(aid, before, &after)
+	return nil
+}
+
+// TxContract is an unsigned tx of the chain, which transfers an amount,
+// deploys a contract, calls a function of a contract or runs a batch. Its
+// nonce is the next one of the sender when its block is connected.
+type TxContract struct {
+	sender    string
+	recipient string
+	deploy    bool
+	upgrade   bool
+	amount    uint64
+	gasLimit  uint64
+	payload   []byte
+	ops       []*BatchOp
+	err       error
+	hash      []byte
+}
+
+// BatchOp is an operation of a batch tx, which transfers an amount to an
+// account or a contract, and calls a function of the contract.
+type BatchOp struct {
+	recipient string
+	amount    uint64
+	payload   []byte
+	err       error
+}
+
+// NewBatchTransfer returns an operation which transfers amount to recipient.
+func NewBatchTransfer(recipient string, amount uint64) *BatchOp {
+	return &BatchOp{recipient: recipient, amount: amount}
+}
+
+// NewBatchCall returns an operation which calls the function fname of
+// contract with args.
+func NewBatchCall(contractName string, amount uint64, fname string, args ...interface{}) *BatchOp {
+	op := &BatchOp{recipient: contractName, amount: amount}
+	op.payload, op.err = callInfo(fname, args)
+	return op
+}
+
+// NewTxBatch returns a tx which runs ops in order. Either every operation
+// succeeds or the tx is reverted, and the operations share its gas limit.
+func NewTxBatch(sender string, ops ...*BatchOp) *TxContract {
+	tx := &TxContract{sender: sender, ops: ops, gasLimit: DefaultGasLimit}
+	for _, op := range ops {
+		if op.err != nil {
+			tx.err = op.err
+		}
+	}
+	return tx
+}
+
+// NewTxTransfer returns a tx which transfers amount from sender to recipient.
+func NewTxTransfer(sender, recipient string, amount uint64) *TxContract {
+	return &TxContract{sender: sender, recipient: recipient, amount: amount, gasLimit: DefaultGasLimit}
+}
+
+// NewTxDeploy returns a tx which deploys code, the output of aergoluac, as
+// the contract of name. args are given to the constructor.
+func NewTxDeploy(sender, name string, amount uint64, code []byte, args ...interface{}) *TxContract {
+	tx := &TxContract{sender: sender, recipient: name, deploy: true, amount: amount, gasLimit: DefaultGasLimit}
+	var jsonArgs []byte
+	if len(args) > 0 {
+		jsonArgs, tx.err = json.Marshal(args)
+	}
+	tx.payload = types.EncodeDeployPayload(code, jsonArgs)
+	return tx
+}
+
+// NewTxDeploySource returns a tx which deploys the Lua source src as the
+// contract of name. The contract has an ABI which is not typed.
+func NewTxDeploySource(sender, name string, amount uint64, src string, args ...interface{}) *TxContract {
+	return NewTxDeploy(sender, name, amount, sourceCode(src), args...)
+}
+
+// sourceCode returns the code of a contract of the Lua source src, which is
+// compiled when it is loaded.
+func sourceCode(src string) []byte {
+	code := make([]byte, 4, 4+len(src)+len(sourceABI))
+	binary.LittleEndian.PutUint32(code, uint32(len(src)))
+	code = append(code, src...)
+	return append(code, sourceABI...)
+}
+
+// NewTxDeployCompiled returns a tx which compiles the Lua source src with
+// aergoluac and deploys it as the contract of name. The contract has the ABI
+// generated by aergoluac.
+func NewTxDeployCompiled(sender, name string, amount uint64, src string, args ...interface{}) *TxContract {
+	code, err := luac.Payload([]byte(src))
+	if err != nil {
+		return &TxContract{sender: sender, recipient: name, deploy: true, err: err}
+	}
+	return NewTxDeploy(sender, name, amount, code, args...)
+}
+
+// NewTxUpgradeCompiled returns a tx which compiles the Lua source src with
+// aergoluac and upgrades the contract of name to it.
+func NewTxUpgradeCompiled(sender, contractName string, src string) *TxContract {
+	tx := &TxContract{sender: sender, recipient: contractName, upgrade: true, gasLimit: DefaultGasLimit}
+	tx.payload, tx.err = luac.Payload([]byte(src))
+	return tx
+}
+
+// NewTxDeployToken returns a tx which deploys a token of the token standard
+// as the contract of name. The supply is given to the sender.
+func NewTxDeployToken(sender, name, tokenName, symbol string, decimals uint32, supply *big.Int) *TxContract {
+	tx := &TxContract{sender: sender, recipient: name, deploy: true, gasLimit: DefaultGasLimit}
+	tx.payload, tx.err = token.DeployPayload(tokenName, symbol, decimals, supply)
+	return tx
+}
+
+// NewTxCall returns a tx which calls the function fname of contract with
+// args.
+func NewTxCall(sender, contractName string, amount uint64, fname string, args ...interface{}) *TxContract {
+	tx := &TxContract{sender: sender, recipient: contractName, amount: amount, gasLimit: DefaultGasLimit}
+	tx.payload, tx.err = callInfo(fname, args)
+	return tx
+}
+
+// GasLimit sets the gas limit of tx, which is DefaultGasLimit unless set.
+func (tx *TxContract) GasLimit(limit uint64) *TxContract {
+	tx.gasLimit = limit
+	return tx
+}
+
+// Hash returns the hash of tx, which is known after its block is connected.
+func (tx *TxContract) Hash() []byte {
+	return tx.hash
+}
+
+func (tx *TxContract) run(bc *DummyChain, bs *types.BlockState, dbTx db.Transaction) error {
+	if tx.err != nil {
+		return tx.err
+	}
+	sender := bc.address(tx.sender)
+	senderState, err := bc.sdb.GetBlockAccountClone(bs, types.ToAccountID(sender))
+	if err != nil {
+		return err
+	}
+	body := &types.TxBody{
+		Nonce:   senderState.GetNonce() + 1,
+		Account: sender,
+		Amount:  tx.amount,
+		Payload: tx.payload,
+		Limit:   tx.gasLimit,
+	}
+	if tx.deploy {
+		if _, ok := bc.addresses[tx.recipient]; ok {
+			return fmt.Errorf("%s already exists", tx.recipient)
+		}
+	} else if tx.ops != nil {
+		body.Type = types.TxType_BATCH
+		if body.Payload, err = bc.batch(tx.ops); err != nil {
+			return err
+		}
+	} else {
+		body.Recipient = bc.address(tx.recipient)
+		if tx.upgrade {
+			body.Type = types.TxType_UPGRADE
+		}
+	}
+	chainTx := &types.Tx{Body: body}
+	chainTx.Hash = chainTx.CalculateTxHash()
+	if err := blockchain.ExecuteTx(bc.sdb, bs, chainTx, dbTx, bs.BlockNo, bc.timestamp); err != nil {
+		return err
+	}
+	if tx.deploy {
+		bc.addresses[tx.recipient] = types.ContractAddress(body.Account, body.Nonce)
+	}
+	tx.hash = chainTx.Hash
+	return nil
+}
+
+// batch returns the payload of a batch tx running ops
+func (bc *DummyChain) batch(ops []*BatchOp) ([]byte, error) {
+	var batch types.Batch
+	for _, op := range ops {
+		batch.Operations = append(batch.Operations, &types.BatchOperation{
+			Recipient: bc.address(op.recipient),
+			Amount:    op.amount,
+			Payload:   op.payload,
+		})
+	}
+	return proto.Marshal(&batch)
+}
+
+func callInfo(fname string, args []interface{}) ([]byte, error) {
+	if args == nil {
+		args = []interface{}{}
+	}
+	return json.Marshal(&types.CallInfo{Name: fname, Args: args})
+}
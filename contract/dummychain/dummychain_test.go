@@ -0,0 +1,661 @@
+package dummychain
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aergoio/aergo/blockchain"
+	"github.com/aergoio/aergo/types"
+	"github.com/mr-tron/base58/base58"
+	"github.com/stretchr/testify/assert"
+)
+
+const counterSource = `
+state.var { count = state.value(), last = state.value() }
+function constructor(start)
+	count:set(start)
+end
+function inc(n)
+	count:set(count:get() + n)
+	last:set(system.getTimestamp())
+	return count:get()
+end
+function get()
+	return count:get(), system.getSender()
+end
+function fail()
+	count:set(0)
+	error("failed")
+end
+abi.register(inc)
+abi.register(get)
+abi.register(fail)
+abi.payable(inc)`
+
+func TestDummyChain(t *testing.T) {
+	bc, err := NewDummyChain()
+	assert.NoError(t, err)
+	defer bc.Close()
+
+	assert.NoError(t, bc.ConnectBlock(
+		NewTxAccount("alice", 1000),
+		NewTxAccount("bob", 0),
+	))
+	assert.NoError(t, bc.ConnectBlock(NewTxTransfer("alice", "bob", 100)))
+	balance, _ := bc.Balance("bob")
+	assert.Equal(t, uint64(100), balance)
+	// the addresses are as long as the ones of the chain
+	assert.Len(t, bc.address("alice"), 20)
+
+	deploy := NewTxDeploySource("alice", "counter", 0, counterSource, 10)
+	assert.NoError(t, bc.ConnectBlock(deploy))
+	receipt, err := bc.Receipt(deploy)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATED", receipt.Status)
+	assert.Equal(t, uint64(3), bc.BlockNo())
+
+	bc.AdvanceTime(time.Hour)
+	ts := bc.Timestamp()
+	inc := NewTxCall("bob", "counter", 10, "inc", 5)
+	fail := NewTxCall("bob", "counter", 0, "fail")
+	assert.NoError(t, bc.ConnectBlock(inc, fail))
+	receipt, _ = bc.Receipt(inc)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	assert.Equal(t, "[15]", receipt.Ret)
+	receipt, _ = bc.Receipt(fail)
+	assert.Contains(t, receipt.Status, "failed")
+
+	ret, err := bc.Query("counter", "get")
+	assert.NoError(t, err)
+	assert.Equal(t, `[15,"`+bc.Address("counter")+`"]`, ret)
+	last, err := bc.GetStateVar("counter", "last", "")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, last)
+	balance, _ = bc.Balance("counter")
+	assert.Equal(t, uint64(10), balance)
+	assert.Equal(t, ts+int64(BlockInterval), bc.Timestamp())
+
+	assert.Error(t, bc.ConnectBlock(NewTxDeploySource("alice", "counter", 0, counterSource)))
+	_, err = bc.Receipt(NewTxCall("bob", "counter", 0, "get"))
+	assert.Error(t, err)
+
+	// the gas limit of a tx is enforced
+	outOfGas := NewTxCall("bob", "counter", 0, "inc", 1).GasLimit(1)
+	assert.NoError(t, bc.ConnectBlock(outOfGas))
+	receipt, _ = bc.Receipt(outOfGas)
+	assert.Equal(t, "out of gas", receipt.Status)
+	assert.Equal(t, uint64(1), receipt.GasUsed)
+	assert.Equal(t, types.ErrNoGasLimit, bc.ConnectBlock(NewTxCall("bob", "counter", 0, "inc", 1).GasLimit(0)))
+
+	// contract.DB is used by one chain at a time
+	_, err = NewDummyChain()
+	assert.Error(t, err)
+}
+
+func TestDummyChainParallelQueries(t *testing.T) {
+	bc, err := NewDummyChain()
+	assert.NoError(t, err)
+	defer bc.Close()
+	assert.NoError(t, bc.ConnectBlock(NewTxAccount("alice", 1000)))
+	assert.NoError(t, bc.ConnectBlock(NewTxDeploySource("alice", "counter", 0, counterSource, 0)))
+
+	// the queries run on snapshots while the blocks are connected
+	const blocks = 10
+	done := make(chan error)
+	for i := 0; i < 8; i++ {
+		go func() {
+			for j := 0; j < blocks; j++ {
+				if _, err := bc.Query("counter", "get"); err != nil {
+					done <- err
+					return
+				}
+			}
+			done <- nil
+		}()
+	}
+	for i := 1; i <= blocks; i++ {
+		assert.NoError(t, bc.ConnectBlock(NewTxCall("alice", "counter", 0, "inc", 1)))
+	}
+	for i := 0; i < 8; i++ {
+		assert.NoError(t, <-done)
+	}
+	ret, err := bc.Query("counter", "get")
+	assert.NoError(t, err)
+	assert.Equal(t, `[10,"`+bc.Address("counter")+`"]`, ret)
+}
+
+func TestDummyChainBatch(t *testing.T) {
+	bc, err := NewDummyChain()
+	assert.NoError(t, err)
+	defer bc.Close()
+	assert.NoError(t, bc.ConnectBlock(
+		NewTxAccount("alice", 1000),
+		NewTxAccount("bob", 0),
+	))
+	assert.NoError(t, bc.ConnectBlock(NewTxDeploySource("alice", "counter", 0, counterSource, 0)))
+	inc := NewTxCall("alice", "counter", 0, "inc", 1)
+	assert.NoError(t, bc.ConnectBlock(inc))
+	receipt, _ := bc.Receipt(inc)
+	incGas := receipt.GasUsed
+	assert.NotZero(t, incGas)
+
+	results := func(receipt *types.Receipt) []*blockchain.BatchResult {
+		var results []*blockchain.BatchResult
+		assert.NoError(t, json.Unmarshal([]byte(receipt.Ret), &results))
+		return results
+	}
+
+	// the operations share the gas of the tx
+	batch := NewTxBatch("alice",
+		NewBatchTransfer("bob", 10),
+		NewBatchCall("counter", 0, "inc", 1),
+		NewBatchCall("counter", 0, "inc", 1),
+	)
+	assert.NoError(t, bc.ConnectBlock(batch))
+	receipt, err = bc.Receipt(batch)
+	assert.NoError(t, err)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	assert.Equal(t, 2*incGas, receipt.GasUsed)
+	rs := results(receipt)
+	assert.Len(t, rs, 3)
+	assert.Equal(t, "[3]", string(rs[2].Ret))
+	balance, _ := bc.Balance("bob")
+	assert.Equal(t, uint64(10), balance)
+
+	// a failing operation reverts the whole batch, and its status tells why
+	batch = NewTxBatch("alice",
+		NewBatchTransfer("bob", 10),
+		NewBatchCall("counter", 0, "inc", 1),
+		NewBatchCall("counter", 0, "fail"),
+		NewBatchTransfer("bob", 10),
+	)
+	assert.NoError(t, bc.ConnectBlock(batch))
+	receipt, _ = bc.Receipt(batch)
+	assert.Contains(t, receipt.Status, "REVERTED: operation 2")
+	assert.True(t, receipt.GasUsed > incGas)
+	rs = results(receipt)
+	assert.Len(t, rs, 3)
+	assert.Equal(t, "SUCCESS", rs[0].Status)
+	assert.Equal(t, "SUCCESS", rs[1].Status)
+	assert.Contains(t, rs[2].Status, "failed")
+	balance, _ = bc.Balance("bob")
+	assert.Equal(t, uint64(10), balance)
+	ret, err := bc.Query("counter", "get")
+	assert.NoError(t, err)
+	assert.Equal(t, `[3,"`+bc.Address("counter")+`"]`, ret)
+
+	// the gas limit of the tx is shared by the operations
+	batch = NewTxBatch("alice",
+		NewBatchCall("counter", 0, "inc", 1),
+		NewBatchCall("counter", 0, "inc", 1),
+	).GasLimit(incGas + 1)
+	assert.NoError(t, bc.ConnectBlock(batch))
+	receipt, _ = bc.Receipt(batch)
+	assert.Contains(t, receipt.Status, "out of gas")
+	assert.Equal(t, incGas+1, receipt.GasUsed)
+	rs = results(receipt)
+	assert.Equal(t, "SUCCESS", rs[0].Status)
+	assert.Equal(t, "out of gas", rs[1].Status)
+}
+
+const callSource = `
+state.var { value = state.value() }
+function set(v)
+	value:set(v)
+	return system.getSender(), system.getAmount()
+end
+function get()
+	return value:get()
+end
+function callSet(callee, v)
+	return contract.call(callee, "set", v)
+end
+function delegateSet(callee, v)
+	return contract.delegatecall(callee, "set", v)
+end
+function recurse(n)
+	if n == 0 then
+		return 0
+	end
+	return contract.call(system.getContractID(), "recurse", n - 1) + 1
+end
+function failing()
+	value:set(-1)
+	error("callee failed")
+end
+function catchFailing(callee)
+	value:set(99)
+	return pcall(contract.call, callee, "failing")
+end
+abi.register(set)
+abi.register(get)
+abi.register(callSet)
+abi.register(delegateSet)
+abi.register(recurse)
+abi.register(failing)
+abi.register(catchFailing)
+abi.payable(callSet)
+abi.payable(delegateSet)`
+
+func TestDummyChainCall(t *testing.T) {
+	bc, err := NewDummyChain()
+	assert.NoError(t, err)
+	defer bc.Close()
+	assert.NoError(t, bc.ConnectBlock(NewTxAccount("alice", 1000)))
+	assert.NoError(t, bc.ConnectBlock(
+		NewTxDeploySource("alice", "caller", 0, callSource),
+		NewTxDeploySource("alice", "callee", 0, callSource),
+	))
+	get := func(name string) string {
+		ret, err := bc.Query(name, "get")
+		assert.NoError(t, err)
+		return ret
+	}
+
+	// a call runs on the storage of the callee, sent by the caller with no
+	// amount
+	call := NewTxCall("alice", "caller", 10, "callSet", bc.Address("callee"), 1)
+	assert.NoError(t, bc.ConnectBlock(call))
+	receipt, _ := bc.Receipt(call)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	assert.Equal(t, `["`+bc.Address("caller")+`",{"_bignum":"0"}]`, receipt.Ret)
+	assert.Equal(t, "[1]", get("callee"))
+	balance, _ := bc.Balance("caller")
+	assert.Equal(t, uint64(10), balance)
+	balance, _ = bc.Balance("callee")
+	assert.Equal(t, uint64(0), balance)
+
+	// a delegate call runs on the storage of the caller, with the sender and
+	// the amount of the tx
+	delegate := NewTxCall("alice", "caller", 10, "delegateSet", bc.Address("callee"), 2)
+	assert.NoError(t, bc.ConnectBlock(delegate))
+	receipt, _ = bc.Receipt(delegate)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	assert.Equal(t, `["`+bc.Address("alice")+`",{"_bignum":"10"}]`, receipt.Ret)
+	assert.Equal(t, "[1]", get("callee"))
+	assert.Equal(t, "[2]", get("caller"))
+	balance, _ = bc.Balance("caller")
+	assert.Equal(t, uint64(20), balance)
+
+	// the calls are nested up to the maximum depth
+	deep := NewTxCall("alice", "caller", 0, "recurse", 16)
+	tooDeep := NewTxCall("alice", "caller", 0, "recurse", 17)
+	assert.NoError(t, bc.ConnectBlock(deep, tooDeep))
+	receipt, _ = bc.Receipt(deep)
+	assert.Equal(t, "[16]", receipt.Ret)
+	receipt, _ = bc.Receipt(tooDeep)
+	assert.Contains(t, receipt.Status, "exceeded the maximum call depth")
+
+	// a failed call reverts the whole tx, even if the caller catches it
+	catch := NewTxCall("alice", "caller", 0, "catchFailing", bc.Address("callee"))
+	assert.NoError(t, bc.ConnectBlock(catch))
+	receipt, _ = bc.Receipt(catch)
+	assert.Contains(t, receipt.Status, "callee failed")
+	assert.Equal(t, "[1]", get("callee"))
+	assert.Equal(t, "[2]", get("caller"))
+}
+
+const walletSource = `
+function balance(account)
+	return contract.balance(account)
+end
+function send(to, amount)
+	contract.send(to, amount)
+end
+function sendAll(to)
+	contract.send(to, contract.balance())
+end
+function deposit()
+	return system.getAmount()
+end
+abi.register(balance)
+abi.register(send)
+abi.register(sendAll)
+abi.register(deposit)
+abi.payable(deposit)`
+
+func TestDummyChainBalance(t *testing.T) {
+	bc, err := NewDummyChain()
+	assert.NoError(t, err)
+	defer bc.Close()
+	assert.NoError(t, bc.ConnectBlock(
+		NewTxAccount("alice", 1000),
+		NewTxAccount("bob", 0),
+		NewTxAccount("rich", 1<<63+1),
+	))
+	assert.NoError(t, bc.ConnectBlock(NewTxDeploySource("alice", "wallet", 0, walletSource)))
+
+	deposit := NewTxCall("alice", "wallet", 30, "deposit")
+	assert.NoError(t, bc.ConnectBlock(deposit))
+	receipt, _ := bc.Receipt(deposit)
+	assert.Equal(t, `[{"_bignum":"30"}]`, receipt.Ret)
+	ret, err := bc.Query("wallet", "balance")
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"_bignum":"30"}]`, ret)
+
+	// a balance is not wrapped to a negative number
+	ret, err = bc.Query("wallet", "balance", bc.Address("rich"))
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"_bignum":"9223372036854775809"}]`, ret)
+
+	send := NewTxCall("alice", "wallet", 0, "send", bc.Address("bob"), 10)
+	tooMuch := NewTxCall("alice", "wallet", 0, "send", bc.Address("bob"), 21)
+	negative := NewTxCall("alice", "wallet", 0, "send", bc.Address("bob"), -1)
+	assert.NoError(t, bc.ConnectBlock(send, tooMuch, negative))
+	receipt, _ = bc.Receipt(send)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	receipt, _ = bc.Receipt(tooMuch)
+	assert.Contains(t, receipt.Status, "not enough balance")
+	receipt, _ = bc.Receipt(negative)
+	assert.Contains(t, receipt.Status, "invalid amount")
+	balance, _ := bc.Balance("bob")
+	assert.Equal(t, uint64(10), balance)
+	balance, _ = bc.Balance("wallet")
+	assert.Equal(t, uint64(20), balance)
+
+	// a bignum is sent as well
+	sendAll := NewTxCall("alice", "wallet", 0, "sendAll", bc.Address("bob"))
+	assert.NoError(t, bc.ConnectBlock(sendAll))
+	receipt, _ = bc.Receipt(sendAll)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	balance, _ = bc.Balance("bob")
+	assert.Equal(t, uint64(30), balance)
+	balance, _ = bc.Balance("wallet")
+	assert.Equal(t, uint64(0), balance)
+}
+
+const eventSource = `
+function emit(v)
+	contract.event("emitted", v, "x")
+end
+function emitFail(v)
+	contract.event("emitted", v)
+	error("failed")
+end
+abi.register(emit)
+abi.register(emitFail)`
+
+func TestDummyChainEvent(t *testing.T) {
+	bc, err := NewDummyChain()
+	assert.NoError(t, err)
+	defer bc.Close()
+	assert.NoError(t, bc.ConnectBlock(NewTxAccount("alice", 1000)))
+	assert.NoError(t, bc.ConnectBlock(NewTxDeploySource("alice", "events", 0, eventSource)))
+
+	emit := NewTxCall("alice", "events", 0, "emit", 7)
+	assert.NoError(t, bc.ConnectBlock(emit))
+	receipt, _ := bc.Receipt(emit)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	assert.Len(t, receipt.Events, 1)
+	ev := receipt.Events[0]
+	assert.Equal(t, "emitted", ev.EventName)
+	assert.Equal(t, `[7,"x"]`, ev.JsonArgs)
+	assert.Equal(t, bc.address("events"), ev.ContractAddress)
+	assert.Equal(t, emit.Hash(), ev.TxHash)
+
+	bloom := bc.EventBloom(bc.BlockNo())
+	if assert.NotNil(t, bloom) {
+		assert.True(t, bloom.MatchFilter(&types.FilterInfo{ContractAddress: bc.address("events"), EventName: "emitted"}))
+		assert.False(t, bloom.MatchFilter(&types.FilterInfo{ContractAddress: bc.address("events"), EventName: "other"}))
+	}
+
+	// the events of a reverted tx are not kept
+	fail := NewTxCall("alice", "events", 0, "emitFail", 8)
+	assert.NoError(t, bc.ConnectBlock(fail))
+	receipt, _ = bc.Receipt(fail)
+	assert.Empty(t, receipt.Events)
+	assert.Equal(t, types.Bloom{}, *bc.EventBloom(bc.BlockNo()))
+}
+
+const mathLibrarySource = `
+function add(a, b)
+	return a + b
+end
+function record(key, value)
+	system.setItem(key, value)
+end
+abi.register(add)
+abi.register(record)
+abi.library()`
+
+const libraryUserSource = `
+function constructor(lib)
+	system.setItem("lib", lib)
+end
+function sum(a, b)
+	local m = contract.library(system.getItem("lib"))
+	m.record("sum", a + b)
+	return m.add(a, b)
+end
+function last()
+	return system.getItem("sum")
+end
+function useContract(address)
+	return contract.library(address).add(1, 2)
+end
+abi.register(sum)
+abi.register(last)
+abi.register(useContract)`
+
+func TestDummyChainLibrary(t *testing.T) {
+	bc, err := NewDummyChain()
+	assert.NoError(t, err)
+	defer bc.Close()
+	assert.NoError(t, bc.ConnectBlock(NewTxAccount("alice", 1000)))
+	assert.NoError(t, bc.ConnectBlock(
+		NewTxDeployCompiled("alice", "math", 0, mathLibrarySource),
+		NewTxDeploySource("alice", "counter", 0, counterSource, 0),
+	))
+	assert.NoError(t, bc.ConnectBlock(NewTxDeployCompiled("alice", "user", 0, libraryUserSource, bc.Address("math"))))
+
+	sum := NewTxCall("alice", "user", 0, "sum", 1, 2)
+	notLibrary := NewTxCall("alice", "user", 0, "useContract", bc.Address("counter"))
+	assert.NoError(t, bc.ConnectBlock(sum, notLibrary))
+	receipt, _ := bc.Receipt(sum)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	assert.Equal(t, "[3]", receipt.Ret)
+	receipt, _ = bc.Receipt(notLibrary)
+	assert.Contains(t, receipt.Status, "is not a library")
+
+	// the library runs on the storage of its caller
+	ret, err := bc.Query("user", "last")
+	assert.NoError(t, err)
+	assert.Equal(t, "[3]", ret)
+	data, err := bc.GetData("math", "sum")
+	assert.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+const destructLibrarySource = `
+function destruct()
+	contract.selfdestruct()
+end
+abi.register(destruct)
+abi.library()`
+
+const destructUserSource = `
+function destruct(lib)
+	contract.library(lib).destruct()
+end
+abi.register(destruct)`
+
+func TestDummyChainLibraryLifecycle(t *testing.T) {
+	bc, err := NewDummyChain()
+	assert.NoError(t, err)
+	defer bc.Close()
+	assert.NoError(t, bc.ConnectBlock(NewTxAccount("alice", 1000)))
+
+	// a library cannot be upgradable
+	upgradable := NewTxDeployCompiled("alice", "upgradable", 0, mathLibrarySource+"\nabi.upgradable()")
+	assert.NoError(t, bc.ConnectBlock(upgradable))
+	receipt, _ := bc.Receipt(upgradable)
+	assert.Contains(t, receipt.Status, "library cannot be upgradable")
+
+	// a library is never destructed, by itself or by its callers
+	assert.NoError(t, bc.ConnectBlock(
+		NewTxDeployCompiled("alice", "lib", 0, destructLibrarySource),
+		NewTxDeploySource("alice", "user", 0, destructUserSource),
+	))
+	direct := NewTxCall("alice", "lib", 0, "destruct")
+	delegated := NewTxCall("alice", "user", 0, "destruct", bc.Address("lib"))
+	assert.NoError(t, bc.ConnectBlock(direct, delegated))
+	receipt, _ = bc.Receipt(direct)
+	assert.Contains(t, receipt.Status, "library cannot be destructed")
+	receipt, _ = bc.Receipt(delegated)
+	assert.Contains(t, receipt.Status, "not permitted selfdestruct in library")
+}
+
+const versionSource = `
+function version()
+	return %d
+end
+function destruct()
+	contract.selfdestruct()
+end
+abi.register(version)
+abi.register(destruct)
+abi.upgradable()`
+
+func TestDummyChainLifecycle(t *testing.T) {
+	bc, err := NewDummyChain()
+	assert.NoError(t, err)
+	defer bc.Close()
+	assert.NoError(t, bc.ConnectBlock(NewTxAccount("alice", 1000), NewTxAccount("bob", 1000)))
+	assert.NoError(t, bc.ConnectBlock(NewTxDeployCompiled("alice", "app", 50, fmt.Sprintf(versionSource, 1))))
+	version := func() string {
+		ret, err := bc.Query("app", "version")
+		assert.NoError(t, err)
+		return ret
+	}
+	assert.Equal(t, "[1]", version())
+
+	// only the owner upgrades the contract
+	notOwner := NewTxUpgradeCompiled("bob", "app", fmt.Sprintf(versionSource, 2))
+	assert.NoError(t, bc.ConnectBlock(notOwner))
+	receipt, _ := bc.Receipt(notOwner)
+	assert.Equal(t, "only the owner can upgrade the contract", receipt.Status)
+	assert.Equal(t, "[1]", version())
+
+	upgrade := NewTxUpgradeCompiled("alice", "app", fmt.Sprintf(versionSource, 2))
+	assert.NoError(t, bc.ConnectBlock(upgrade))
+	receipt, _ = bc.Receipt(upgrade)
+	assert.Equal(t, "UPGRADED", receipt.Status)
+	if assert.Len(t, receipt.Events, 1) {
+		assert.Equal(t, "upgrade", receipt.Events[0].EventName)
+		assert.Contains(t, receipt.Events[0].JsonArgs, "[2,")
+	}
+	assert.Equal(t, "[2]", version())
+
+	// the balance goes to the owner, and the code is never run again
+	destruct := NewTxCall("bob", "app", 0, "destruct")
+	assert.NoError(t, bc.ConnectBlock(destruct))
+	receipt, _ = bc.Receipt(destruct)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	if assert.Len(t, receipt.Events, 1) {
+		assert.Equal(t, `["`+bc.Address("alice")+`",50]`, receipt.Events[0].JsonArgs)
+	}
+	balance, _ := bc.Balance("app")
+	assert.Equal(t, uint64(0), balance)
+	_, err = bc.Query("app", "version")
+	assert.Error(t, err)
+	call := NewTxCall("alice", "app", 0, "version")
+	assert.NoError(t, bc.ConnectBlock(call))
+	receipt, _ = bc.Receipt(call)
+	assert.Contains(t, receipt.Status, "cannot find contract")
+
+	// a destructed contract is not upgraded back
+	again := NewTxUpgradeCompiled("alice", "app", fmt.Sprintf(versionSource, 3))
+	assert.NoError(t, bc.ConnectBlock(again))
+	receipt, _ = bc.Receipt(again)
+	assert.NotEqual(t, "UPGRADED", receipt.Status)
+}
+
+const memorySource = `
+function alloc()
+	return #string.rep("x", 12 * 1024 * 1024)
+end
+function hold(callee)
+	local s = string.rep("x", 12 * 1024 * 1024)
+	return contract.call(callee, "alloc") + #s
+end
+abi.register(alloc)
+abi.register(hold)`
+
+func TestDummyChainMemory(t *testing.T) {
+	bc, err := NewDummyChain()
+	assert.NoError(t, err)
+	defer bc.Close()
+	assert.NoError(t, bc.ConnectBlock(NewTxAccount("alice", 1000)))
+	assert.NoError(t, bc.ConnectBlock(
+		NewTxDeploySource("alice", "caller", 0, memorySource),
+		NewTxDeploySource("alice", "callee", 0, memorySource),
+	))
+
+	// the memory costs gas by its size class
+	alloc := NewTxCall("alice", "callee", 0, "alloc")
+	assert.NoError(t, bc.ConnectBlock(alloc))
+	receipt, _ := bc.Receipt(alloc)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	assert.True(t, receipt.GasUsed > 16*1024, "gas used %d", receipt.GasUsed)
+
+	// the callee has the memory left by the caller
+	hold := NewTxCall("alice", "caller", 0, "hold", bc.Address("callee"))
+	assert.NoError(t, bc.ConnectBlock(hold))
+	receipt, _ = bc.Receipt(hold)
+	assert.Contains(t, receipt.Status, "memory limit exceeded")
+}
+
+const factorySource = `
+local function fromhex(s)
+	return (s:gsub("..", function(h) return string.char(tonumber(h, 16)) end))
+end
+function make(code, salt, start)
+	return contract.deploy(fromhex(code), salt, start)
+end
+function view()
+	return contract.deploy("", "salt")
+end
+abi.register(make)
+abi.register(view)`
+
+func TestDummyChainDeploy(t *testing.T) {
+	bc, err := NewDummyChain()
+	assert.NoError(t, err)
+	defer bc.Close()
+	assert.NoError(t, bc.ConnectBlock(NewTxAccount("alice", 1000)))
+	assert.NoError(t, bc.ConnectBlock(NewTxDeploySource("alice", "factory", 0, factorySource)))
+
+	// the contract is deployed at the address predicted from the salt
+	code := sourceCode(counterSource)
+	deploy := NewTxCall("alice", "factory", 0, "make", hex.EncodeToString(code), "s1", 7)
+	assert.NoError(t, bc.ConnectBlock(deploy))
+	receipt, _ := bc.Receipt(deploy)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	address := types.SaltedContractAddress(bc.address("factory"), []byte("s1"), types.CodeHash(code))
+	assert.Equal(t, `["`+base58.Encode(address)+`"]`, receipt.Ret)
+	bc.addresses["child"] = address
+	ret, err := bc.Query("child", "get")
+	assert.NoError(t, err)
+	assert.Contains(t, ret, "[7,")
+	inc := NewTxCall("alice", "child", 0, "inc", 1)
+	assert.NoError(t, bc.ConnectBlock(inc))
+	receipt, _ = bc.Receipt(inc)
+	assert.Equal(t, "[8]", receipt.Ret)
+
+	// an address is deployed once
+	again := NewTxCall("alice", "factory", 0, "make", hex.EncodeToString(code), "s1", 0)
+	other := NewTxCall("alice", "factory", 0, "make", hex.EncodeToString(code), "s2", 0)
+	assert.NoError(t, bc.ConnectBlock(again, other))
+	receipt, _ = bc.Receipt(again)
+	assert.Contains(t, receipt.Status, "already deployed")
+	receipt, _ = bc.Receipt(other)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	assert.NotContains(t, receipt.Ret, base58.Encode(address))
+
+	// a query cannot deploy
+	_, err = bc.Query("factory", "view")
+	assert.Error(t, err)
+}
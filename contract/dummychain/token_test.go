@@ -0,0 +1,155 @@
+package dummychain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/aergoio/aergo/cmd/aergoluac/luac"
+	"github.com/aergoio/aergo/contract/token"
+	"github.com/aergoio/aergo/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func bignumJSON(n int64) map[string]string {
+	return types.NewBignumJSON(big.NewInt(n))
+}
+
+func TestToken(t *testing.T) {
+	bc, err := NewDummyChain()
+	assert.NoError(t, err)
+	defer bc.Close()
+	assert.NoError(t, bc.ConnectBlock(
+		NewTxAccount("alice", 1000),
+		NewTxAccount("bob", 1000),
+		NewTxAccount("carol", 1000),
+	))
+
+	supply, _ := new(big.Int).SetString("1000000000000000000000000", 10)
+	deploy := NewTxDeployToken("alice", "gem", "Gem", "GEM", token.DefaultDecimals, supply)
+	assert.NoError(t, bc.ConnectBlock(deploy))
+	receipt, err := bc.Receipt(deploy)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATED", receipt.Status)
+	assert.Len(t, receipt.Events, 1)
+	assert.Equal(t, token.EventTransfer, receipt.Events[0].EventName)
+
+	ret, err := bc.Query("gem", "name")
+	assert.NoError(t, err)
+	assert.Equal(t, `["Gem"]`, ret)
+	ret, err = bc.Query("gem", "decimals")
+	assert.NoError(t, err)
+	assert.Equal(t, `[18]`, ret)
+	ret, err = bc.Query("gem", "totalSupply")
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"_bignum":"1000000000000000000000000"}]`, ret)
+	balance, err := bc.TokenBalance("gem", "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, supply.String(), balance.String())
+
+	transfer := NewTxCall("alice", "gem", 0, "transfer", bc.Address("bob"), bignumJSON(100))
+	overdraft := NewTxCall("bob", "gem", 0, "transfer", bc.Address("carol"), bignumJSON(101))
+	negative := NewTxCall("bob", "gem", 0, "transfer", bc.Address("carol"), bignumJSON(-1))
+	assert.NoError(t, bc.ConnectBlock(transfer, overdraft, negative))
+	receipt, _ = bc.Receipt(transfer)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	assert.Equal(t, "[true]", receipt.Ret)
+	assert.Len(t, receipt.Events, 1)
+	var args []interface{}
+	assert.NoError(t, json.Unmarshal([]byte(receipt.Events[0].JsonArgs), &args))
+	assert.Equal(t, []interface{}{bc.Address("alice"), bc.Address("bob"),
+		map[string]interface{}{types.BignumKey: "100"}}, args)
+	receipt, _ = bc.Receipt(overdraft)
+	assert.Contains(t, receipt.Status, "not enough balance")
+	receipt, _ = bc.Receipt(negative)
+	assert.Contains(t, receipt.Status, "negative amount")
+
+	approve := NewTxCall("bob", "gem", 0, "approve", bc.Address("carol"), bignumJSON(60))
+	assert.NoError(t, bc.ConnectBlock(approve))
+	receipt, _ = bc.Receipt(approve)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	assert.Equal(t, token.EventApproval, receipt.Events[0].EventName)
+	ret, err = bc.Query("gem", "allowance", bc.Address("bob"), bc.Address("carol"))
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"_bignum":"60"}]`, ret)
+
+	spend := NewTxCall("carol", "gem", 0, "transferFrom", bc.Address("bob"), bc.Address("carol"), bignumJSON(50))
+	overspend := NewTxCall("carol", "gem", 0, "transferFrom", bc.Address("bob"), bc.Address("carol"), bignumJSON(20))
+	assert.NoError(t, bc.ConnectBlock(spend, overspend))
+	receipt, _ = bc.Receipt(spend)
+	assert.Equal(t, "SUCCESS", receipt.Status)
+	receipt, _ = bc.Receipt(overspend)
+	assert.Contains(t, receipt.Status, "not enough allowance")
+
+	for name, expected := range map[string]int64{"bob": 50, "carol": 50} {
+		balance, err := bc.TokenBalance("gem", name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, balance.Int64(), name)
+	}
+	ret, err = bc.Query("gem", "balanceOf", bc.Address("carol"))
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"_bignum":"50"}]`, ret)
+	ret, err = bc.Query("gem", "allowance", bc.Address("bob"), bc.Address("carol"))
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"_bignum":"10"}]`, ret)
+	balance, err = bc.TokenBalance("gem", "dave")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, balance.Sign())
+
+	// a contract without the code of the token is not a token, even with the
+	// balances, and the typed ABI rejects the invalid arguments
+	assert.NoError(t, bc.ConnectBlock(
+		NewTxDeploySource("alice", "counter", 0, counterSource, 0),
+		NewTxDeploySource("alice", "fake", 0, token.Source, "Fake", "FAKE", 18, bignumJSON(1)),
+	))
+	_, err = bc.TokenBalance("counter", "alice")
+	assert.Error(t, err)
+	_, err = bc.TokenBalance("fake", "alice")
+	assert.Error(t, err)
+	_, err = bc.Query("gem", "balanceOf", 1)
+	assert.Error(t, err)
+}
+
+func TestTokenDecimals(t *testing.T) {
+	bc, err := NewDummyChain()
+	assert.NoError(t, err)
+	defer bc.Close()
+	assert.NoError(t, bc.ConnectBlock(NewTxAccount("alice", 1000)))
+
+	code, err := token.Payload()
+	assert.NoError(t, err)
+	for i, decimals := range []interface{}{19, -1, 1.5, "18"} {
+		deploy := NewTxDeploy("alice", fmt.Sprintf("bad%d", i), 0, code, "Gem", "GEM", decimals, bignumJSON(1))
+		assert.NoError(t, bc.ConnectBlock(deploy))
+		receipt, err := bc.Receipt(deploy)
+		assert.NoError(t, err)
+		assert.Contains(t, receipt.Status, "invalid decimals", decimals)
+	}
+	assert.Error(t, NewTxDeployToken("alice", "gem", "Gem", "GEM", token.MaxDecimals+1, big.NewInt(1)).err)
+}
+
+func TestTokenABI(t *testing.T) {
+	// the ABI shipped with the token is the one generated by aergoluac
+	_, out, err := luac.Compile([]byte(token.Source))
+	assert.NoError(t, err)
+	var compiled types.ABI
+	assert.NoError(t, json.Unmarshal(out, &compiled))
+
+	abi := token.ABI()
+	assert.Equal(t, abi.Version, compiled.Version)
+	assert.Equal(t, abi.StateVariables, compiled.StateVariables)
+	assert.Len(t, compiled.Functions, len(abi.Functions))
+	for _, fn := range abi.Functions {
+		c := compiled.GetFunction(fn.Name)
+		if !assert.NotNil(t, c, fn.Name) {
+			continue
+		}
+		assert.Equal(t, fn.View, c.View, fn.Name)
+		assert.Equal(t, fn.Returns, c.Returns, fn.Name)
+		assert.Len(t, c.Arguments, len(fn.Arguments), fn.Name)
+		for i, arg := range c.Arguments {
+			assert.Equal(t, fn.Arguments[i].Type, arg.Type, fn.Name)
+		}
+	}
+}
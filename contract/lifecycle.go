@@ -0,0 +1,219 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package contract
+
+/*
+#include <stdlib.h>
+#include "vm.h"
+*/
+import "C"
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aergoio/aergo-lib/db"
+	"github.com/aergoio/aergo/state"
+	"github.com/aergoio/aergo/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/mr-tron/base58/base58"
+)
+
+// contractInfoKey is the storage key of the owner and the versions of a
+// contract. The keys of Lua, including the ones of the state variables, start
+// with the contract address, which has no '_' in base58, and the prefix is
+// not the one of the state variables, so they never collide.
+var contractInfoKey = []byte("_contract_info")
+
+var (
+	errNotOwner      = errors.New("only the owner can upgrade the contract")
+	errNotUpgradable = errors.New("contract is not upgradable")
+	errNoOwner       = errors.New("contract has no owner")
+	errLibDestruct   = errors.New("library cannot be destructed")
+)
+
+func getContractInfo(contractState *state.ContractState) (*types.ContractInfo, error) {
+	data, err := contractState.GetData(contractInfoKey)
+	if err != nil {
+		return nil, err
+	}
+	info := &types.ContractInfo{}
+	if len(data) == 0 {
+		return info, nil
+	}
+	if err := proto.Unmarshal(data, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func setContractInfo(contractState *state.ContractState, info *types.ContractInfo) error {
+	data, err := proto.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return contractState.SetData(contractInfoKey, data)
+}
+
+// addVersion records the current code of the contract as a new version.
+func addVersion(contractState *state.ContractState, info *types.ContractInfo, blockNo uint64, txHash []byte) error {
+	info.Versions = append(info.Versions, &types.ContractVersion{
+		Version:  uint32(len(info.Versions) + 1),
+		CodeHash: contractState.GetCodeHash(),
+		BlockNo:  blockNo,
+		TxHash:   txHash,
+	})
+	return setContractInfo(contractState, info)
+}
+
+// recordDeploy makes the deployer the owner of a new contract.
+func recordDeploy(contractState *state.ContractState, bcCtx *LBlockchainCtx, txHash []byte) error {
+	info := &types.ContractInfo{}
+	var blockNo uint64
+	if bcCtx != nil {
+		owner, err := base58.Decode(C.GoString(bcCtx.sender))
+		if err != nil {
+			return err
+		}
+		info.Owner = owner
+		blockNo = uint64(bcCtx.blockHeight)
+	}
+	return addVersion(contractState, info, blockNo, txHash)
+}
+
+// Upgrade replaces the code of a contract, keeping its storage. The contract
+// must be declared upgradable in its ABI, and only its owner can upgrade it.
+// The receipt has an upgrade event with the new version and code hash.
+func Upgrade(stateSet *StateSet, contractState *state.ContractState, code, contractAddress, sender, txHash []byte,
+	blockNo uint64, dbTx db.Transaction) error {
+	ctrLog.Debug().Str("contractAddress", base58.Encode(contractAddress)).Msg("contract is upgraded")
+	ev, err := upgrade(contractState, code, contractAddress, sender, txHash, blockNo)
+	if err != nil {
+		setReceipt(dbTx, txHash, contractAddress, "", "", 0, nil, err)
+		return err
+	}
+	stateSet.addEvent(ev)
+	setReceipt(dbTx, txHash, contractAddress, "UPGRADED", "{}", 0, stateSet, nil)
+	return nil
+}
+
+func upgrade(contractState *state.ContractState, code, contractAddress, sender, txHash []byte,
+	blockNo uint64) (*types.Event, error) {
+	contract := getContract(contractState, contractAddress)
+	if contract == nil {
+		return nil, fmt.Errorf("cannot find contract %s", base58.Encode(contractAddress))
+	}
+	abi := new(types.ABI)
+	if err := json.Unmarshal(contract.abi, abi); err != nil || !abi.Upgradable {
+		return nil, errNotUpgradable
+	}
+	info, err := getContractInfo(contractState)
+	if err != nil {
+		return nil, err
+	}
+	if len(info.Owner) == 0 || !bytes.Equal(info.Owner, sender) {
+		return nil, errNotOwner
+	}
+	if err := checkCode(code); err != nil {
+		return nil, err
+	}
+	if err := contractState.SetCode(code); err != nil {
+		return nil, err
+	}
+	if err := addVersion(contractState, info, blockNo, txHash); err != nil {
+		return nil, err
+	}
+	version := info.Versions[len(info.Versions)-1]
+	return &types.Event{
+		ContractAddress: contractAddress,
+		EventName:       "upgrade",
+		JsonArgs:        fmt.Sprintf(`[%d,"%s"]`, version.Version, base58.Encode(version.CodeHash)),
+		TxHash:          txHash,
+	}, nil
+}
+
+// GetContractInfo returns the owner and the versions of a contract.
+func GetContractInfo(contractState *state.ContractState, contractAddress []byte) (*types.ContractInfo, error) {
+	info, err := getContractInfo(contractState)
+	if err != nil {
+		return nil, err
+	}
+	info.Address = contractAddress
+	if contract := getContract(contractState, contractAddress); contract != nil {
+		abi := new(types.ABI)
+		if err := json.Unmarshal(contract.abi, abi); err == nil {
+			info.Upgradable = abi.Upgradable
+		}
+	}
+	return info, nil
+}
+
+// selfDestruct clears the code of the contract and sends its balance to the
+// owner. The storage is left to keep the history of the contract. A library
+// is never destructed, as other contracts run its code.
+func selfDestruct(stateSet *StateSet, contractState *state.ContractState, contractID, txHash string) error {
+	address, err := base58.Decode(contractID)
+	if err != nil {
+		return err
+	}
+	if contract := getContract(contractState, address); contract != nil && contract.isLibrary() {
+		return errLibDestruct
+	}
+	info, err := getContractInfo(contractState)
+	if err != nil {
+		return err
+	}
+	if len(info.Owner) == 0 {
+		return errNoOwner
+	}
+	st, err := stateSet.GetAccount(types.ToAccountID(address))
+	if err != nil {
+		return err
+	}
+	owner, err := stateSet.GetAccount(types.ToAccountID(info.Owner))
+	if err != nil {
+		return err
+	}
+	amount := st.Balance
+	owner.Balance += amount
+	st.Balance = 0
+	codeHash := st.CodeHash
+	st.CodeHash = nil
+	// the code is never run again at the address
+	codeCache.Remove(string(codeHash))
+	statePool.drop(contractKey(codeHash, address))
+
+	info.Destructed = true
+	if err := setContractInfo(contractState, info); err != nil {
+		return err
+	}
+	hash, _ := hex.DecodeString(txHash)
+	stateSet.addEvent(&types.Event{
+		ContractAddress: address,
+		EventName:       "selfdestruct",
+		JsonArgs:        fmt.Sprintf(`["%s",%d]`, base58.Encode(info.Owner), amount),
+		TxHash:          hash,
+	})
+	return nil
+}
+
+//export LuaSelfDestruct
+func LuaSelfDestruct(L *LState, stateKey *C.char, contractID *C.char, txHash *C.char) C.int {
+	key := C.GoString(stateKey)
+	stateSet := contractMap.lookupSet(key)
+	contractState := contractMap.lookup(key)
+	if stateSet == nil || contractState == nil {
+		luaPushError(L, "[Contract.LuaSelfDestruct]not found contract state")
+		return -1
+	}
+	if err := selfDestruct(stateSet, contractState, C.GoString(contractID), C.GoString(txHash)); err != nil {
+		luaPushError(L, err.Error())
+		return -1
+	}
+	return 0
+}
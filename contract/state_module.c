@@ -0,0 +1,137 @@
+#include <string.h>
+#include "vm.h"
+#include "system_module.h"
+#include "state_module.h"
+
+static const char *state_close_key = "__state_close__";
+static const char *state_module_key = "__state_module__";
+
+/* the state variables of a contract. state.var declares them as globals at
+   the top level of the contract, in the globals of the execution given to
+   the module, and each value, map entry and array element
+   is a storage item:
+     value          _sv_<name>
+     map entry      _sv_<name>-<key>
+     array element  _sv_<name>-<index>
+     array length   _sv_<name>#
+   the integer keys of a map are keyed by their decimal form. */
+static const char *state_module =
+	"local set_item, get_item, G = ...\n"
+	"local type, error, tostring, setmetatable, pairs = type, error, tostring, setmetatable, pairs\n"
+	"local floor, format, find = math.floor, string.format, string.find\n"
+	"local declaring = true\n"
+	"local kinds, info, names = {}, {}, {}\n"
+	"local function descriptor(kind)\n"
+	"  return function() local d = {}; kinds[d] = kind; return d end\n"
+	"end\n"
+	"local function map_key(k)\n"
+	"  if type(k) == 'string' then return k end\n"
+	"  if type(k) == 'number' and k == floor(k) then return format('%d', k) end\n"
+	"  error('invalid map key: ' .. tostring(k), 3)\n"
+	"end\n"
+	"local value_methods = {\n"
+	"  get = function(self) return get_item(info[self]) end,\n"
+	"  set = function(self, v) set_item(info[self], v) end,\n"
+	"}\n"
+	"local array_methods = {}\n"
+	"function array_methods.length(self) return get_item(info[self] .. '#') or 0 end\n"
+	"function array_methods.append(self, v)\n"
+	"  local n = array_methods.length(self) + 1\n"
+	"  set_item(info[self] .. '#', n)\n"
+	"  set_item(format('%s-%d', info[self], n), v)\n"
+	"end\n"
+	"function array_methods.pop(self)\n"
+	"  local n = array_methods.length(self)\n"
+	"  if n == 0 then return nil end\n"
+	"  local key = format('%s-%d', info[self], n)\n"
+	"  local v = get_item(key)\n"
+	"  set_item(key, nil)\n"
+	"  set_item(info[self] .. '#', n - 1)\n"
+	"  return v\n"
+	"end\n"
+	"function array_methods.ipairs(self)\n"
+	"  local n, i = array_methods.length(self), 0\n"
+	"  return function()\n"
+	"    i = i + 1\n"
+	"    if i <= n then return i, get_item(format('%s-%d', info[self], i)) end\n"
+	"  end\n"
+	"end\n"
+	"local function array_key(self, i)\n"
+	"  if type(i) ~= 'number' or i ~= floor(i) or i < 1 or i > array_methods.length(self) then\n"
+	"    error('index out of range: ' .. tostring(i), 3)\n"
+	"  end\n"
+	"  return format('%s-%d', info[self], i)\n"
+	"end\n"
+	"local mts = {\n"
+	"  value = {__index = value_methods, __newindex = function() error('use set to change a state value', 2) end},\n"
+	"  map = {\n"
+	"    __index = function(self, k) return get_item(info[self] .. '-' .. map_key(k)) end,\n"
+	"    __newindex = function(self, k, v) set_item(info[self] .. '-' .. map_key(k), v) end,\n"
+	"  },\n"
+	"  array = {\n"
+	"    __index = function(self, k)\n"
+	"      if type(k) == 'string' then return array_methods[k] end\n"
+	"      return get_item(array_key(self, k))\n"
+	"    end,\n"
+	"    __newindex = function(self, k, v) set_item(array_key(self, k), v) end,\n"
+	"  },\n"
+	"}\n"
+	"local state = {value = descriptor('value'), map = descriptor('map'), array = descriptor('array')}\n"
+	"function state.var(vars)\n"
+	"  if not declaring then error('state variables must be declared at the top level', 2) end\n"
+	"  if type(vars) ~= 'table' then error('state.var needs a table of state variables', 2) end\n"
+	"  for name, d in pairs(vars) do\n"
+	"    if type(name) ~= 'string' or not find(name, '^[%a_][%w_]*$') then\n"
+	"      error('invalid state variable name: ' .. tostring(name), 2)\n"
+	"    end\n"
+	"    if kinds[d] == nil then error('invalid type of state variable ' .. name, 2) end\n"
+	"    if names[name] then error('state variable ' .. name .. ' is declared twice', 2) end\n"
+	"    names[name] = true\n"
+	"    local v = setmetatable({}, mts[kinds[d]])\n"
+	"    info[v] = '" STATE_VAR_KEY_PREFIX "' .. name\n"
+	"    G[name] = v\n"
+	"  end\n"
+	"end\n"
+	"return state, function() declaring = false end\n";
+
+/* luaopen_state loads the module, which state_open runs for every execution */
+int luaopen_state(lua_State *L)
+{
+	if (luaL_loadbuffer(L, state_module, strlen(state_module), "state") != 0) {
+		lua_error(L);
+	}
+	lua_setfield(L, LUA_REGISTRYINDEX, state_module_key);
+	state_reset(L);
+	return 0;
+}
+
+/* state_open sets a new state module to the globals of an execution at the
+   top of the stack */
+void state_open(lua_State *L)
+{
+	lua_getfield(L, LUA_REGISTRYINDEX, state_module_key);
+	lua_pushcfunction(L, system_set_item);
+	lua_pushcfunction(L, system_get_item);
+	lua_pushvalue(L, -4);
+	lua_call(L, 3, 2);
+	lua_setfield(L, LUA_REGISTRYINDEX, state_close_key);
+	lua_setfield(L, -2, "state");
+}
+
+/* state_reset forgets the state module of the last execution */
+void state_reset(lua_State *L)
+{
+	lua_pushboolean(L, 0);
+	lua_setfield(L, LUA_REGISTRYINDEX, state_close_key);
+}
+
+/* state_close_declarations is called after the top level of a contract runs.
+   state.var fails afterwards. */
+void state_close_declarations(lua_State *L)
+{
+	lua_getfield(L, LUA_REGISTRYINDEX, state_close_key);
+	if (lua_isfunction(L, -1))
+		lua_call(L, 0, 0);
+	else
+		lua_pop(L, 1);
+}
@@ -0,0 +1,10 @@
+#ifndef _STATE_MODULE_H
+#define _STATE_MODULE_H
+
+typedef struct lua_State lua_State;
+extern int luaopen_state(lua_State *L);
+extern void state_open(lua_State *L);
+extern void state_reset(lua_State *L);
+extern void state_close_declarations(lua_State *L);
+
+#endif /* _STATE_MODULE_H */
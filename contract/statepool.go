@@ -0,0 +1,198 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package contract
+
+/*
+#include "vm.h"
+*/
+import "C"
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/aergoio/aergo/state"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// lStatePoolSize is the number of the Lua states made ahead of the
+	// executions
+	lStatePoolSize = 64
+	// codeCacheSize is the number of the contract codes kept in memory
+	codeCacheSize = 256
+)
+
+var (
+	statePool = newLStatePool(lStatePoolSize)
+	codeCache *lru.Cache
+)
+
+func init() {
+	var err error
+	if codeCache, err = lru.New(codeCacheSize); err != nil {
+		panic(err)
+	}
+}
+
+// lStatePool hands out Lua states whose modules are already opened and
+// sandboxed. A used state is kept for the next execution of the same
+// contract, keyed by its code hash and its address, if vm_reset finds that it
+// is left as a new state which has loaded the contract: each execution runs
+// in its own globals, so nothing of an execution is seen by the next one.
+// Otherwise the state is closed and a new one is made in the background,
+// because the memory and the gas of the executions would differ between
+// nodes.
+type lStatePool struct {
+	ready chan *LState
+	used  chan *LState
+	once  sync.Once
+
+	mu    sync.Mutex
+	idle  map[string][]*LState
+	nIdle int
+}
+
+func newLStatePool(size int) *lStatePool {
+	return &lStatePool{
+		ready: make(chan *LState, size),
+		used:  make(chan *LState, size),
+		idle:  make(map[string][]*LState),
+	}
+}
+
+func (p *lStatePool) start() {
+	go func() {
+		for i := 0; i < cap(p.ready); i++ {
+			p.refill()
+		}
+	}()
+	go func() {
+		for L := range p.used {
+			L.Close()
+			p.refill()
+		}
+	}()
+}
+
+func (p *lStatePool) refill() {
+	if len(p.ready) == cap(p.ready) {
+		return
+	}
+	L := newLState()
+	if L == nil {
+		return
+	}
+	select {
+	case p.ready <- L:
+	default:
+		L.Close()
+	}
+}
+
+// get returns a Lua state which has run the contract of key, or a new one,
+// made right now if the pool is drained.
+func (p *lStatePool) get(key string) *LState {
+	p.once.Do(p.start)
+	if L := p.popIdle(key); L != nil {
+		return L
+	}
+	select {
+	case L := <-p.ready:
+		return L
+	default:
+		return newLState()
+	}
+}
+
+func (p *lStatePool) popIdle(key string) *LState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	states := p.idle[key]
+	if len(states) == 0 {
+		return nil
+	}
+	L := states[len(states)-1]
+	if len(states) == 1 {
+		delete(p.idle, key)
+	} else {
+		p.idle[key] = states[:len(states)-1]
+	}
+	p.nIdle--
+	return L
+}
+
+// put gives back a used Lua state to run the contract of key again, or to be
+// closed and replaced.
+func (p *lStatePool) put(key string, L *LState) {
+	if L == nil {
+		return
+	}
+	// the execution context of the hook is freed after the execution
+	C.lua_sethook(L, nil, 0, 0)
+	if len(key) > 0 && C.vm_reset(L) != 0 {
+		p.mu.Lock()
+		if p.nIdle < cap(p.ready) {
+			p.idle[key] = append(p.idle[key], L)
+			p.nIdle++
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+	}
+	select {
+	case p.used <- L:
+	default:
+		L.Close()
+	}
+}
+
+// drop closes the Lua states kept for the contract of key.
+func (p *lStatePool) drop(key string) {
+	p.mu.Lock()
+	states := p.idle[key]
+	delete(p.idle, key)
+	p.nIdle -= len(states)
+	p.mu.Unlock()
+	for _, L := range states {
+		select {
+		case p.used <- L:
+		default:
+			L.Close()
+		}
+	}
+}
+
+// contractCode is a contract code split into its bytecode and ABI.
+type contractCode struct {
+	code []byte
+	abi  []byte
+}
+
+// loadCode returns the code of a contract. The codes are cached by their
+// hashes, which never point to another code.
+func loadCode(contractState *state.ContractState) *contractCode {
+	codeHash := contractState.GetCodeHash()
+	if len(codeHash) == 0 {
+		return nil
+	}
+	if cached, ok := codeCache.Get(string(codeHash)); ok {
+		return cached.(*contractCode)
+	}
+	val, err := contractState.GetCode()
+	if err != nil || len(val) < 4 {
+		return nil
+	}
+	l := binary.LittleEndian.Uint32(val[0:])
+	if uint64(len(val)) < 4+uint64(l) {
+		return nil
+	}
+	code := &contractCode{
+		code: val[4 : 4+l],
+		abi:  val[4+l:],
+	}
+	codeCache.Add(string(codeHash), code)
+	return code
+}
@@ -0,0 +1,154 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package contract
+
+import (
+	"errors"
+
+	"github.com/aergoio/aergo/state"
+	"github.com/aergoio/aergo/types"
+)
+
+// StateSet keeps the account states used by a contract execution, including
+// the contracts called by it. The changes are put to the block state by
+// Apply only after the whole execution succeeds, so a failed execution
+// leaves nothing behind.
+type StateSet struct {
+	sdb       *state.ChainStateDB
+	bs        *types.BlockState
+	snapshot  *state.Snapshot
+	origin    map[types.AccountID]*types.State
+	changes   map[types.AccountID]*types.State
+	contracts map[types.AccountID]*state.ContractState
+	trace     []*types.CallTrace
+	events    []*types.Event
+	tracer    *tracer
+	err       error
+}
+
+// NewStateSet returns a StateSet reading the account states of bs. If bs is
+// nil, the states of the latest block are used.
+func NewStateSet(sdb *state.ChainStateDB, bs *types.BlockState) *StateSet {
+	s := &StateSet{
+		sdb:       sdb,
+		bs:        bs,
+		origin:    map[types.AccountID]*types.State{},
+		changes:   map[types.AccountID]*types.State{},
+		contracts: map[types.AccountID]*state.ContractState{},
+	}
+	if bs != nil && bs.Trace {
+		s.tracer = newTracer()
+	}
+	return s
+}
+
+// newSnapshotStateSet returns a StateSet reading the account states of a
+// snapshot, which is the case of queries. Its changes are never applied.
+func newSnapshotStateSet(snapshot *state.Snapshot) *StateSet {
+	s := NewStateSet(nil, nil)
+	s.snapshot = snapshot
+	return s
+}
+
+// GetAccount returns the state of an account to be changed in the set.
+func (s *StateSet) GetAccount(aid types.AccountID) (*types.State, error) {
+	if st, ok := s.changes[aid]; ok {
+		return st, nil
+	}
+	if s.sdb == nil && s.snapshot == nil {
+		return nil, errors.New("no state db to open an account")
+	}
+	var st *types.State
+	var err error
+	if s.snapshot != nil {
+		st, err = s.snapshot.GetAccountStateClone(aid)
+	} else if s.bs != nil {
+		st, err = s.sdb.GetBlockAccountClone(s.bs, aid)
+	} else {
+		st, err = s.sdb.GetAccountStateClone(aid)
+	}
+	if err != nil {
+		return nil, err
+	}
+	change := types.Clone(*st).(types.State)
+	s.origin[aid] = st
+	s.changes[aid] = &change
+	return &change, nil
+}
+
+// OpenContract returns the contract state of an account in the set. The
+// same contract state is returned while the execution goes on.
+func (s *StateSet) OpenContract(aid types.AccountID) (*state.ContractState, error) {
+	if contractState, ok := s.contracts[aid]; ok {
+		return contractState, nil
+	}
+	st, err := s.GetAccount(aid)
+	if err != nil {
+		return nil, err
+	}
+	var contractState *state.ContractState
+	if s.snapshot != nil {
+		contractState, err = s.snapshot.OpenContractState(st)
+	} else {
+		contractState, err = s.sdb.OpenContractState(st)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.bs != nil && s.bs.DryRun {
+		contractState.SetDryRun()
+	}
+	s.contracts[aid] = contractState
+	return contractState, nil
+}
+
+// setContract adds a contract state opened by the caller of the execution.
+// It is committed by the caller, not by the set.
+func (s *StateSet) setContract(aid types.AccountID, contractState *state.ContractState) {
+	if _, ok := s.contracts[aid]; !ok {
+		s.contracts[aid] = contractState
+	}
+}
+
+// Apply commits the contracts opened in the set and puts the changed
+// account states to the block state.
+func (s *StateSet) Apply() error {
+	for aid, contractState := range s.contracts {
+		if _, ok := s.changes[aid]; !ok {
+			continue
+		}
+		// the storage root of a dry run is already in the state, but its
+		// trie nodes are left in memory
+		if !contractState.IsDryRun() {
+			if err := s.sdb.CommitContractState(contractState); err != nil {
+				return err
+			}
+		}
+		delete(s.contracts, aid)
+	}
+	for aid, st := range s.changes {
+		s.bs.PutAccount(aid, s.origin[aid], st)
+	}
+	for _, ev := range s.events {
+		s.bs.EventBloom.AddEvent(ev)
+	}
+	return nil
+}
+
+// Trace returns the contract calls made during the execution.
+func (s *StateSet) Trace() []*types.CallTrace {
+	return s.trace
+}
+
+func (s *StateSet) addEvent(ev *types.Event) {
+	ev.EventIdx = int32(len(s.events))
+	s.events = append(s.events, ev)
+}
+
+// Events returns the events emitted during the execution.
+func (s *StateSet) Events() []*types.Event {
+	return s.events
+}
@@ -1,22 +1,47 @@
 #include <string.h>
 #include <stdlib.h>
+#include <stdio.h>
 #include "vm.h"
 #include "util.h"
+#include "bignum_module.h"
+#include "system_module.h"
 #include "_cgo_export.h"
 
 extern const bc_ctx_t *getLuaExecContext(lua_State *L);
 
 static int systemPrint(lua_State *L)
 {
-	printf ("systemPrinted");
-	return 1;
+	int i, n = lua_gettop(L);
+	luaL_Buffer b;
+	bc_ctx_t *exec = (bc_ctx_t *)getLuaExecContext(L);
+
+	luaL_buffinit(L, &b);
+	for (i = 1; i <= n; i++) {
+		if (i > 1)
+			luaL_addchar(&b, '\t');
+		if (lua_isstring(L, i)) {
+			lua_pushvalue(L, i);
+			luaL_addvalue(&b);
+		} else if (lua_isboolean(L, i)) {
+			luaL_addstring(&b, lua_toboolean(L, i) ? "true" : "false");
+		} else {
+			luaL_addstring(&b, luaL_typename(L, i));
+		}
+	}
+	luaL_pushresult(&b);
+	LuaPrint(exec, (char *)lua_tostring(L, -1));
+	return 0;
 }
 
-static int setItem(lua_State *L)
+/* system_set_item is system.setItem without the check of the key. The state
+   module uses it for the keys of the state variables. Setting nil deletes
+   the item, so that it does not take the storage anymore. */
+int system_set_item(lua_State *L)
 {
 	const char *key;
 	char *jsonValue;
 	char *dbKey;
+	unsigned long long gas;
 	bc_ctx_t *exec = (bc_ctx_t *)getLuaExecContext(L);
 
 	if (exec == NULL) {
@@ -30,20 +55,45 @@ static int setItem(lua_State *L)
 	luaL_checkany(L, 2);
 	key = luaL_checkstring(L, 1);
 
-	jsonValue = lua_util_get_json (L, -1);
-
 	dbKey = lua_util_get_db_key(exec, key);
+	if (lua_isnil(L, -1)) {
+		if (exec->trace)
+			LuaTraceItem(exec, (char *)key, dbKey, NULL, 1);
+		if (LuaDelDB(L, exec->stateKey, dbKey) != 0) {
+			free(dbKey);
+			lua_error(L);
+		}
+		free(dbKey);
+		vm_use_gas(L, GAS_SET_ITEM);
+		return 0;
+	}
+
+	jsonValue = lua_util_get_json (L, -1);
 
+	if (exec->trace)
+		LuaTraceItem(exec, (char *)key, dbKey, jsonValue, 1);
 	if (LuaSetDB(L, exec->stateKey, dbKey, jsonValue) != 0) {
 		lua_error(L);
 	}
+	gas = GAS_SET_ITEM + GAS_SET_ITEM_BYTE * strlen(jsonValue);
 	free(jsonValue);
 	free(dbKey);
+	vm_use_gas(L, gas);
 
 	return 0;
 }
 
-static int getItem(lua_State *L)
+static int setItem(lua_State *L)
+{
+	const char *key = luaL_checkstring(L, 1);
+
+	if (strncmp(key, STATE_VAR_KEY_PREFIX, strlen(STATE_VAR_KEY_PREFIX)) == 0) {
+		luaL_error(L, "the key prefix %s is reserved for the state variables", STATE_VAR_KEY_PREFIX);
+	}
+	return system_set_item(L);
+}
+
+int system_get_item(lua_State *L)
 {
 	const char *key;
 	char *dbKey;
@@ -55,6 +105,7 @@ static int getItem(lua_State *L)
 		luaL_error(L, "cannot find execution context");
 	}
 	key = luaL_checkstring(L, 1);
+	vm_use_gas(L, GAS_GET_ITEM);
 	dbKey = lua_util_get_db_key(exec, key);
 
 	ret = LuaGetDB(L, exec->stateKey, dbKey);
@@ -62,10 +113,15 @@ static int getItem(lua_State *L)
     if (ret < 0) {
 		lua_error(L);
     }
-    if (ret == 0)
+    if (ret == 0) {
+		if (exec->trace)
+			LuaTraceItem(exec, (char *)key, dbKey, NULL, 0);
         return 0;
+    }
     jsonValue = (char *)luaL_checkstring(L, -1);
     lua_pop(L, 1);
+	if (exec->trace)
+		LuaTraceItem(exec, (char *)key, dbKey, jsonValue, 0);
 
 	if (lua_util_json_to_lua(L, jsonValue) != 0) {
 		luaL_error(L, "getItem error : can't convert %s", jsonValue);
@@ -80,6 +136,7 @@ static int getSender(lua_State *L)
 	if (exec == NULL) {
 		luaL_error(L, "cannot find execution context");
 	}
+	vm_use_gas(L, GAS_SYSTEM_CALL);
 	lua_pushstring(L, exec->sender);
 	return 1;
 }
@@ -90,6 +147,7 @@ static int getTxhash(lua_State *L)
 	if (exec == NULL) {
 		luaL_error(L, "cannot find execution context");
 	}
+	vm_use_gas(L, GAS_SYSTEM_CALL);
 	lua_pushstring(L, exec->txHash);
 	return 1;
 }
@@ -100,6 +158,7 @@ static int getBlockHeight(lua_State *L)
 	if (exec == NULL) {
 		luaL_error(L, "cannot find execution context");
 	}
+	vm_use_gas(L, GAS_SYSTEM_CALL);
 	lua_pushinteger(L, exec->blockHeight);
 	return 1;
 }
@@ -110,6 +169,7 @@ static int getTimestamp(lua_State *L)
 	if (exec == NULL) {
 		luaL_error(L, "cannot find execution context");
 	}
+	vm_use_gas(L, GAS_SYSTEM_CALL);
 	lua_pushinteger(L, exec->timestamp);
 	return 1;
 }
@@ -120,20 +180,36 @@ static int getContractID(lua_State *L)
 	if (exec == NULL) {
 		luaL_error(L, "cannot find execution context");
 	}
+	vm_use_gas(L, GAS_SYSTEM_CALL);
 	lua_pushstring(L, exec->contractId);
 	return 1;
 }
 
+/* getAmount returns a bignum, as a lua number cannot hold every amount */
+static int getAmount(lua_State *L)
+{
+	char buf[32];
+	const bc_ctx_t *exec = getLuaExecContext(L);
+	if (exec == NULL) {
+		luaL_error(L, "cannot find execution context");
+	}
+	vm_use_gas(L, GAS_SYSTEM_CALL);
+	snprintf(buf, sizeof(buf), "%llu", exec->amount);
+	bignum_push(L, buf);
+	return 1;
+}
+
 static const luaL_Reg sys_lib[] = {
 	{"print", systemPrint},
 	{"setItem", setItem},
-	{"getItem", getItem},
+	{"getItem", system_get_item},
 	{"getSender", getSender},
 	{"getCreator", getContractID},
 	{"getTxhash", getTxhash},
 	{"getBlockheight", getBlockHeight},
 	{"getTimestamp", getTimestamp},
 	{"getContractID", getContractID},
+	{"getAmount", getAmount},
 	{NULL, NULL}
 };
 
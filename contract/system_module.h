@@ -2,6 +2,11 @@
 #define _SYSTEM_MODULE_H
 
 typedef struct lua_State lua_State;
+/* the prefix of the storage keys of the state variables */
+#define STATE_VAR_KEY_PREFIX "_sv_"
+
 extern int luaopen_system(lua_State *L);
+extern int system_set_item(lua_State *L);
+extern int system_get_item(lua_State *L);
 
 #endif /* _SYSTEM_MODULE_H */
\ No newline at end of file
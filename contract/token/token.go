@@ -0,0 +1,279 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+// Package token is the fungible token standard of aergo. The token contract
+// is shipped as a Lua source with its typed ABI, so it is deployed without
+// aergoluac, and the balances are read from its state variables without
+// running the contract. A token is a contract with the code of the standard.
+package token
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/aergoio/aergo/types"
+	"github.com/mr-tron/base58/base58"
+)
+
+// DefaultDecimals is the decimals of a token unless given, which is the
+// decimals of aergo.
+const DefaultDecimals = 18
+
+// MaxDecimals is the greatest decimals of a token, which is the decimals of
+// aergo.
+const MaxDecimals = 18
+
+// BalancesVar is the map state variable of the balances of a token, keyed by
+// the base58 addresses of the owners.
+const BalancesVar = "balances"
+
+// the events of a token, whose arguments are the sender, the recipient or the
+// spender, and the amount
+const (
+	EventTransfer = "transfer"
+	EventApproval = "approval"
+)
+
+var (
+	errInvalidAmount   = errors.New("invalid token amount")
+	errInvalidDecimals = errors.New("invalid token decimals")
+)
+
+// Source is the token contract. The amounts are bignums, and the tokens are
+// created to the deployer by the constructor, whose arguments are the name,
+// the symbol, the decimals and the supply of the token.
+const Source = `
+state.var {
+	tokenName = state.value(),
+	tokenSymbol = state.value(),
+	tokenDecimals = state.value(),
+	supply = state.value(),
+	balances = state.map(),
+	allowances = state.map(),
+}
+
+local function amount(value)
+	local n = bignum.number(value)
+	assert(not bignum.isneg(n), "negative amount")
+	return n
+end
+
+local function balance(owner)
+	return balances[owner] or bignum.number(0)
+end
+
+local function allowanceKey(owner, spender)
+	return owner .. "/" .. spender
+end
+
+local function move(from, to, n)
+	local b = balance(from)
+	assert(b >= n, "not enough balance")
+	balances[from] = b - n
+	balances[to] = balance(to) + n
+	contract.event("transfer", from, to, n)
+end
+
+function constructor(name, symbol, decimals, total)
+	assert(type(decimals) == "number" and decimals % 1 == 0 and
+		decimals >= 0 and decimals <= 18, "invalid decimals")
+	local n = amount(total)
+	tokenName:set(name)
+	tokenSymbol:set(symbol)
+	tokenDecimals:set(decimals)
+	supply:set(n)
+	balances[system.getSender()] = n
+	contract.event("transfer", "", system.getSender(), n)
+end
+
+function name()
+	return tokenName:get()
+end
+
+function symbol()
+	return tokenSymbol:get()
+end
+
+function decimals()
+	return tokenDecimals:get()
+end
+
+function totalSupply()
+	return supply:get()
+end
+
+function balanceOf(owner)
+	return balance(owner)
+end
+
+function allowance(owner, spender)
+	return allowances[allowanceKey(owner, spender)] or bignum.number(0)
+end
+
+function transfer(to, value)
+	move(system.getSender(), to, amount(value))
+	return true
+end
+
+function approve(spender, value)
+	local n = amount(value)
+	allowances[allowanceKey(system.getSender(), spender)] = n
+	contract.event("approval", system.getSender(), spender, n)
+	return true
+end
+
+function transferFrom(from, to, value)
+	local n = amount(value)
+	local spender = system.getSender()
+	local a = allowance(from, spender)
+	assert(a >= n, "not enough allowance")
+	allowances[allowanceKey(from, spender)] = a - n
+	move(from, to, n)
+	return true
+end
+
+abi.register(name)
+abi.register(symbol)
+abi.register(decimals)
+abi.register(totalSupply)
+abi.register(balanceOf)
+abi.register(allowance)
+abi.register(transfer)
+abi.register(approve)
+abi.register(transferFrom)
+abi.view(name, symbol, decimals, totalSupply, balanceOf, allowance)
+abi.types(name, {}, {"string"})
+abi.types(symbol, {}, {"string"})
+abi.types(decimals, {}, {"integer"})
+abi.types(totalSupply, {}, {"bignum"})
+abi.types(balanceOf, {"address"}, {"bignum"})
+abi.types(allowance, {"address", "address"}, {"bignum"})
+abi.types(transfer, {"address", "bignum"}, {"bool"})
+abi.types(approve, {"address", "bignum"}, {"bool"})
+abi.types(transferFrom, {"address", "address", "bignum"}, {"bool"})
+`
+
+func function(name string, view bool, args []*types.FnArgument, rets ...string) *types.Function {
+	return &types.Function{Name: name, Arguments: args, Returns: rets, View: view}
+}
+
+func arg(name, typ string) *types.FnArgument {
+	return &types.FnArgument{Name: name, Type: typ}
+}
+
+// ABI returns the typed ABI of Source, which aergoluac generates from its
+// declarations.
+func ABI() *types.ABI {
+	return &types.ABI{
+		Version:  types.TypedABIVersion,
+		Language: "lua",
+		Functions: []*types.Function{
+			function("name", true, nil, types.ABITypeString),
+			function("symbol", true, nil, types.ABITypeString),
+			function("decimals", true, nil, types.ABITypeInteger),
+			function("totalSupply", true, nil, types.ABITypeBignum),
+			function("balanceOf", true, []*types.FnArgument{
+				arg("owner", types.ABITypeAddress),
+			}, types.ABITypeBignum),
+			function("allowance", true, []*types.FnArgument{
+				arg("owner", types.ABITypeAddress),
+				arg("spender", types.ABITypeAddress),
+			}, types.ABITypeBignum),
+			function("transfer", false, []*types.FnArgument{
+				arg("to", types.ABITypeAddress),
+				arg("value", types.ABITypeBignum),
+			}, types.ABITypeBool),
+			function("approve", false, []*types.FnArgument{
+				arg("spender", types.ABITypeAddress),
+				arg("value", types.ABITypeBignum),
+			}, types.ABITypeBool),
+			function("transferFrom", false, []*types.FnArgument{
+				arg("from", types.ABITypeAddress),
+				arg("to", types.ABITypeAddress),
+				arg("value", types.ABITypeBignum),
+			}, types.ABITypeBool),
+		},
+		StateVariables: []*types.StateVar{
+			{Name: "allowances", Type: types.StateVarMap},
+			{Name: BalancesVar, Type: types.StateVarMap},
+			{Name: "supply", Type: types.StateVarValue},
+			{Name: "tokenDecimals", Type: types.StateVarValue},
+			{Name: "tokenName", Type: types.StateVarValue},
+			{Name: "tokenSymbol", Type: types.StateVarValue},
+		},
+	}
+}
+
+// Payload returns the code of the token contract, which is Source with its
+// ABI. The source is compiled by the nodes.
+func Payload() ([]byte, error) {
+	abi, err := json.Marshal(ABI())
+	if err != nil {
+		return nil, err
+	}
+	payload := make([]byte, 4, 4+len(Source)+len(abi))
+	binary.LittleEndian.PutUint32(payload, uint32(len(Source)))
+	payload = append(payload, Source...)
+	payload = append(payload, abi...)
+	return payload, nil
+}
+
+// DeployPayload returns the payload of the tx deploying a token.
+func DeployPayload(name, symbol string, decimals uint32, supply *big.Int) ([]byte, error) {
+	if decimals > MaxDecimals {
+		return nil, errInvalidDecimals
+	}
+	if supply.Sign() < 0 || types.CheckBignum(supply) != nil {
+		return nil, errInvalidAmount
+	}
+	code, err := Payload()
+	if err != nil {
+		return nil, err
+	}
+	args, err := json.Marshal([]interface{}{name, symbol, decimals, types.NewBignumJSON(supply)})
+	if err != nil {
+		return nil, err
+	}
+	return types.EncodeDeployPayload(code, args), nil
+}
+
+// IsToken returns true if codeHash is the hash of the code of the token
+// contract. A contract with the same ABI but another code is not a token,
+// because its state variables may not be balances.
+func IsToken(codeHash []byte) bool {
+	code, err := Payload()
+	if err != nil {
+		return false
+	}
+	hash := sha256.Sum256(code)
+	return bytes.Equal(hash[:], codeHash)
+}
+
+// BalanceKey returns the storage key of the balance of owner in the token
+// contract.
+func BalanceKey(tokenAddress, owner []byte) []byte {
+	return types.StateVarKey(tokenAddress, BalancesVar, base58.Encode(owner))
+}
+
+// ParseAmount returns the amount of a stored balance, which is the JSON of a
+// bignum. An empty balance is zero.
+func ParseAmount(data []byte) (*big.Int, error) {
+	if len(data) == 0 {
+		return new(big.Int), nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	n, ok := types.ParseBignumJSON(v)
+	if !ok {
+		return nil, errInvalidAmount
+	}
+	return n, nil
+}
@@ -0,0 +1,37 @@
+package token
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/aergoio/aergo/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsToken(t *testing.T) {
+	code, err := Payload()
+	assert.NoError(t, err)
+	hash := sha256.Sum256(code)
+	assert.True(t, IsToken(hash[:]))
+
+	// the same ABI with another code is not a token
+	other := sha256.Sum256(append(code, ' '))
+	assert.False(t, IsToken(other[:]))
+	assert.False(t, IsToken(nil))
+}
+
+func TestDeployPayload(t *testing.T) {
+	payload, err := DeployPayload("Gem", "GEM", DefaultDecimals, big.NewInt(100))
+	assert.NoError(t, err)
+	code, args, err := types.DecodeDeployPayload(payload)
+	assert.NoError(t, err)
+	expected, _ := Payload()
+	assert.Equal(t, expected, code)
+	assert.Equal(t, `["Gem","GEM",18,{"_bignum":"100"}]`, string(args))
+
+	_, err = DeployPayload("Gem", "GEM", MaxDecimals+1, big.NewInt(100))
+	assert.Equal(t, errInvalidDecimals, err)
+	_, err = DeployPayload("Gem", "GEM", DefaultDecimals, big.NewInt(-1))
+	assert.Equal(t, errInvalidAmount, err)
+}
@@ -0,0 +1,144 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package contract
+
+/*
+#include "vm.h"
+*/
+import "C"
+import (
+	"encoding/json"
+
+	"github.com/aergoio/aergo/types"
+)
+
+// the ops of the trace steps
+const (
+	traceEnter   = "enter"
+	traceCall    = "call"
+	traceReturn  = "return"
+	traceGetItem = "getItem"
+	traceSetItem = "setItem"
+	tracePrint   = "print"
+)
+
+// tracer records the steps of the contract executions of a tx. The gas of a
+// step is known when the next step of the same context is recorded, and a
+// return step ends its context.
+type tracer struct {
+	steps []*types.TraceStep
+	gasAt []uint64
+	open  map[string]int
+}
+
+func newTracer() *tracer {
+	return &tracer{open: map[string]int{}}
+}
+
+func (t *tracer) record(bcCtx *LBlockchainCtx, step *types.TraceStep) {
+	key := C.GoString(bcCtx.stateKey)
+	gasUsed := uint64(bcCtx.gasUsed)
+	if i, ok := t.open[key]; ok && gasUsed >= t.gasAt[i] {
+		t.steps[i].Gas = gasUsed - t.gasAt[i]
+	}
+	step.Contract = C.GoString(bcCtx.contractId)
+	step.Depth = uint32(bcCtx.callDepth)
+	t.steps = append(t.steps, step)
+	t.gasAt = append(t.gasAt, gasUsed)
+	if step.Op == traceReturn {
+		delete(t.open, key)
+	} else {
+		t.open[key] = len(t.steps) - 1
+	}
+}
+
+// traceStep records a step in bcCtx if the set is traced.
+func (s *StateSet) traceStep(bcCtx *LBlockchainCtx, step *types.TraceStep) {
+	if s == nil || s.tracer == nil || bcCtx == nil {
+		return
+	}
+	s.tracer.record(bcCtx, step)
+}
+
+func (s *StateSet) traceCall(bcCtx *LBlockchainCtx, fname string, args interface{}) {
+	if s == nil || s.tracer == nil {
+		return
+	}
+	var value string
+	switch v := args.(type) {
+	case string:
+		value = v
+	default:
+		if b, err := json.Marshal(v); err == nil {
+			value = string(b)
+		}
+	}
+	s.traceStep(bcCtx, &types.TraceStep{Op: traceCall, Function: fname, Value: value})
+}
+
+func (s *StateSet) traceReturn(bcCtx *LBlockchainCtx, fname, jsonRet string, err error) {
+	step := &types.TraceStep{Op: traceReturn, Function: fname, Value: jsonRet}
+	if err != nil {
+		step.Message = err.Error()
+	}
+	s.traceStep(bcCtx, step)
+}
+
+// Steps returns the steps recorded while tracing the execution.
+func (s *StateSet) Steps() []*types.TraceStep {
+	if s.tracer == nil {
+		return nil
+	}
+	return s.tracer.steps
+}
+
+func lookupTracedSet(bcCtx *LBlockchainCtx) *StateSet {
+	if bcCtx == nil || bcCtx.trace == 0 {
+		return nil
+	}
+	return contractMap.lookupSet(C.GoString(bcCtx.stateKey))
+}
+
+//export LuaTraceEnter
+func LuaTraceEnter(bcCtx *LBlockchainCtx, name *C.char, line C.int) {
+	fname := C.GoString(name)
+	if fname == "" {
+		fname = "?"
+	}
+	lookupTracedSet(bcCtx).traceStep(bcCtx, &types.TraceStep{Op: traceEnter, Function: fname, Line: int32(line)})
+}
+
+//export LuaTraceItem
+func LuaTraceItem(bcCtx *LBlockchainCtx, key *C.char, dbKey *C.char, value *C.char, set C.int) {
+	stateSet := lookupTracedSet(bcCtx)
+	if stateSet == nil {
+		return
+	}
+	step := &types.TraceStep{Op: traceGetItem, Key: C.GoString(key)}
+	if value != nil {
+		step.Value = C.GoString(value)
+	}
+	if set != 0 {
+		step.Op = traceSetItem
+		if contractState := contractMap.lookup(C.GoString(bcCtx.stateKey)); contractState != nil {
+			if old, err := contractState.GetData([]byte(C.GoString(dbKey))); err == nil {
+				step.OldValue = string(old)
+			}
+		}
+	}
+	stateSet.traceStep(bcCtx, step)
+}
+
+//export LuaPrint
+func LuaPrint(bcCtx *LBlockchainCtx, msg *C.char) {
+	message := C.GoString(msg)
+	if bcCtx == nil {
+		ctrLog.Debug().Msg(message)
+		return
+	}
+	ctrLog.Debug().Str("contract", C.GoString(bcCtx.contractId)).Msg(message)
+	lookupTracedSet(bcCtx).traceStep(bcCtx, &types.TraceStep{Op: tracePrint, Message: message})
+}
@@ -5,6 +5,8 @@
 #include <ctype.h>
 #include "util.h"
 #include "vm.h"
+#include "bignum_module.h"
+#include "_cgo_export.h"
 
 void lua_util_sbuf_init(sbuff_t *sbuf, int len)
 {
@@ -75,6 +77,16 @@ static void dump_json (lua_State *L, int idx, sbuff_t *sbuf)
 		src_val = "},";
 		break;
 	}
+	case LUA_TUSERDATA: {
+		const char *n = bignum_get(L, idx);
+		if (n != NULL) {
+			copy_to_buffer (BIGNUM_JSON_PREFIX, strlen (BIGNUM_JSON_PREFIX), sbuf);
+			copy_to_buffer ((char *)n, strlen (n), sbuf);
+			src_val = "\"},";
+			break;
+		}
+	}
+	/* fall through */
 	default:
 		src_val = (char *)lua_typename (L, lua_type(L, idx));
 		copy_to_buffer ("\"unsupport type:", 16, sbuf);
@@ -88,6 +100,34 @@ static void dump_json (lua_State *L, int idx, sbuff_t *sbuf)
 	copy_to_buffer (src_val, len, sbuf);
 }
 
+/* json_value_end returns the end of the value at json in an object, which is
+   the ',' or the '}' after it. The nested objects and the strings are
+   skipped */
+static char *json_value_end(char *json)
+{
+	int depth = 0;
+	bool in_string = false;
+
+	for (; *json != '\0'; ++json) {
+		if (in_string) {
+			if (*json == '"')
+				in_string = false;
+			continue;
+		}
+		if (*json == '"') {
+			in_string = true;
+		} else if (*json == '{') {
+			++depth;
+		} else if (*json == '}' || *json == ',') {
+			if (depth == 0)
+				break;
+			if (*json == '}')
+				--depth;
+		}
+	}
+	return json;
+}
+
 static int json_to_lua_table(lua_State *L, char *json) {
 	char *token_end;
 	bool end = false;
@@ -103,8 +143,9 @@ static int json_to_lua_table(lua_State *L, char *json) {
 	   if (lua_util_json_to_lua (L, json) != 0)
 		   return -1;
 	   json = token_end + 1;
-	   token_end = json;
-	   while(*token_end != ',' && *token_end != '}') ++token_end;
+	   token_end = json_value_end(json);
+	   if (*token_end == '\0')
+		   return -1;
 	   if (*token_end == '}')
 			end = true;
 	   *token_end = '\0';
@@ -116,6 +157,22 @@ static int json_to_lua_table(lua_State *L, char *json) {
 	return 0;
 }
 
+/* json_to_bignum converts the JSON object of a bignum */
+static int json_to_bignum(lua_State *L, char *json)
+{
+	char *digits = json + strlen(BIGNUM_JSON_PREFIX);
+	char *end = strchr(digits, '"');
+
+	if (end == NULL || strcmp(end, "\"}") != 0)
+		return -1;
+	*end = '\0';
+	if (LuaBignumParse(L, digits) < 0) {
+		lua_pop(L, 1);
+		return -1;
+	}
+	return 0;
+}
+
 int lua_util_json_to_lua (lua_State *L, char *json)
 {
 	if (*json == '"') {
@@ -126,6 +183,8 @@ int lua_util_json_to_lua (lua_State *L, char *json)
 		double d;
 		sscanf(json, "%lf", &d);
 		lua_pushnumber(L, d);
+	} else if (strncmp(json, BIGNUM_JSON_PREFIX, strlen(BIGNUM_JSON_PREFIX)) == 0) {
+		return json_to_bignum(L, json);
 	} else if (*json == '{') {
 		return json_to_lua_table(L, json);
 	} else if (strcmp(json, "true") == 0) {
@@ -1,65 +1,561 @@
 #include <string.h>
 #include <stdlib.h>
+#include <time.h>
 #include "vm.h"
 #include "system_module.h"
+#include "contract_module.h"
+#include "crypto_module.h"
+#include "state_module.h"
+#include "bignum_module.h"
 #include "util.h"
+#include "_cgo_export.h"
 
 const char *luaExecContext= "__exec_context__";
 
+/* the registry keys of the contract loaded in a lua state, of the globals of
+   its execution and of the functions registered to its abi */
+static const char *contract_chunk_key = "__contract_chunk__";
+static const char *contract_env_key = "__contract_env__";
+static const char *contract_fns_key = "__contract_fns__";
+
 static void preloadModules(lua_State *L)
 {
 	luaopen_system(L);
+	luaopen_contract(L);
+	luaopen_crypto(L);
+	luaopen_state(L);
+	luaopen_bignum(L);
+}
+
+/* the globals left in the sandbox of contracts. the others are removed */
+static const char *sandbox_globals[] = {
+	"_G", "_VERSION", "assert", "error", "getmetatable", "ipairs", "next",
+	"pairs", "pcall", "print", "rawequal", "rawget", "rawset", "select",
+	"setmetatable", "tonumber", "tostring", "type", "unpack", "xpcall",
+	"string", "table", "math", "bit", "abi", "system", "contract", "crypto",
+	"state", "bignum", NULL
+};
+
+/* the functions left in the standard libraries. string.dump, math.random and
+   the math functions whose results depend on the platform are removed */
+static const char *sandbox_string[] = {
+	"byte", "char", "find", "format", "gmatch", "gsub", "len", "lower",
+	"match", "rep", "reverse", "sub", "upper", NULL
+};
+static const char *sandbox_table[] = {
+	"concat", "insert", "maxn", "remove", "sort", NULL
+};
+static const char *sandbox_math[] = {
+	"abs", "ceil", "floor", "fmod", "frexp", "huge", "ldexp", "max", "min",
+	"modf", "pi", "sqrt", NULL
+};
+
+/* the globals which a contract cannot refer to. contract.Create rejects the
+   code referring to them */
+static const char *banned_globals[] = {
+	"os", "io", "debug", "package", "require", "module", "dofile", "loadfile",
+	"load", "loadstring", "collectgarbage", "gcinfo", "newproxy", "getfenv",
+	"setfenv", "coroutine", "jit", "ffi", NULL
+};
+
+static int in_list(const char *name, const char **list)
+{
+	int i;
+	for (i = 0; list[i] != NULL; i++) {
+		if (strcmp(name, list[i]) == 0)
+			return 1;
+	}
+	return 0;
+}
+
+/* remove the fields of the table at the top of the stack not in the list */
+static void filter_table(lua_State *L, const char **list)
+{
+	lua_pushnil(L);
+	while (lua_next(L, -2) != 0) {
+		lua_pop(L, 1);
+		if (lua_type(L, -1) != LUA_TSTRING || !in_list(lua_tostring(L, -1), list)) {
+			/* clearing an existing field is allowed during the traversal */
+			lua_pushvalue(L, -1);
+			lua_pushnil(L);
+			lua_rawset(L, -4);
+		}
+	}
+}
+
+static void filter_lib(lua_State *L, const char *lib, const char **list)
+{
+	lua_getfield(L, LUA_GLOBALSINDEX, lib);
+	if (lua_istable(L, -1))
+		filter_table(L, list);
+	lua_pop(L, 1);
+}
+
+static int abiDeclaration(lua_State *L)
+{
+	return 0;
+}
+
+/* abi.register records the functions which the txs and the queries can call */
+static int abiRegister(lua_State *L)
+{
+	int i, n = lua_gettop(L);
+
+	lua_getfield(L, LUA_REGISTRYINDEX, contract_fns_key);
+	if (!lua_istable(L, -1))
+		luaL_error(L, "cannot register functions");
+	for (i = 1; i <= n; i++) {
+		luaL_checktype(L, i, LUA_TFUNCTION);
+		lua_pushvalue(L, i);
+		lua_pushboolean(L, 1);
+		lua_rawset(L, -3);
+	}
+	return 0;
+}
+
+/* push a new abi table. abi.view, abi.payable, abi.types, abi.upgradable and
+   abi.library are used by aergoluac to generate a typed abi. They do nothing
+   in the vm */
+static void newAbi(lua_State *L)
+{
+	lua_newtable(L);
+	lua_pushcfunction(L, abiRegister);
+	lua_setfield(L, -2, "register");
+	lua_pushcfunction(L, abiDeclaration);
+	lua_setfield(L, -2, "view");
+	lua_pushcfunction(L, abiDeclaration);
+	lua_setfield(L, -2, "payable");
+	lua_pushcfunction(L, abiDeclaration);
+	lua_setfield(L, -2, "types");
+	lua_pushcfunction(L, abiDeclaration);
+	lua_setfield(L, -2, "upgradable");
+	lua_pushcfunction(L, abiDeclaration);
+	lua_setfield(L, -2, "library");
+}
+
+/* replace the table at the top of the stack with a shallow copy of it */
+static void copyTable(lua_State *L)
+{
+	lua_newtable(L);
+	lua_pushnil(L);
+	while (lua_next(L, -3) != 0) {
+		lua_pushvalue(L, -2);
+		lua_insert(L, -2);
+		lua_rawset(L, -4);
+	}
+	lua_remove(L, -2);
+}
+
+/* newEnv pushes the globals of an execution. The globals of the sandbox are
+   copied into a new table, and so are the libraries, so that the changes made
+   by an execution are thrown away with its globals, and the lua state can run
+   the contract again as if it were new */
+static void newEnv(lua_State *L)
+{
+	int i;
+	const char *name;
+
+	lua_newtable(L);
+	for (i = 0; sandbox_globals[i] != NULL; i++) {
+		name = sandbox_globals[i];
+		if (strcmp(name, "_G") == 0 || strcmp(name, "abi") == 0 || strcmp(name, "state") == 0)
+			continue;
+		lua_getfield(L, LUA_GLOBALSINDEX, name);
+		if (lua_istable(L, -1))
+			copyTable(L);
+		lua_setfield(L, -2, name);
+	}
+	lua_pushvalue(L, -1);
+	lua_setfield(L, -2, "_G");
+	newAbi(L);
+	lua_setfield(L, -2, "abi");
+	state_open(L);
+
+	lua_newtable(L);
+	lua_setfield(L, LUA_REGISTRYINDEX, contract_fns_key);
+}
+
+static void sandbox(lua_State *L)
+{
+	filter_lib(L, "string", sandbox_string);
+	filter_lib(L, "table", sandbox_table);
+	filter_lib(L, "math", sandbox_math);
+	lua_pushvalue(L, LUA_GLOBALSINDEX);
+	filter_table(L, sandbox_globals);
+	lua_pop(L, 1);
+	/* print of lua writes to the stdout of the node, so it is replaced with
+	   system.print, which is logged and traced */
+	lua_getfield(L, LUA_GLOBALSINDEX, "system");
+	lua_getfield(L, -1, "print");
+	lua_setfield(L, LUA_GLOBALSINDEX, "print");
+	lua_pop(L, 1);
+	/* the string library of the methods of the strings is shared by the
+	   executions, so the contracts must not reach it by getmetatable */
+	lua_pushliteral(L, "");
+	if (lua_getmetatable(L, -1)) {
+		lua_pushboolean(L, 0);
+		lua_setfield(L, -2, "__metatable");
+		lua_pop(L, 1);
+	}
+	lua_pop(L, 1);
 }
 
 static void setLuaExecContext(lua_State *L, bc_ctx_t *bc_ctx)
 {
 	lua_pushlightuserdata(L, bc_ctx);
-	lua_setglobal(L, luaExecContext);
+	lua_setfield(L, LUA_REGISTRYINDEX, luaExecContext);
 }
 
 const bc_ctx_t *getLuaExecContext(lua_State *L)
 {
 	bc_ctx_t *exec;
-	lua_getglobal(L, luaExecContext);
+	lua_getfield(L, LUA_REGISTRYINDEX, luaExecContext);
 	exec = (bc_ctx_t *)lua_touserdata(L, -1);
 	lua_pop(L, 1);
 
 	return exec;
 }
 
+static void vmHook(lua_State *L, lua_Debug *ar)
+{
+	bc_ctx_t *exec;
+
+	if (ar->event == LUA_HOOKCOUNT) {
+		vm_use_gas(L, GAS_HOOK_INSTRUCTIONS * GAS_INSTRUCTION);
+		return;
+	}
+	/* the calls are hooked only while tracing */
+	exec = (bc_ctx_t *)getLuaExecContext(L);
+	if (exec == NULL || lua_getinfo(L, "nS", ar) == 0 || strcmp(ar->what, "Lua") != 0)
+		return;
+	LuaTraceEnter(exec, (char *)ar->name, ar->linedefined);
+}
+
+/* the memory of a lua state is limited while its contract runs. The
+   allocations made by go are not limited, because a memory error cannot be
+   raised across go. The error is raised again after a contract catches it.
+   The memory is counted in size classes rather than in the bytes asked by
+   the allocations, and every growth of it costs gas by its size class */
+typedef struct {
+	lua_Alloc alloc;
+	void *ud;
+	bc_ctx_t *exec;
+	long long used;
+	long long limit;
+	long long baseline;
+	int running;
+	int unlimited;
+	int exceeded;
+} vm_memory_t;
+
+static long long sizeClass(size_t size)
+{
+	long long class = MEMORY_MIN_CLASS;
+
+	if (size == 0)
+		return 0;
+	while (class < (long long)size)
+		class <<= 1;
+	return class;
+}
+
+static void *limitedAlloc(void *ud, void *ptr, size_t osize, size_t nsize)
+{
+	vm_memory_t *m = (vm_memory_t *)ud;
+	long long grow = sizeClass(nsize) - sizeClass(osize);
+	int metered = m->running && m->unlimited == 0;
+
+	if (grow > 0 && metered && m->limit > 0 && m->used + grow > m->limit) {
+		m->exceeded = 1;
+		return NULL;
+	}
+	ptr = m->alloc(m->ud, ptr, osize, nsize);
+	if (ptr == NULL && nsize != 0)
+		return NULL;
+	m->used += grow;
+	/* the memory allocated before the execution is not counted in used, so
+	   freeing it must not give the contract more memory than its limit */
+	if (m->used < 0)
+		m->used = 0;
+	/* the context is only valid while the contract runs */
+	if (m->running && m->exec != NULL) {
+		if (grow > 0 && metered)
+			m->exec->gasUsed += (grow + GAS_MEMORY_BYTES - 1) / GAS_MEMORY_BYTES * GAS_MEMORY;
+		m->exec->memoryUsed = m->used;
+	}
+	return ptr;
+}
+
+static vm_memory_t *getMemory(lua_State *L)
+{
+	void *ud;
+
+	if (lua_getallocf(L, &ud) != limitedAlloc)
+		return NULL;
+	return (vm_memory_t *)ud;
+}
+
+int vm_memory_exceeded(lua_State *L)
+{
+	vm_memory_t *m = getMemory(L);
+	return m != NULL && m->exceeded;
+}
+
+void vm_memory_unlimited(lua_State *L, int on)
+{
+	vm_memory_t *m = getMemory(L);
+	if (m == NULL)
+		return;
+	if (on)
+		m->unlimited++;
+	else
+		m->unlimited--;
+}
+
+static void setMemoryRunning(lua_State *L, int running)
+{
+	vm_memory_t *m = getMemory(L);
+	if (m != NULL)
+		m->running = running;
+}
+
+static long long footprint(lua_State *L)
+{
+	return (long long)lua_gc(L, LUA_GCCOUNT, 0) * 1024 + lua_gc(L, LUA_GCCOUNTB, 0);
+}
+
+static long long nowNano()
+{
+	struct timespec ts;
+	clock_gettime(CLOCK_REALTIME, &ts);
+	return (long long)ts.tv_sec * 1000000000LL + ts.tv_nsec;
+}
+
+void vm_use_gas(lua_State *L, unsigned long long gas)
+{
+	bc_ctx_t *exec = (bc_ctx_t *)getLuaExecContext(L);
+	if (exec == NULL)
+		return;
+	exec->gasUsed += gas;
+	if (exec->gasUsed > exec->gasLimit) {
+		luaL_error(L, "out of gas");
+	}
+	if (vm_memory_exceeded(L)) {
+		luaL_error(L, "memory limit exceeded");
+	}
+	if (exec->deadline > 0 && nowNano() > exec->deadline) {
+		exec->timedOut = 1;
+		luaL_error(L, "query timeout");
+	}
+}
+
+/* clearKeys sets the registry keys of an execution to false. They are never
+   removed, so the registry does not grow with the executions */
+static void clearKeys(lua_State *L)
+{
+	lua_pushboolean(L, 0);
+	lua_setfield(L, LUA_REGISTRYINDEX, contract_env_key);
+	lua_pushboolean(L, 0);
+	lua_setfield(L, LUA_REGISTRYINDEX, contract_fns_key);
+	state_reset(L);
+}
+
 lua_State *vm_newstate()
 {
+	vm_memory_t *m;
 	lua_State *L = luaL_newstate();
 	if (L == NULL)
 	    return NULL;
+	m = (vm_memory_t *)calloc(1, sizeof(vm_memory_t));
+	if (m == NULL) {
+		lua_close(L);
+		return NULL;
+	}
+	m->alloc = lua_getallocf(L, &m->ud);
+	lua_setallocf(L, limitedAlloc, m);
+	/* the instruction count hook is not called from compiled traces */
+	luaJIT_setmode(L, 0, LUAJIT_MODE_ENGINE|LUAJIT_MODE_OFF);
 	luaL_openlibs(L);
 	preloadModules(L);
+	sandbox(L);
+	clearKeys(L);
+	setLuaExecContext(L, NULL);
 	return L;
 }
 
+void vm_closestate(lua_State *L)
+{
+	vm_memory_t *m = getMemory(L);
+
+	lua_close(L);
+	free(m);
+}
+
+static const char *bytecode_checker =
+	"local fn, banned = ...\n"
+	"local jutil = require('jit.util')\n"
+	"local band, shr = bit.band, bit.rshift\n"
+	"local function op(f, pc) return band(jutil.funcbc(f, pc), 0xff) end\n"
+	"local GGET, GSET = op(loadstring('return x'), 1), op(loadstring('x = 0'), 2)\n"
+	"local function check(f)\n"
+	"  for pc = 1, math.huge do\n"
+	"    local ins = jutil.funcbc(f, pc)\n"
+	"    if ins == nil then break end\n"
+	"    local o = band(ins, 0xff)\n"
+	"    if o == GGET or o == GSET then\n"
+	"      local name = jutil.funck(f, -shr(ins, 16) - 1)\n"
+	"      if banned[name] then error('banned global: ' .. name, 0) end\n"
+	"    end\n"
+	"  end\n"
+	"  for i = -1, -jutil.funcinfo(f).gcconsts, -1 do\n"
+	"    local k = jutil.funck(f, i)\n"
+	"    if type(k) == 'proto' then check(k) end\n"
+	"  end\n"
+	"end\n"
+	"check(fn)\n";
+
+/* vm_check_bytecode returns an error message if the bytecode refers to one of
+   the banned globals. The bytecode is loaded but not run. */
+const char *vm_check_bytecode(const char *code, size_t sz)
+{
+	int i;
+	const char *errMsg = NULL;
+	lua_State *L = luaL_newstate();
+
+	if (L == NULL)
+		return strdup("cannot create a lua state");
+	luaL_openlibs(L);
+
+	if (luaL_loadstring(L, bytecode_checker) != 0 ||
+		luaL_loadbuffer(L, code, sz, "contract") != 0) {
+		errMsg = strdup(lua_tostring(L, -1));
+		lua_close(L);
+		return errMsg;
+	}
+	lua_newtable(L);
+	for (i = 0; banned_globals[i] != NULL; i++) {
+		lua_pushboolean(L, 1);
+		lua_setfield(L, -2, banned_globals[i]);
+	}
+	if (lua_pcall(L, 2, 0, 0) != 0) {
+		errMsg = strdup(lua_tostring(L, -1));
+	}
+	lua_close(L);
+	return errMsg;
+}
+
+/* vm_loadbuff runs the top level of a contract in new globals. The contract
+   is loaded once in a lua state, which runs it again after vm_reset */
 const char *vm_loadbuff(lua_State *L, const char *code, size_t sz, const char *name, bc_ctx_t *bc_ctx)
 {
 	int err;
 	const char *errMsg = NULL;
+	vm_memory_t *m = getMemory(L);
 
 	setLuaExecContext(L, bc_ctx);
+	lua_getfield(L, LUA_REGISTRYINDEX, contract_chunk_key);
+	if (!lua_isfunction(L, -1)) {
+		lua_pop(L, 1);
+		err = luaL_loadbuffer(L, code, sz, name);
+		if (err != 0) {
+			errMsg = strdup(lua_tostring(L, -1));
+			return errMsg;
+		}
+		lua_pushvalue(L, -1);
+		lua_setfield(L, LUA_REGISTRYINDEX, contract_chunk_key);
+		/* a reset state must be like this one to run the contract again */
+		lua_gc(L, LUA_GCCOLLECT, 0);
+		if (m != NULL)
+			m->baseline = footprint(L);
+	}
+	newEnv(L);
+	lua_pushvalue(L, -1);
+	lua_setfield(L, LUA_REGISTRYINDEX, contract_env_key);
+	lua_setfenv(L, -2);
 
-	err = luaL_loadbuffer(L, code, sz, name);
-	if (err != 0) {
-		errMsg = strdup(lua_tostring(L, -1));
-		return errMsg;
+	if (m != NULL) {
+		m->exec = bc_ctx;
+		m->limit = bc_ctx != NULL ? (long long)bc_ctx->memoryLimit : 0;
+		m->used = 0;
+		m->exceeded = 0;
 	}
+	if (bc_ctx != NULL && bc_ctx->trace)
+		lua_sethook(L, vmHook, LUA_MASKCOUNT|LUA_MASKCALL, GAS_HOOK_INSTRUCTIONS);
+	else
+		lua_sethook(L, vmHook, LUA_MASKCOUNT, GAS_HOOK_INSTRUCTIONS);
+
+	setMemoryRunning(L, 1);
 	err = lua_pcall(L, 0, 0, 0);
+	setMemoryRunning(L, 0);
 	if (err != 0) {
 		errMsg = strdup(lua_tostring(L, -1));
 		return errMsg;
 	}
+	state_close_declarations(L);
 	return NULL;
 }
 
+/* vm_reset makes L ready to run its contract again. It returns 0 if L must be
+   closed instead, because the execution left more in L than a new state has
+   after loading the contract. Then the memory and the gas of the next
+   execution could differ from the ones on a new state */
+int vm_reset(lua_State *L)
+{
+	vm_memory_t *m = getMemory(L);
+
+	lua_sethook(L, NULL, 0, 0);
+	lua_settop(L, 0);
+	setLuaExecContext(L, NULL);
+	clearKeys(L);
+	lua_getfield(L, LUA_REGISTRYINDEX, contract_chunk_key);
+	if (!lua_isfunction(L, -1)) {
+		lua_pop(L, 1);
+		return 0;
+	}
+	lua_pushvalue(L, LUA_GLOBALSINDEX);
+	lua_setfenv(L, -2);
+	lua_pop(L, 1);
+	if (m == NULL || m->unlimited != 0)
+		return 0;
+	m->exec = NULL;
+	m->limit = 0;
+	m->used = 0;
+	m->exceeded = 0;
+	lua_gc(L, LUA_GCCOLLECT, 0);
+	return footprint(L) == m->baseline;
+}
+
+/* vm_getfield pushes a global of the execution */
 void vm_getfield(lua_State *L, const char *name)
 {
-	lua_getfield(L, LUA_GLOBALSINDEX, name);
+	lua_getfield(L, LUA_REGISTRYINDEX, contract_env_key);
+	if (lua_istable(L, -1))
+		lua_getfield(L, -1, name);
+	else
+		lua_pushnil(L);
+	lua_remove(L, -2);
+}
+
+/* vm_getfunction pushes the function of name registered by abi.register, or
+   returns 0 with nothing pushed */
+int vm_getfunction(lua_State *L, const char *name)
+{
+	vm_getfield(L, name);
+	if (lua_isfunction(L, -1)) {
+		lua_getfield(L, LUA_REGISTRYINDEX, contract_fns_key);
+		if (lua_istable(L, -1)) {
+			lua_pushvalue(L, -2);
+			lua_rawget(L, -2);
+			if (lua_toboolean(L, -1)) {
+				lua_pop(L, 2);
+				return 1;
+			}
+			lua_pop(L, 1);
+		}
+		lua_pop(L, 1);
+	}
+	lua_pop(L, 1);
+	return 0;
 }
 
 const char *vm_pcall(lua_State *L, int argc, int *nresult)
@@ -68,7 +564,9 @@ const char *vm_pcall(lua_State *L, int argc, int *nresult)
 	const char *errMsg = NULL;
 	int nr = lua_gettop(L) - argc - 1;
 
+	setMemoryRunning(L, 1);
 	err = lua_pcall(L, argc, LUA_MULTRET, 0);
+	setMemoryRunning(L, 0);
 	if (err != 0) {
 		errMsg = strdup(lua_tostring(L, -1));
 		return errMsg;
@@ -20,7 +20,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/aergoio/aergo-lib/db"
 	"github.com/aergoio/aergo-lib/log"
@@ -31,21 +35,65 @@ import (
 	"github.com/aergoio/aergo/state"
 )
 
-const DbName = "contracts.db"
+const (
+	DbName = "contracts.db"
+
+	// MaxGasLimit is the gas limit of a contract execution whose transaction
+	// has a greater one. It is also applied to queries.
+	MaxGasLimit uint64 = 100000000
+)
+
+// MaxMemory is the maximum bytes of the memory of a contract execution,
+// which the nested calls share.
+const MaxMemory uint64 = types.MaxContractMemory
+
+// QueryTimeout is the time allowed to a query, after which it fails
+var QueryTimeout = types.DefaultQueryTimeout * time.Millisecond
 
 var (
 	ctrLog      *log.Logger
 	DB          db.DB
 	contractMap stateMap
+	// querySeq numbers the queries, which have no tx hash to tell apart the
+	// states of the queries running at the same time
+	querySeq uint64
+
+	// ErrOutOfGas is returned when a contract execution exceeds its gas limit
+	ErrOutOfGas = errors.New("out of gas")
+	// ErrMemoryLimit is returned when a contract execution exceeds MaxMemory
+	ErrMemoryLimit = errors.New("memory limit exceeded")
+	// ErrQueryTimeout is returned when a query runs longer than QueryTimeout
+	ErrQueryTimeout = errors.New("query timeout")
+
+	errInvalidCode     = errors.New("invalid contract code")
+	errViewCall        = errors.New("view function cannot be called by a transaction")
+	errNoConstructor   = errors.New("contract has no constructor")
+	errConstructorCall = errors.New("constructor cannot be called")
+	errUpgradableLib   = errors.New("library cannot be upgradable")
 )
 
+// SetQueryTimeout sets the timeout of the queries. A zero value leaves the
+// default.
+func SetQueryTimeout(queryTimeout time.Duration) {
+	if queryTimeout > 0 {
+		QueryTimeout = queryTimeout
+	}
+}
+
+// constructorName is the name of the function run on deployment
+const constructorName = "constructor"
+
 type Contract struct {
 	code    []byte
+	abi     []byte
 	address []byte
+	// key tells apart the Lua states which have loaded the contract
+	key string
 }
 
 type stateMap struct {
 	states map[string]*state.ContractState
+	sets   map[string]*StateSet
 	mu     sync.Mutex
 }
 
@@ -58,6 +106,8 @@ type Executor struct {
 	err           error
 	blockchainCtx *LBlockchainCtx
 	jsonRet       string
+	// stateKey is the key of the contract state in contractMap
+	stateKey string
 }
 
 func init() {
@@ -65,21 +115,38 @@ func init() {
 	contractMap.init()
 }
 
-func NewContext(contractState *state.ContractState, Sender, txHash []byte, blockHeight uint64,
-	timestamp int64, node string, confirmed bool, contractID []byte, query bool) *LBlockchainCtx {
+func NewContext(stateSet *StateSet, contractState *state.ContractState, Sender, txHash []byte, blockHeight uint64,
+	timestamp int64, node string, confirmed bool, contractID []byte, query bool, gasLimit uint64,
+	amount uint64) *LBlockchainCtx {
 
-	var iConfirmed, isQuery int
+	var iConfirmed, isQuery, trace int
+	var deadline int64
 	if confirmed {
 		iConfirmed = 1
 	}
 	if query {
 		isQuery = 1
 	}
+	if stateSet != nil && stateSet.tracer != nil {
+		trace = 1
+	}
+	if stateSet != nil && stateSet.bs != nil {
+		deadline = stateSet.bs.Deadline
+	}
+	if gasLimit > MaxGasLimit {
+		gasLimit = MaxGasLimit
+	}
 	enContractId := base58.Encode(contractID)
 	enTxHash := hex.EncodeToString(txHash)
 
 	stateKey := fmt.Sprintf("%s%s", enContractId, enTxHash)
-	contractMap.register(stateKey, contractState)
+	if query {
+		stateKey = fmt.Sprintf("%s#%d", stateKey, atomic.AddUint64(&querySeq, 1))
+	}
+	contractMap.register(stateKey, contractState, stateSet)
+	if stateSet != nil {
+		stateSet.setContract(types.ToAccountID(contractID), contractState)
+	}
 
 	return &LBlockchainCtx{
 		stateKey:    C.CString(stateKey),
@@ -91,6 +158,11 @@ func NewContext(contractState *state.ContractState, Sender, txHash []byte, block
 		confirmed:   C.int(iConfirmed),
 		contractId:  C.CString(enContractId),
 		isQuery:     C.int(isQuery),
+		gasLimit:    C.ulonglong(gasLimit),
+		amount:      C.ulonglong(amount),
+		trace:       C.int(trace),
+		memoryLimit: C.ulonglong(MaxMemory),
+		deadline:    C.longlong(deadline),
 	}
 }
 
@@ -100,7 +172,7 @@ func newLState() *LState {
 
 func (L *LState) Close() {
 	if L != nil {
-		C.lua_close(L)
+		C.vm_closestate(L)
 	}
 }
 
@@ -110,7 +182,10 @@ func newExecutor(contract *Contract, bcCtx *LBlockchainCtx) *Executor {
 
 	ce := &Executor{
 		contract: contract,
-		L:        newLState(),
+		L:        statePool.get(contract.key),
+	}
+	if bcCtx != nil {
+		ce.stateKey = C.GoString(bcCtx.stateKey)
 	}
 	if ce.L == nil {
 		ctrLog.Error().Str("error", "Failed: create lua state")
@@ -136,18 +211,40 @@ func (ce *Executor) call(ci *types.CallInfo) {
 	if ce.err != nil {
 		return
 	}
-	abiStr := C.CString("abi")
-	callStr := C.CString("call")
 	abiName := C.CString(ci.Name)
-
-	defer C.free(unsafe.Pointer(abiStr))
-	defer C.free(unsafe.Pointer(callStr))
 	defer C.free(unsafe.Pointer(abiName))
 
-	C.vm_getfield(ce.L, abiStr)
-	C.lua_getfield(ce.L, -1, callStr)
-	C.lua_pushstring(ce.L, abiName)
-	for _, v := range ci.Args {
+	if C.vm_getfunction(ce.L, abiName) == 0 {
+		ce.err = fmt.Errorf("function %s is not registered", ci.Name)
+		return
+	}
+	ce.processArgs(ci.Args, 0)
+}
+
+// constructCall runs the constructor of the contract with args. A contract
+// without a constructor can be deployed only with no argument.
+func (ce *Executor) constructCall(args []interface{}) {
+	if ce.err != nil {
+		return
+	}
+	ctorStr := C.CString(constructorName)
+	defer C.free(unsafe.Pointer(ctorStr))
+
+	C.vm_getfield(ce.L, ctorStr)
+	if C.lua_type(ce.L, -1) != C.LUA_TFUNCTION {
+		C.lua_settop(ce.L, -2)
+		if len(args) > 0 {
+			ce.err = errNoConstructor
+		}
+		return
+	}
+	ce.processArgs(args, 0)
+}
+
+// processArgs pushes args after the function and nfixed arguments already
+// pushed, and calls the function.
+func (ce *Executor) processArgs(args []interface{}, nfixed int) {
+	for _, v := range args {
 		switch arg := v.(type) {
 		case string:
 			argC := C.CString(arg)
@@ -155,19 +252,30 @@ func (ce *Executor) call(ci *types.CallInfo) {
 			C.free(unsafe.Pointer(argC))
 		case int:
 			C.lua_pushinteger(ce.L, C.long(arg))
+		case float64:
+			C.lua_pushnumber(ce.L, C.double(arg))
+		case nil:
+			C.lua_pushnil(ce.L)
 		case bool:
 			var b int
 			if arg {
 				b = 1
 			}
 			C.lua_pushboolean(ce.L, C.int(b))
+		case map[string]interface{}:
+			n, ok := types.ParseBignumJSON(arg)
+			if !ok {
+				ce.err = errors.New("unsupported type")
+				return
+			}
+			luaPushBignum(ce.L, n)
 		default:
 			ce.err = errors.New("unsupported type")
 			return
 		}
 	}
 	nret := C.int(0)
-	if cErrMsg := C.vm_pcall(ce.L, C.int(len(ci.Args)+1), &nret); cErrMsg != nil {
+	if cErrMsg := C.vm_pcall(ce.L, C.int(len(args)+nfixed), &nret); cErrMsg != nil {
 		errMsg := C.GoString(cErrMsg)
 		C.free(unsafe.Pointer(cErrMsg))
 		ctrLog.Warn().Str("error", errMsg).Msgf("contract %s", base58.Encode(ce.contract.address))
@@ -177,9 +285,19 @@ func (ce *Executor) call(ci *types.CallInfo) {
 	ce.jsonRet = C.GoString(C.vm_get_json_ret(ce.L, nret))
 }
 
+// destructed tells if the contract running in ce has destructed itself.
+func (ce *Executor) destructed() bool {
+	contractState := contractMap.lookup(ce.stateKey)
+	return contractState != nil && len(contractState.GetCodeHash()) == 0
+}
+
 func (ce *Executor) close() {
 	if ce != nil {
-		ce.L.Close()
+		var key string
+		if ce.contract != nil && !ce.destructed() {
+			key = ce.contract.key
+		}
+		statePool.put(key, ce.L)
 		if ce.blockchainCtx != nil {
 			context := ce.blockchainCtx
 			contractMap.unregister(C.GoString(context.stateKey))
@@ -194,6 +312,46 @@ func (ce *Executor) close() {
 }
 
 func Call(contractState *state.ContractState, code, contractAddress, txHash []byte, bcCtx *LBlockchainCtx, dbTx db.Transaction) error {
+	jsonRet, gasUsed, stateSet, err := execute(contractState, code, contractAddress, bcCtx)
+	setReceipt(dbTx, txHash, contractAddress, "SUCCESS", jsonRet, gasUsed, stateSet, err)
+	return err
+}
+
+// setReceipt writes the receipt of a contract execution. On error, the status
+// is the error message.
+func setReceipt(dbTx db.Transaction, txHash, contractAddress []byte, status, jsonRet string, gasUsed uint64,
+	stateSet *StateSet, err error) {
+	var receipt types.Receipt
+	if err == nil {
+		receipt = types.NewReceipt(contractAddress, status, jsonRet)
+	} else {
+		receipt = types.NewReceipt(contractAddress, err.Error(), "")
+	}
+	receipt.GasUsed = gasUsed
+	if stateSet != nil {
+		receipt.CallTrace = stateSet.Trace()
+		if err == nil {
+			receipt.Events = stateSet.Events()
+		}
+		receipt.Steps = stateSet.Steps()
+	}
+	dbTx.Set(txHash, receipt.Bytes())
+}
+
+// Execute calls the contract function described by code and returns its
+// result as JSON with the gas used. Unlike Call, it does not write a receipt.
+func Execute(contractState *state.ContractState, code, contractAddress []byte, bcCtx *LBlockchainCtx) (string, uint64, error) {
+	jsonRet, gasUsed, _, err := execute(contractState, code, contractAddress, bcCtx)
+	return jsonRet, gasUsed, err
+}
+
+func execute(contractState *state.ContractState, code, contractAddress []byte, bcCtx *LBlockchainCtx) (string, uint64, *StateSet, error) {
+	var stateSet *StateSet
+	if bcCtx != nil {
+		stateKey := C.GoString(bcCtx.stateKey)
+		stateSet = contractMap.lookupSet(stateKey)
+		defer contractMap.unregister(stateKey)
+	}
 	var err error
 	var ci types.CallInfo
 	contract := getContract(contractState, contractAddress)
@@ -206,39 +364,148 @@ func Call(contractState *state.ContractState, code, contractAddress, txHash []by
 		err = fmt.Errorf("cannot find contract %s", string(contractAddress))
 		ctrLog.Warn().AnErr("err", err)
 	}
-	var ce *Executor
-	if err == nil {
-		ctrLog.Debug().Str("abi", string(code)).Msgf("contract %s", base58.Encode(contractAddress))
-		ce = newExecutor(contract, bcCtx)
-		defer ce.close()
-		ce.call(&ci)
-		err = ce.err
+	if err != nil {
+		return "", 0, stateSet, err
 	}
-	var receipt types.Receipt
-	if err == nil {
-		receipt = types.NewReceipt(contractAddress, "SUCCESS", ce.jsonRet)
-	} else {
-		receipt = types.NewReceipt(contractAddress, err.Error(), "")
+	var amount uint64
+	if bcCtx != nil {
+		amount = uint64(bcCtx.amount)
 	}
-	dbTx.Set(txHash, receipt.Bytes())
-	return err
+	fn, err := contract.checkCall(&ci, amount)
+	if err != nil {
+		return "", 0, stateSet, err
+	}
+	if fn != nil && fn.View {
+		return "", 0, stateSet, errViewCall
+	}
+	ctrLog.Debug().Str("abi", string(code)).Msgf("contract %s", base58.Encode(contractAddress))
+	stateSet.traceCall(bcCtx, ci.Name, ci.Args)
+	ce := newExecutor(contract, bcCtx)
+	defer ce.close()
+	ce.call(&ci)
+	jsonRet, gasUsed, err := ce.result(bcCtx, stateSet, fn)
+	stateSet.traceReturn(bcCtx, ci.Name, jsonRet, err)
+	return jsonRet, gasUsed, stateSet, err
+}
+
+// result returns the result of the execution by ce with the gas used. If fn is
+// given, the return values are checked against its types.
+func (ce *Executor) result(bcCtx *LBlockchainCtx, stateSet *StateSet, fn *types.Function) (string, uint64, error) {
+	gasUsed, _ := usedGas(bcCtx)
+	if err := ce.limitErr(bcCtx); err != nil {
+		// the contract may catch the error, but the execution is aborted anyway
+		return "", gasUsed, err
+	}
+	if ce.err != nil {
+		return "", gasUsed, ce.err
+	}
+	if stateSet != nil && stateSet.err != nil {
+		return "", gasUsed, stateSet.err
+	}
+	if fn != nil {
+		if err := fn.CheckReturns(ce.jsonRet); err != nil {
+			return "", gasUsed, err
+		}
+	}
+	return ce.jsonRet, gasUsed, nil
+}
+
+// limitErr returns the error of the limit of gas, memory or time exceeded by
+// the execution of ce in bcCtx.
+func (ce *Executor) limitErr(bcCtx *LBlockchainCtx) error {
+	if _, outOfGas := usedGas(bcCtx); outOfGas {
+		return ErrOutOfGas
+	}
+	if ce.L != nil && C.vm_memory_exceeded(ce.L) != 0 {
+		return ErrMemoryLimit
+	}
+	if bcCtx != nil && bcCtx.timedOut != 0 {
+		return ErrQueryTimeout
+	}
+	return nil
+}
+
+// GasUsed returns the gas used by the execution in bcCtx, including the gas
+// of the contracts it calls. It is charged to the payer of the tx.
+func GasUsed(bcCtx *LBlockchainCtx) uint64 {
+	gasUsed, _ := usedGas(bcCtx)
+	return gasUsed
 }
 
-func Create(contractState *state.ContractState, code, contractAddress, txHash []byte, dbTx db.Transaction) error {
+func usedGas(bcCtx *LBlockchainCtx) (uint64, bool) {
+	if bcCtx == nil {
+		return 0, false
+	}
+	if bcCtx.gasUsed > bcCtx.gasLimit {
+		return uint64(bcCtx.gasLimit), true
+	}
+	return uint64(bcCtx.gasUsed), false
+}
+
+// Create deploys the contract in payload, which may have the arguments of the
+// constructor. The constructor runs in bcCtx with the sender of the deploy tx.
+func Create(contractState *state.ContractState, payload, contractAddress, txHash []byte, bcCtx *LBlockchainCtx,
+	dbTx db.Transaction) error {
 	ctrLog.Debug().Str("contractAddress", base58.Encode(contractAddress)).Msg("new contract is deployed")
-	err := contractState.SetCode(code)
+	var stateSet *StateSet
+	if bcCtx != nil {
+		stateKey := C.GoString(bcCtx.stateKey)
+		stateSet = contractMap.lookupSet(stateKey)
+		defer contractMap.unregister(stateKey)
+	}
+	code, args, err := types.DecodeDeployPayload(payload)
+	if err == nil {
+		err = checkCode(code)
+	}
 	if err != nil {
+		setReceipt(dbTx, txHash, contractAddress, "", "", 0, stateSet, err)
+		return err
+	}
+	if err := contractState.SetCode(code); err != nil {
+		return err
+	}
+	if err := recordDeploy(contractState, bcCtx, txHash); err != nil {
 		return err
 	}
-	receipt := types.NewReceipt(contractAddress, "CREATED", "{}")
-	dbTx.Set(txHash, receipt.Bytes())
 
-	return nil
+	jsonRet, gasUsed, err := construct(contractState, args, contractAddress, bcCtx, stateSet)
+	if err == nil && len(jsonRet) == 0 {
+		jsonRet = "{}"
+	}
+	setReceipt(dbTx, txHash, contractAddress, "CREATED", jsonRet, gasUsed, stateSet, err)
+	return err
 }
 
-func Query(contractAddress []byte, contractState *state.ContractState, queryInfo []byte) ([]byte, error) {
+func construct(contractState *state.ContractState, args, contractAddress []byte, bcCtx *LBlockchainCtx,
+	stateSet *StateSet) (string, uint64, error) {
+	var ctorArgs []interface{}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &ctorArgs); err != nil {
+			return "", 0, fmt.Errorf("invalid constructor arguments: %s", err.Error())
+		}
+	}
+	contract := getContract(contractState, contractAddress)
+	if contract == nil {
+		return "", 0, fmt.Errorf("cannot find contract %s", base58.Encode(contractAddress))
+	}
+	stateSet.traceCall(bcCtx, constructorName, ctorArgs)
+	ce := newExecutor(contract, bcCtx)
+	defer ce.close()
+	ce.constructCall(ctorArgs)
+	jsonRet, gasUsed, err := ce.result(bcCtx, stateSet, nil)
+	stateSet.traceReturn(bcCtx, constructorName, jsonRet, err)
+	return jsonRet, gasUsed, err
+}
+
+// Query runs a view function of a contract on a snapshot of the state, so
+// that the queries run in parallel with the block executions and with each
+// other.
+func Query(contractAddress []byte, snapshot *state.Snapshot, queryInfo []byte) ([]byte, error) {
 	var ci types.CallInfo
-	var err error
+	contractState, err := snapshot.OpenContractStateAccount(types.ToAccountID(contractAddress))
+	if err != nil {
+		return nil, err
+	}
 	contract := getContract(contractState, contractAddress)
 	if contract != nil {
 		err = json.Unmarshal(queryInfo, &ci)
@@ -252,33 +519,104 @@ func Query(contractAddress []byte, contractState *state.ContractState, queryInfo
 	if err != nil {
 		return nil, err
 	}
+	fn, err := contract.checkCall(&ci, 0)
+	if err != nil {
+		return nil, err
+	}
 	var ce *Executor
 
-	bcCtx := NewContext(contractState, contractAddress, nil,
-		0, 0, "", false, contractAddress, true)
+	stateSet := newSnapshotStateSet(snapshot)
+	bcCtx := NewContext(stateSet, contractState, contractAddress, nil,
+		0, 0, "", false, contractAddress, true, MaxGasLimit, 0)
+	defer contractMap.unregister(C.GoString(bcCtx.stateKey))
+	if QueryTimeout > 0 {
+		bcCtx.deadline = C.longlong(time.Now().Add(QueryTimeout).UnixNano())
+	}
 	ctrLog.Debug().Str("abi", string(queryInfo)).Msgf("contract %s", base58.Encode(contractAddress))
 	ce = newExecutor(contract, bcCtx)
 	defer ce.close()
 	ce.call(&ci)
 	err = ce.err
+	if limitErr := ce.limitErr(bcCtx); limitErr != nil {
+		err = limitErr
+	} else if err == nil {
+		err = stateSet.err
+	}
+	if err == nil && fn != nil {
+		err = fn.CheckReturns(ce.jsonRet)
+	}
 
 	return []byte(ce.jsonRet), err
 }
 
-func getContract(contractState *state.ContractState, contractAddress []byte) *Contract {
-	val, err := contractState.GetCode()
+// checkCode rejects a contract code which is malformed or refers to one of the
+// globals removed from the sandbox of contracts.
+func checkCode(code []byte) error {
+	if len(code) < 4 {
+		return errInvalidCode
+	}
+	l := binary.LittleEndian.Uint32(code[0:])
+	if l == 0 || uint64(l) > uint64(len(code)-4) {
+		return errInvalidCode
+	}
+	if cErrMsg := C.vm_check_bytecode((*C.char)(unsafe.Pointer(&code[4])), C.size_t(l)); cErrMsg != nil {
+		errMsg := C.GoString(cErrMsg)
+		C.free(unsafe.Pointer(cErrMsg))
+		return errors.New(errMsg)
+	}
+	// the callers of a library run its code as their own
+	abi := new(types.ABI)
+	if err := json.Unmarshal(code[4+l:], abi); err == nil && abi.Library && abi.Upgradable {
+		return errUpgradableLib
+	}
+	return nil
+}
 
-	if err != nil {
+// isLibrary tells if the ABI of the contract declares abi.library.
+func (c *Contract) isLibrary() bool {
+	abi := new(types.ABI)
+	return json.Unmarshal(c.abi, abi) == nil && abi.Library
+}
+
+// checkCall validates ci against the ABI of the contract. It returns the
+// function called, or nil if the ABI of the contract is not typed.
+func (c *Contract) checkCall(ci *types.CallInfo, amount uint64) (*types.Function, error) {
+	if ci.Name == constructorName {
+		return nil, errConstructorCall
+	}
+	abi := new(types.ABI)
+	if err := json.Unmarshal(c.abi, abi); err != nil || !abi.IsTyped() {
+		return nil, nil
+	}
+	fn := abi.GetFunction(ci.Name)
+	if fn == nil {
+		return nil, fmt.Errorf("function %s is not in the ABI", ci.Name)
+	}
+	if amount > 0 && !fn.Payable {
+		return nil, fmt.Errorf("function %s is not payable", ci.Name)
+	}
+	if err := fn.CheckArgs(ci.Args); err != nil {
+		return nil, err
+	}
+	return fn, nil
+}
+
+func getContract(contractState *state.ContractState, contractAddress []byte) *Contract {
+	code := loadCode(contractState)
+	if code == nil {
 		return nil
 	}
-	if len(val) > 0 {
-		l := binary.LittleEndian.Uint32(val[0:])
-		return &Contract{
-			code:    val[4 : 4+l],
-			address: contractAddress[:],
-		}
+	return &Contract{
+		code:    code.code,
+		abi:     code.abi,
+		address: contractAddress[:],
+		key:     contractKey(contractState.GetCodeHash(), contractAddress),
 	}
-	return nil
+}
+
+// contractKey returns the key of the Lua states of a contract in statePool
+func contractKey(codeHash, contractAddress []byte) string {
+	return string(codeHash) + string(contractAddress)
 }
 
 func GetReceipt(txHash []byte) (*types.Receipt, error) {
@@ -289,7 +627,33 @@ func GetReceipt(txHash []byte) (*types.Receipt, error) {
 	return types.NewReceiptFromBytes(val), nil
 }
 
+func verifiedABIKey(codeHash []byte) []byte {
+	return append([]byte("source"), codeHash...)
+}
+
+// SetVerifiedABI stores with the receipts the ABI of a contract code and the
+// source verified to be compiled to the code. GetABI returns it for every
+// contract of the code.
+func SetVerifiedABI(codeHash []byte, abi *types.ABI) error {
+	data, err := json.Marshal(abi)
+	if err != nil {
+		return err
+	}
+	DB.Set(verifiedABIKey(codeHash), data)
+	return nil
+}
+
+// GetABI returns the ABI of a contract, which has the source of the contract
+// if it is verified.
 func GetABI(contractState *state.ContractState, contractAddress []byte) (*types.ABI, error) {
+	if codeHash := contractState.GetCodeHash(); len(codeHash) > 0 {
+		if data := DB.Get(verifiedABIKey(codeHash)); len(data) > 0 {
+			abi := new(types.ABI)
+			if err := json.Unmarshal(data, abi); err == nil {
+				return abi, nil
+			}
+		}
+	}
 	val, err := contractState.GetCode()
 	if err != nil {
 		return nil, err
@@ -307,9 +671,10 @@ func GetABI(contractState *state.ContractState, contractAddress []byte) (*types.
 
 func (sm *stateMap) init() {
 	sm.states = make(map[string]*state.ContractState)
+	sm.sets = make(map[string]*StateSet)
 }
 
-func (sm *stateMap) register(key string, state *state.ContractState) {
+func (sm *stateMap) register(key string, state *state.ContractState, stateSet *StateSet) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
@@ -318,6 +683,7 @@ func (sm *stateMap) register(key string, state *state.ContractState) {
 		ctrLog.Warn().AnErr("err", err)
 	}
 	sm.states[key] = state
+	sm.sets[key] = stateSet
 }
 
 func (sm *stateMap) unregister(key string) {
@@ -330,6 +696,7 @@ func (sm *stateMap) unregister(key string) {
 	}
 
 	delete(sm.states, key)
+	delete(sm.sets, key)
 }
 
 func (sm *stateMap) lookup(key string) *state.ContractState {
@@ -339,6 +706,13 @@ func (sm *stateMap) lookup(key string) *state.ContractState {
 	return sm.states[key]
 }
 
+func (sm *stateMap) lookupSet(key string) *StateSet {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return sm.sets[key]
+}
+
 //export LuaSetDB
 func LuaSetDB(L *LState, stateKey *C.char, key *C.char, value *C.char) C.int {
 	stateKeyString := C.GoString(stateKey)
@@ -347,17 +721,28 @@ func LuaSetDB(L *LState, stateKey *C.char, key *C.char, value *C.char) C.int {
 
 	stateDb := contractMap.lookup(stateKeyString)
 	if stateDb == nil {
-		errMsg := C.CString("[System.LuaSetDB]not found contract state")
-		C.lua_pushstring(L, errMsg)
-		C.free(unsafe.Pointer(errMsg))
+		luaPushError(L, "[System.LuaSetDB]not found contract state")
 		return -1
 	}
 
 	err := stateDb.SetData([]byte(keyString), []byte(valueString))
 	if err != nil {
-		errMsg := C.CString(err.Error())
-		C.lua_pushstring(L, errMsg)
-		C.free(unsafe.Pointer(errMsg))
+		luaPushError(L, err.Error())
+		return -1
+	}
+	return 0
+}
+
+//export LuaDelDB
+func LuaDelDB(L *LState, stateKey *C.char, key *C.char) C.int {
+	stateDb := contractMap.lookup(C.GoString(stateKey))
+	if stateDb == nil {
+		luaPushError(L, "[System.LuaDelDB]not found contract state")
+		return -1
+	}
+
+	if err := stateDb.DeleteData([]byte(C.GoString(key))); err != nil {
+		luaPushError(L, err.Error())
 		return -1
 	}
 	return 0
@@ -370,25 +755,112 @@ func LuaGetDB(L *LState, stateKey *C.char, key *C.char) C.int {
 
 	stateDb := contractMap.lookup(stateKeyString)
 	if stateDb == nil {
-		errMsg := C.CString("[System.LuaGetDB]not found contract state")
-		C.lua_pushstring(L, errMsg)
-		C.free(unsafe.Pointer(errMsg))
+		luaPushError(L, "[System.LuaGetDB]not found contract state")
 		return -1
 	}
 
 	data, err := stateDb.GetData([]byte(keyString))
 	if err != nil {
-		errMsg := C.CString(err.Error())
-		C.lua_pushstring(L, errMsg)
-		C.free(unsafe.Pointer(errMsg))
+		luaPushError(L, err.Error())
 		return -1
 	}
 
 	if data == nil {
 		return 0
 	}
-	dataString := C.CString(string(data))
-	C.lua_pushstring(L, dataString)
-	C.free(unsafe.Pointer(dataString))
+	luaPushBytes(L, data)
+	return 1
+}
+
+//export LuaGetBalance
+func LuaGetBalance(L *LState, stateKey *C.char, account *C.char) C.int {
+	stateSet := contractMap.lookupSet(C.GoString(stateKey))
+	if stateSet == nil {
+		luaPushError(L, "[Contract.LuaGetBalance]not found contract state")
+		return -1
+	}
+	address, err := base58.Decode(C.GoString(account))
+	if err != nil || len(address) == 0 {
+		luaPushError(L, "[Contract.LuaGetBalance]invalid address")
+		return -1
+	}
+	st, err := stateSet.GetAccount(types.ToAccountID(address))
+	if err != nil {
+		luaPushError(L, err.Error())
+		return -1
+	}
+	// a lua number cannot hold every balance
+	luaPushBignum(L, new(big.Int).SetUint64(st.GetBalance()))
 	return 1
 }
+
+//export LuaSendAmount
+func LuaSendAmount(L *LState, stateKey *C.char, contractID *C.char, account *C.char, decimal *C.char) C.int {
+	stateSet := contractMap.lookupSet(C.GoString(stateKey))
+	if stateSet == nil {
+		luaPushError(L, "[Contract.LuaSendAmount]not found contract state")
+		return -1
+	}
+	from, err := base58.Decode(C.GoString(contractID))
+	if err != nil {
+		luaPushError(L, err.Error())
+		return -1
+	}
+	to, err := base58.Decode(C.GoString(account))
+	if err != nil || len(to) == 0 {
+		luaPushError(L, "[Contract.LuaSendAmount]invalid address")
+		return -1
+	}
+	amount, err := strconv.ParseUint(C.GoString(decimal), 10, 64)
+	if err != nil {
+		luaPushError(L, "[Contract.LuaSendAmount]invalid amount")
+		return -1
+	}
+	sender, err := stateSet.GetAccount(types.ToAccountID(from))
+	if err != nil {
+		luaPushError(L, err.Error())
+		return -1
+	}
+	receiver, err := stateSet.GetAccount(types.ToAccountID(to))
+	if err != nil {
+		luaPushError(L, err.Error())
+		return -1
+	}
+	if sender.Balance < amount {
+		luaPushError(L, "[Contract.LuaSendAmount]not enough balance")
+		return -1
+	}
+	sender.Balance = sender.Balance - amount
+	receiver.Balance = receiver.Balance + amount
+	return 0
+}
+
+//export LuaEvent
+func LuaEvent(L *LState, stateKey *C.char, contractID *C.char, txHash *C.char, name *C.char, args *C.char) C.int {
+	stateSet := contractMap.lookupSet(C.GoString(stateKey))
+	if stateSet == nil {
+		luaPushError(L, "[Contract.LuaEvent]not found contract state")
+		return -1
+	}
+	address, err := base58.Decode(C.GoString(contractID))
+	if err != nil {
+		luaPushError(L, err.Error())
+		return -1
+	}
+	hash, err := hex.DecodeString(C.GoString(txHash))
+	if err != nil {
+		luaPushError(L, err.Error())
+		return -1
+	}
+	stateSet.addEvent(&types.Event{
+		ContractAddress: address,
+		EventName:       C.GoString(name),
+		JsonArgs:        C.GoString(args),
+		TxHash:          hash,
+	})
+	return 0
+}
+
+func luaPushError(L *LState, msg string) {
+	luaPushString(L, msg)
+}
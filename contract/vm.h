@@ -5,6 +5,31 @@
 #include <lauxlib.h>
 #include <luajit.h>
 
+#define GAS_HOOK_INSTRUCTIONS   100
+#define GAS_INSTRUCTION         1
+#define GAS_SYSTEM_CALL         10
+#define GAS_GET_ITEM            50
+#define GAS_SET_ITEM            100
+#define GAS_SET_ITEM_BYTE       1
+#define GAS_CONTRACT_CALL       1000
+#define GAS_DEPLOY              5000
+#define GAS_DEPLOY_BYTE         1
+#define GAS_GET_BALANCE         50
+#define GAS_SEND                500
+#define GAS_EVENT               100
+#define GAS_EVENT_BYTE          1
+#define GAS_HASH                50
+#define GAS_HASH_BYTE           1
+#define GAS_ECVERIFY            3000
+#define GAS_ENCODE              10
+#define GAS_ENCODE_BYTE         1
+#define GAS_BIGNUM              10
+#define GAS_BIGNUM_WORD         1
+#define BIGNUM_WORD_DIGITS      19
+#define GAS_MEMORY              1
+#define GAS_MEMORY_BYTES        1024
+#define MEMORY_MIN_CLASS        16
+
 typedef struct blockchain_ctx {
     char *stateKey;
     char *sender;
@@ -15,13 +40,30 @@ typedef struct blockchain_ctx {
     char *node;
     int confirmed;
     int isQuery;
+    unsigned long long gasLimit;
+    unsigned long long gasUsed;
+    int callDepth;
+    unsigned long long amount;
+    int trace;
+    unsigned long long memoryLimit;
+    long long memoryUsed;
+    long long deadline;
+    int timedOut;
+    int library;
 } bc_ctx_t;
 
 lua_State *vm_newstate();
+void vm_closestate(lua_State *L);
+int vm_memory_exceeded(lua_State *L);
+void vm_memory_unlimited(lua_State *L, int on);
 void vm_getfield(lua_State *L, const char *name);
+int vm_getfunction(lua_State *L, const char *name);
+int vm_reset(lua_State *L);
 const char *vm_loadbuff(lua_State *L, const char *code, size_t sz, const char *name, bc_ctx_t *bc_ctx);
 const char *vm_pcall(lua_State *L, int argc, int* nresult);
 const char *vm_get_json_ret(lua_State *L, int nresult);
 const char *vm_tostring(lua_State *L, int idx);
+const char *vm_check_bytecode(const char *code, size_t sz);
+void vm_use_gas(lua_State *L, unsigned long long gas);
 
 #endif /* _VM_H */
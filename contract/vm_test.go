@@ -1,6 +1,8 @@
 package contract
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"io/ioutil"
@@ -8,10 +10,13 @@ import (
 	"path"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aergoio/aergo-lib/db"
+	"github.com/aergoio/aergo/account/key"
 	"github.com/aergoio/aergo/state"
 	"github.com/aergoio/aergo/types"
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/mr-tron/base58/base58"
 )
 
@@ -57,6 +62,7 @@ func init() {
 	tmpDir, _ := ioutil.TempDir("", "vmtest")
 
 	sdb.Init(path.Join(tmpDir, "testDB"))
+	sdb.SetGenesis(&types.Genesis{Block: &types.Block{}})
 	DB = db.NewDB(db.BadgerImpl, path.Join(tmpDir, "receiptDB"))
 
 	var err error
@@ -73,7 +79,8 @@ func init() {
 }
 
 func getContractState(t *testing.T, code string) *state.ContractState {
-	contractState, err := sdb.OpenContractStateAccount(types.ToAccountID(aid))
+	// a new state, as the states applied by querySnapshot are left in sdb
+	contractState, err := sdb.OpenContractState(types.NewState())
 	if err != nil {
 		t.Fatalf("contract state open error : %s\n", err.Error())
 	}
@@ -89,6 +96,31 @@ func getContractState(t *testing.T, code string) *state.ContractState {
 	return contractState
 }
 
+// querySnapshot applies contractState to a new block of testDB and returns a
+// snapshot for the queries. The contract state is opened again, because the
+// committed one cannot be changed anymore.
+func querySnapshot(t *testing.T, testDB *state.ChainStateDB,
+	contractState *state.ContractState) (*state.Snapshot, *state.ContractState) {
+	if err := testDB.CommitContractState(contractState); err != nil {
+		t.Fatalf("contract state commit error : %s\n", err.Error())
+	}
+	latest := testDB.GetSnapshot().BlockInfo()
+	blockNo := latest.BlockNo + 1
+	bs := types.NewBlockState(types.NewBlockInfo(blockNo,
+		types.ToBlockID([]byte(fmt.Sprintf("block%d", blockNo))), latest.BlockHash))
+	before, _ := testDB.GetAccountStateClone(types.ToAccountID(aid))
+	after := types.Clone(*contractState.State).(types.State)
+	bs.PutAccount(types.ToAccountID(aid), before, &after)
+	if err := testDB.Apply(bs); err != nil {
+		t.Fatalf("block state apply error : %s\n", err.Error())
+	}
+	reopened, err := testDB.OpenContractState(contractState.State)
+	if err != nil {
+		t.Fatalf("contract state open error : %s\n", err.Error())
+	}
+	return testDB.GetSnapshot(), reopened
+}
+
 func contractCall(t *testing.T, contractState *state.ContractState, ci string,
 	bcCtx *LBlockchainCtx) {
 	dbTx := DB.NewTx(true)
@@ -115,8 +147,8 @@ func TestContractSystem(t *testing.T) {
 	callInfo := "{\"Name\":\"testState\", \"Args\":[]}"
 	sender, _ := base58.Decode("sender2")
 	contractState := getContractState(t, systemCode)
-	bcCtx := NewContext(contractState, sender, tid, 100, 1234,
-		"node", true, aid, false)
+	bcCtx := NewContext(nil, contractState, sender, tid, 100, 1234,
+		"node", true, aid, false, MaxGasLimit, 0)
 
 	contractCall(t, contractState, callInfo, bcCtx)
 	receipt := types.NewReceiptFromBytes(DB.Get(tid))
@@ -124,9 +156,34 @@ func TestContractSystem(t *testing.T) {
 	if receipt.GetRet() != "[\"sender2\",\"c2b36750\",\"31KcyXb99xYD5tQ9Jpx4BMnhVh9a\",1234,100,999]" {
 		t.Errorf("contract Call ret error :%s\n", receipt.GetRet())
 	}
+	if receipt.GetGasUsed() == 0 {
+		t.Errorf("contract Call gas used is not recorded")
+	}
 
 }
 
+func TestContractOutOfGas(t *testing.T) {
+	callInfo := "{\"Name\":\"testState\", \"Args\":[]}"
+	sender, _ := base58.Decode("sender2")
+	contractState := getContractState(t, systemCode)
+	bcCtx := NewContext(nil, contractState, sender, tid, 100, 1234,
+		"node", true, aid, false, 10, 0)
+
+	dbTx := DB.NewTx(true)
+	err := Call(contractState, []byte(callInfo), aid, tid, bcCtx, dbTx)
+	dbTx.Commit()
+	if err != ErrOutOfGas {
+		t.Fatalf("expected out of gas, got %v", err)
+	}
+	receipt := types.NewReceiptFromBytes(DB.Get(tid))
+	if receipt.GetStatus() != ErrOutOfGas.Error() {
+		t.Errorf("contract Call status error :%s\n", receipt.GetStatus())
+	}
+	if receipt.GetGasUsed() != 10 {
+		t.Errorf("contract Call gas used error :%d\n", receipt.GetGasUsed())
+	}
+}
+
 func TestGetABI(t *testing.T) {
 	contractState := getContractState(t, helloCode)
 	abi, err := GetABI(contractState, aid)
@@ -136,23 +193,59 @@ func TestGetABI(t *testing.T) {
 	t.Log(abi)
 }
 
+func TestVerifiedABI(t *testing.T) {
+	contractState := getContractState(t, helloCode)
+	abi, err := GetABI(contractState, aid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if abi.Source != "" {
+		t.Errorf("unverified abi has the source %q", abi.Source)
+	}
+
+	const src = `function hello(say) return "Hello " .. say end abi.register(hello)`
+	abi.Source = src
+	if err := SetVerifiedABI(contractState.GetCodeHash(), abi); err != nil {
+		t.Fatal(err)
+	}
+	verified, err := GetABI(contractState, aid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified.Source != src || len(verified.Functions) != len(abi.Functions) {
+		t.Errorf("verified abi: source %q, %d functions", verified.Source, len(verified.Functions))
+	}
+
+	// another code has its own abi
+	other := getContractState(t, queryCode)
+	abi, err = GetABI(other, aid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if abi.Source != "" {
+		t.Errorf("abi of another code has the source %q", abi.Source)
+	}
+}
+
 func TestContractQuery(t *testing.T) {
 	queryInfo := "{\"Name\":\"query\", \"Args\":[\"key1\"]}"
 	setInfo := "{\"Name\":\"inc\", \"Args\":[]}"
 
 	contractState := getContractState(t, queryCode)
 
-	ret, err := Query(aid, contractState, []byte(setInfo))
+	snapshot, contractState := querySnapshot(t, sdb, contractState)
+	ret, err := Query(aid, snapshot, []byte(setInfo))
 	if err == nil || !strings.Contains(err.Error(), "not permitted set in query") {
 		t.Errorf("failed check error: %s", err.Error())
 	}
 
-	bcCtx := NewContext(contractState, nil, nil, 100, 1234,
-		"node", true, aid, false)
+	bcCtx := NewContext(nil, contractState, nil, nil, 100, 1234,
+		"node", true, aid, false, MaxGasLimit, 0)
 
 	contractCall(t, contractState, setInfo, bcCtx)
 
-	ret, err = Query(aid, contractState, []byte(queryInfo))
+	snapshot, _ = querySnapshot(t, sdb, contractState)
+	ret, err = Query(aid, snapshot, []byte(queryInfo))
 	if err != nil {
 		t.Errorf("contract query error :%s\n", err.Error())
 	}
@@ -161,3 +254,750 @@ func TestContractQuery(t *testing.T) {
 		t.Errorf("contract query ret error :%s\n", ret)
 	}
 }
+
+func TestCryptoHash(t *testing.T) {
+	if h := cryptoHash([]byte("abc"), cryptoSha256); h != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("sha256 error :%s\n", h)
+	}
+	if h := cryptoHash([]byte(""), cryptoKeccak256); h != "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470" {
+		t.Errorf("keccak256 error :%s\n", h)
+	}
+}
+
+func TestCryptoVerify(t *testing.T) {
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := base58.Encode(key.GenerateAddress(&privKey.PublicKey))
+	hash := cryptoHash([]byte("voucher"), cryptoSha256)
+	msg, _ := hex.DecodeString(hash)
+	sign, err := btcec.SignCompact(btcec.S256(), privKey, msg, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid, err := cryptoVerify(hash, hex.EncodeToString(sign), address)
+	if err != nil || !valid {
+		t.Errorf("ecverify error :%v\n", err)
+	}
+	valid, err = cryptoVerify(cryptoHash([]byte("other"), cryptoSha256), hex.EncodeToString(sign), address)
+	if err != nil || valid {
+		t.Errorf("ecverify must fail with another message\n")
+	}
+	if _, err = cryptoVerify("xyz", hex.EncodeToString(sign), address); err == nil {
+		t.Errorf("ecverify must fail with an invalid message\n")
+	}
+}
+
+func TestContractCrypto(t *testing.T) {
+	contractState := getSourceState(t, `
+function hashes()
+	return crypto.sha256("abc"), crypto.keccak256("")
+end
+function codec()
+	local s = "a\0b"
+	local h = crypto.hexencode(s)
+	return h, crypto.hexdecode(h) == s, crypto.base58decode(crypto.base58encode(s)) == s
+end
+function decodeNul()
+	return crypto.hexdecode("61\0zz")
+end
+function verify(msg, sig, address)
+	return crypto.ecverify(msg, sig, address)
+end
+function verifyNul(msg, sig, address)
+	return crypto.ecverify(msg .. "\0", sig, address)
+end
+function hash(n)
+	return crypto.sha256(string.rep("a", n))
+end
+function base58(n)
+	return #crypto.base58encode(string.rep("a", n)), #crypto.base58decode(string.rep("2", n))
+end
+abi.register(hashes)
+abi.register(codec)
+abi.register(decodeNul)
+abi.register(verify)
+abi.register(verifyNul)
+abi.register(hash)
+abi.register(base58)`)
+
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := base58.Encode(key.GenerateAddress(&privKey.PublicKey))
+	hash := cryptoHash([]byte("voucher"), cryptoSha256)
+	msg, _ := hex.DecodeString(hash)
+	sign, err := btcec.SignCompact(btcec.S256(), privKey, msg, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := fmt.Sprintf(`["%s", "%s", "%s"]`, hash, hex.EncodeToString(sign), address)
+
+	snapshot, contractState := querySnapshot(t, sdb, contractState)
+	for _, c := range []struct{ call, ret string }{
+		{`{"Name":"hashes", "Args":[]}`, `["ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",` +
+			`"c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"]`},
+		{`{"Name":"codec", "Args":[]}`, `["610062",true,true]`},
+		{`{"Name":"verify", "Args":` + args + `}`, `[true]`},
+		{`{"Name":"base58", "Args":[1024]}`, `[1399,750]`},
+	} {
+		ret, err := Query(aid, snapshot, []byte(c.call))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(ret) != c.ret {
+			t.Errorf("crypto ret error :%s, expected %s\n", ret, c.ret)
+		}
+	}
+	// the strings are not cut at a NUL
+	for _, call := range []string{`{"Name":"decodeNul", "Args":[]}`, `{"Name":"verifyNul", "Args":` + args + `}`} {
+		if _, err := Query(aid, snapshot, []byte(call)); err == nil {
+			t.Errorf("%s does not fail", call)
+		}
+	}
+	// base58 takes time quadratic in the length
+	if _, err := Query(aid, snapshot, []byte(`{"Name":"base58", "Args":[1025]}`)); err == nil ||
+		!strings.Contains(err.Error(), "base58 input is longer than 1024 bytes") {
+		t.Errorf("base58 of a long string does not fail: %v", err)
+	}
+
+	// the gas of a hash grows with the length of the data
+	hashGas := func(n int) uint64 {
+		bcCtx := NewContext(nil, contractState, nil, tid, 100, 1234,
+			"node", true, aid, false, MaxGasLimit, 0)
+		contractCall(t, contractState, fmt.Sprintf(`{"Name":"hash", "Args":[%d]}`, n), bcCtx)
+		return types.NewReceiptFromBytes(DB.Get(tid)).GasUsed
+	}
+	if small, large := hashGas(1), hashGas(1001); large < small+1000 {
+		t.Errorf("the gas of a hash is not scaled: %d, %d", small, large)
+	}
+}
+
+func TestCheckCode(t *testing.T) {
+	for _, code := range []string{helloCode, systemCode, queryCode} {
+		rcode, _ := base58.Decode(code)
+		if err := checkCode(rcode); err != nil {
+			t.Errorf("contract code check error :%s\n", err.Error())
+		}
+	}
+	if err := checkCode([]byte{1, 0}); err != errInvalidCode {
+		t.Errorf("expected invalid code, got %v", err)
+	}
+	if err := checkCode([]byte{100, 0, 0, 0, 1}); err != errInvalidCode {
+		t.Errorf("expected invalid code, got %v", err)
+	}
+}
+
+// TestContractDeterminism runs the same calls on two separate states and
+// checks that they end up with identical results and storage.
+func TestContractDeterminism(t *testing.T) {
+	run := func() (string, []byte) {
+		tmpDir, _ := ioutil.TempDir("", "vmdeterminism")
+		defer os.RemoveAll(tmpDir)
+		testDB := state.NewStateDB()
+		if err := testDB.Init(path.Join(tmpDir, "testDB")); err != nil {
+			t.Fatal(err)
+		}
+		if err := testDB.SetGenesis(&types.Genesis{Block: &types.Block{}}); err != nil {
+			t.Fatal(err)
+		}
+		defer testDB.Close()
+
+		contractState, err := testDB.OpenContractStateAccount(types.ToAccountID(aid))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rcode, _ := base58.Decode(queryCode)
+		if err := contractState.SetCode(rcode); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			bcCtx := NewContext(nil, contractState, nil, tid, uint64(100+i), 1234,
+				"node", true, aid, false, MaxGasLimit, 0)
+			contractCall(t, contractState, "{\"Name\":\"inc\", \"Args\":[]}", bcCtx)
+		}
+		snapshot, contractState := querySnapshot(t, testDB, contractState)
+		ret, err := Query(aid, snapshot, []byte("{\"Name\":\"query\", \"Args\":[\"key1\"]}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(ret), contractState.StorageRoot
+	}
+
+	ret1, root1 := run()
+	ret2, root2 := run()
+	if ret1 != "[3]" || ret1 != ret2 {
+		t.Errorf("contract results differ :%s, %s\n", ret1, ret2)
+	}
+	if len(root1) == 0 || !bytes.Equal(root1, root2) {
+		t.Errorf("contract storage roots differ :%x, %x\n", root1, root2)
+	}
+}
+
+func TestContractCheckCall(t *testing.T) {
+	contract := &Contract{
+		abi: []byte(`{"version":"0.2","language":"lua","functions":[
+			{"name":"deposit","payable":true},
+			{"name":"balanceOf","arguments":[{"name":"owner","type":"address"}],"returns":["integer"],"view":true}]}`),
+	}
+	fn, err := contract.checkCall(&types.CallInfo{Name: "balanceOf", Args: []interface{}{accountId}}, 0)
+	if err != nil || fn == nil || !fn.View {
+		t.Errorf("check call error :%v\n", err)
+	}
+	if _, err = contract.checkCall(&types.CallInfo{Name: "balanceOf", Args: []interface{}{float64(1)}}, 0); err == nil {
+		t.Errorf("check call must fail with a wrong type\n")
+	}
+	if _, err = contract.checkCall(&types.CallInfo{Name: "balanceOf", Args: []interface{}{accountId}}, 10); err == nil {
+		t.Errorf("check call must fail with an amount to a function not payable\n")
+	}
+	if _, err = contract.checkCall(&types.CallInfo{Name: "deposit"}, 10); err != nil {
+		t.Errorf("check call error :%v\n", err)
+	}
+	if _, err = contract.checkCall(&types.CallInfo{Name: "withdraw"}, 0); err == nil {
+		t.Errorf("check call must fail with a function not in the abi\n")
+	}
+
+	// the calls to a contract with an untyped abi are not validated
+	contract.abi = []byte(`{"version":"0.1","language":"lua","functions":[{"name":"hello"}]}`)
+	if fn, err = contract.checkCall(&types.CallInfo{Name: "other"}, 10); fn != nil || err != nil {
+		t.Errorf("check call error :%v\n", err)
+	}
+}
+
+func TestContractCreate(t *testing.T) {
+	contractState, err := sdb.OpenContractState(types.NewState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := base58.Decode(helloCode)
+
+	dbTx := DB.NewTx(true)
+	err = Create(contractState, types.EncodeDeployPayload(code, nil), aid, tid, nil, dbTx)
+	dbTx.Commit()
+	if err != nil {
+		t.Fatalf("contract Create error : %s\n", err.Error())
+	}
+	receipt := types.NewReceiptFromBytes(DB.Get(tid))
+	if receipt.GetStatus() != "CREATED" {
+		t.Errorf("contract Create status error :%s\n", receipt.GetStatus())
+	}
+
+	// hello has no constructor to take the arguments
+	dbTx = DB.NewTx(true)
+	err = Create(contractState, types.EncodeDeployPayload(code, []byte(`["arg"]`)), aid, tid, nil, dbTx)
+	dbTx.Commit()
+	if err != errNoConstructor {
+		t.Errorf("expected no constructor, got %v", err)
+	}
+
+	// the code must be in a deploy payload
+	dbTx = DB.NewTx(true)
+	err = Create(contractState, code, aid, tid, nil, dbTx)
+	dbTx.Commit()
+	if err != types.ErrInvalidDeployPayload {
+		t.Errorf("expected an invalid deploy payload, got %v", err)
+	}
+}
+
+func TestContractUpgrade(t *testing.T) {
+	contractState, err := sdb.OpenContractState(types.NewState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := base58.Decode(helloCode)
+	dbTx := DB.NewTx(true)
+	err = Create(contractState, types.EncodeDeployPayload(code, nil), aid, tid, nil, dbTx)
+	dbTx.Commit()
+	if err != nil {
+		t.Fatalf("contract Create error : %s\n", err.Error())
+	}
+
+	info, err := GetContractInfo(contractState, aid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Versions) != 1 || !bytes.Equal(info.Versions[0].CodeHash, contractState.CodeHash) {
+		t.Errorf("contract version error :%v\n", info.Versions)
+	}
+	if info.Upgradable {
+		t.Errorf("hello must not be upgradable\n")
+	}
+
+	// hello is not declared upgradable
+	newCode, _ := base58.Decode(queryCode)
+	dbTx = DB.NewTx(true)
+	err = Upgrade(NewStateSet(sdb, nil), contractState, newCode, aid, nil, tid, 1, dbTx)
+	dbTx.Commit()
+	if err != errNotUpgradable {
+		t.Errorf("expected not upgradable, got %v", err)
+	}
+	receipt := types.NewReceiptFromBytes(DB.Get(tid))
+	if receipt.GetStatus() != errNotUpgradable.Error() {
+		t.Errorf("contract Upgrade status error :%s\n", receipt.GetStatus())
+	}
+}
+
+func TestContractTrace(t *testing.T) {
+	setInfo := "{\"Name\":\"inc\", \"Args\":[]}"
+
+	contractState := getContractState(t, queryCode)
+	bs := types.NewBlockState(types.NewBlockInfo(1, types.BlockID{}, types.BlockID{}))
+	bs.Trace = true
+	stateSet := NewStateSet(sdb, bs)
+	bcCtx := NewContext(stateSet, contractState, nil, tid, 100, 1234,
+		"node", true, aid, false, MaxGasLimit, 0)
+
+	contractCall(t, contractState, setInfo, bcCtx)
+	receipt := types.NewReceiptFromBytes(DB.Get(tid))
+	steps := receipt.GetSteps()
+	if len(steps) < 3 {
+		t.Fatalf("too few trace steps: %v", steps)
+	}
+	if steps[0].Op != traceCall || steps[0].Function != "inc" {
+		t.Errorf("first step is not the call: %v", steps[0])
+	}
+	if last := steps[len(steps)-1]; last.Op != traceReturn || last.Message != "" {
+		t.Errorf("last step is not the return: %v", last)
+	}
+	var gas uint64
+	var enter, set bool
+	for _, step := range steps {
+		gas += step.Gas
+		switch step.Op {
+		case traceEnter:
+			enter = true
+		case traceSetItem:
+			set = true
+		}
+	}
+	if !enter || !set {
+		t.Errorf("function entries or storage writes are not traced: %v", steps)
+	}
+	if gas != receipt.GetGasUsed() {
+		t.Errorf("gas of the steps %d, but %d used", gas, receipt.GetGasUsed())
+	}
+
+	// an execution without tracing has no step
+	contractCall(t, contractState, setInfo, nil)
+	if receipt := types.NewReceiptFromBytes(DB.Get(tid)); len(receipt.GetSteps()) != 0 {
+		t.Errorf("untraced execution has steps: %v", receipt.GetSteps())
+	}
+}
+
+func TestContractPrint(t *testing.T) {
+	contractState := getSourceState(t, `
+function hello()
+	print("hello", 1)
+end
+abi.register(hello)`)
+	bs := types.NewBlockState(types.NewBlockInfo(1, types.BlockID{}, types.BlockID{}))
+	bs.Trace = true
+	bcCtx := NewContext(NewStateSet(sdb, bs), contractState, nil, tid, 100, 1234,
+		"node", true, aid, false, MaxGasLimit, 0)
+
+	// print is system.print, not the one of lua writing to the stdout
+	contractCall(t, contractState, `{"Name":"hello", "Args":[]}`, bcCtx)
+	var printed []string
+	for _, step := range types.NewReceiptFromBytes(DB.Get(tid)).GetSteps() {
+		if step.Op == tracePrint {
+			printed = append(printed, step.Message)
+		}
+	}
+	if len(printed) != 1 || printed[0] != "hello\t1" {
+		t.Errorf("print is not traced: %v", printed)
+	}
+}
+
+func TestCodeCache(t *testing.T) {
+	contractState := getContractState(t, helloCode)
+	contract := getContract(contractState, aid)
+	if contract == nil {
+		t.Fatal("cannot get the contract")
+	}
+	if _, ok := codeCache.Get(string(contractState.GetCodeHash())); !ok {
+		t.Fatal("code is not cached")
+	}
+	// the same code is shared by the contracts having it
+	other, err := sdb.OpenContractState(&types.State{CodeHash: contractState.GetCodeHash()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := getContract(other, aid); c == nil || &c.code[0] != &contract.code[0] {
+		t.Error("cached code is not used")
+	}
+
+	contractState = getContractState(t, queryCode)
+	if c := getContract(contractState, aid); c == nil || bytes.Equal(c.code, contract.code) {
+		t.Error("code of another hash is returned")
+	}
+	if getContract(&state.ContractState{State: &types.State{}}, aid) != nil {
+		t.Error("contract without code is returned")
+	}
+}
+
+// BenchmarkNewLState measures making a Lua state for every execution, which
+// the pool takes off the execution path.
+func BenchmarkNewLState(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		newLState().Close()
+	}
+}
+
+func BenchmarkPooledLState(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		statePool.put("", statePool.get(""))
+	}
+}
+
+// BenchmarkLoadCode measures reading a contract code from the state db for
+// every execution, which the cache saves.
+func BenchmarkLoadCode(b *testing.B) {
+	rcode, _ := base58.Decode(queryCode)
+	contractState, _ := sdb.OpenContractStateAccount(types.ToAccountID(aid))
+	contractState.SetCode(rcode)
+	st := &types.State{CodeHash: contractState.GetCodeHash()}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cs, _ := sdb.OpenContractState(st)
+		if _, err := cs.GetCode(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCachedCode(b *testing.B) {
+	rcode, _ := base58.Decode(queryCode)
+	contractState, _ := sdb.OpenContractStateAccount(types.ToAccountID(aid))
+	contractState.SetCode(rcode)
+	st := &types.State{CodeHash: contractState.GetCodeHash()}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cs, _ := sdb.OpenContractState(st)
+		if getContract(cs, aid) == nil {
+			b.Fatal("cannot get the contract")
+		}
+	}
+}
+
+func BenchmarkContractCall(b *testing.B) {
+	benchmarkContractCall(b)
+}
+
+// BenchmarkContractCallNewState is the baseline of BenchmarkContractCall:
+// every call loads the contract in a new Lua state.
+func BenchmarkContractCallNewState(b *testing.B) {
+	pool := statePool
+	statePool = newLStatePool(0)
+	defer func() { statePool = pool }()
+	benchmarkContractCall(b)
+}
+
+func benchmarkContractCall(b *testing.B) {
+	rcode, _ := base58.Decode(queryCode)
+	contractState, _ := sdb.OpenContractStateAccount(types.ToAccountID(aid))
+	contractState.SetCode(rcode)
+	ci := []byte("{\"Name\":\"inc\", \"Args\":[]}")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bcCtx := NewContext(nil, contractState, nil, tid, 100, 1234,
+			"node", true, aid, false, MaxGasLimit, 0)
+		dbTx := DB.NewTx(true)
+		if err := Call(contractState, ci, aid, tid, bcCtx, dbTx); err != nil {
+			b.Fatal(err)
+		}
+		dbTx.Discard()
+	}
+}
+
+// TestContractStateReuse checks that an execution on a reused Lua state sees
+// nothing of the previous ones, and uses the same gas.
+func TestContractStateReuse(t *testing.T) {
+	contractState := getSourceState(t, `
+count = 0
+function inc()
+	count = count + 1
+	string.upper = nil
+	abi.register(hidden)
+	return count, type(string.upper), getmetatable("")
+end
+function hidden()
+	return "hidden"
+end
+abi.register(inc)`)
+
+	var gasUsed uint64
+	for i := 0; i < 3; i++ {
+		bcCtx := NewContext(nil, contractState, nil, tid, 100, 1234,
+			"node", true, aid, false, MaxGasLimit, 0)
+		contractCall(t, contractState, `{"Name":"inc", "Args":[]}`, bcCtx)
+		receipt := types.NewReceiptFromBytes(DB.Get(tid))
+		if receipt.GetRet() != `[1,"function",false]` {
+			t.Errorf("call %d sees the globals of a previous call: %s", i, receipt.GetRet())
+		}
+		if i > 0 && receipt.GasUsed != gasUsed {
+			t.Errorf("call %d uses %d gas instead of %d", i, receipt.GasUsed, gasUsed)
+		}
+		gasUsed = receipt.GasUsed
+	}
+
+	bcCtx := NewContext(nil, contractState, nil, tid, 100, 1234,
+		"node", true, aid, false, MaxGasLimit, 0)
+	dbTx := DB.NewTx(true)
+	err := Call(contractState, []byte(`{"Name":"hidden", "Args":[]}`), aid, tid, bcCtx, dbTx)
+	dbTx.Commit()
+	if err == nil {
+		t.Error("a function registered by a previous call is called")
+	}
+}
+
+// getSourceState returns a contract state having the Lua source as its code.
+// The vm loads a source as well as a bytecode.
+func getSourceState(t *testing.T, src string) *state.ContractState {
+	contractState, err := sdb.OpenContractState(types.NewState())
+	if err != nil {
+		t.Fatalf("contract state open error : %s\n", err.Error())
+	}
+	code := make([]byte, 4, 4+len(src))
+	binary.LittleEndian.PutUint32(code, uint32(len(src)))
+	code = append(code, src...)
+	code = append(code, `{"version":"0.1","language":"lua"}`...)
+	if err := contractState.SetCode(code); err != nil {
+		t.Fatalf("contract SetCode error : %s\n", err.Error())
+	}
+	return contractState
+}
+
+func TestContractStateVariables(t *testing.T) {
+	contractState := getSourceState(t, `
+state.var {
+	owner = state.value(),
+	balances = state.map(),
+	holders = state.array(),
+}
+function set(k, v)
+	balances[k] = v
+	holders:append(k)
+	owner:set(k)
+end
+function get(k)
+	return balances[k], holders:length(), holders[1], owner:get()
+end
+function outOfRange()
+	return holders[5]
+end
+function declare()
+	state.var { late = state.value() }
+end
+function reserved()
+	system.setItem("_sv_owner", "x")
+end
+function remove(k)
+	balances[k] = nil
+	return holders:pop()
+end
+abi.register(set)
+abi.register(get)
+abi.register(outOfRange)
+abi.register(declare)
+abi.register(reserved)
+abi.register(remove)`)
+
+	bcCtx := NewContext(nil, contractState, nil, tid, 100, 1234,
+		"node", true, aid, false, MaxGasLimit, 0)
+	contractCall(t, contractState, `{"Name":"set", "Args":["alice", 10]}`, bcCtx)
+
+	snapshot, contractState := querySnapshot(t, sdb, contractState)
+	ret, err := Query(aid, snapshot, []byte(`{"Name":"get", "Args":["alice"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(ret) != `[10,1,"alice","alice"]` {
+		t.Errorf("state variables ret error :%s\n", ret)
+	}
+	data, err := contractState.GetData(types.StateVarKey(aid, "balances", "alice"))
+	if err != nil || string(data) != "10" {
+		t.Errorf("map entry is not stored under its key: %s, %v", data, err)
+	}
+	data, err = contractState.GetData(types.StateVarLengthKey(aid, "holders"))
+	if err != nil || string(data) != "1" {
+		t.Errorf("array length is not stored under its key: %s, %v", data, err)
+	}
+
+	for _, fname := range []string{"outOfRange", "declare", "reserved"} {
+		bcCtx = NewContext(nil, contractState, nil, tid, 100, 1234,
+			"node", true, aid, false, MaxGasLimit, 0)
+		dbTx := DB.NewTx(true)
+		err := Call(contractState, []byte(`{"Name":"`+fname+`", "Args":[]}`), aid, tid, bcCtx, dbTx)
+		dbTx.Commit()
+		if err == nil {
+			t.Errorf("%s does not fail", fname)
+		}
+	}
+
+	// the removed entries and the popped elements leave the storage
+	bcCtx = NewContext(nil, contractState, nil, tid, 100, 1234,
+		"node", true, aid, false, MaxGasLimit, 0)
+	contractCall(t, contractState, `{"Name":"remove", "Args":["alice"]}`, bcCtx)
+	if ret := types.NewReceiptFromBytes(DB.Get(tid)).Ret; ret != `["alice"]` {
+		t.Errorf("pop ret error :%s\n", ret)
+	}
+	for _, key := range [][]byte{types.StateVarKey(aid, "balances", "alice"), types.StateVarKey(aid, "holders", "1")} {
+		if data, err := contractState.GetData(key); err != nil || data != nil {
+			t.Errorf("removed item is left in the storage: %s, %v", data, err)
+		}
+	}
+	data, err = contractState.GetData(types.StateVarLengthKey(aid, "holders"))
+	if err != nil || string(data) != "0" {
+		t.Errorf("array length is not updated by pop: %s, %v", data, err)
+	}
+}
+
+func TestContractBignum(t *testing.T) {
+	contractState := getSourceState(t, `
+state.var { balances = state.map() }
+local decimals = bignum.number("1000000000000000000")
+local function balance(who)
+	return balances[who] or bignum.number(0)
+end
+function mint(to, amount)
+	balances[to] = balance(to) + amount
+end
+function transfer(from, to, amount)
+	assert(bignum.isbignum(amount), "amount is not a bignum")
+	assert(balance(from) >= amount, "not enough balance")
+	balances[from] = balance(from) - amount
+	balances[to] = balance(to) + amount
+end
+function balanceOf(who)
+	return balance(who)
+end
+function supply()
+	return bignum.number(21000000) * decimals
+end
+function ops()
+	return tostring(bignum.number(7) / 2), tostring(-bignum.number(7) % 2),
+		bignum.compare(1, bignum.number(2)), bignum.number(2) ^ 10 .. ""
+end
+function divZero()
+	return bignum.number(1) / 0
+end
+function overflow()
+	return bignum.number(2) ^ 2000
+end
+function fraction()
+	return bignum.number("1.5")
+end
+function mixed()
+	local ok = pcall(function() return bignum.number(1) < 2 end)
+	return bignum.number(1) == 1, ok, bignum.compare(bignum.number(1), 1), bignum.number(1) == bignum.number(1)
+end
+function square(s)
+	local x = bignum.number(s)
+	return x * x
+end
+abi.register(mint)
+abi.register(transfer)
+abi.register(balanceOf)
+abi.register(supply)
+abi.register(ops)
+abi.register(divZero)
+abi.register(overflow)
+abi.register(fraction)
+abi.register(mixed)
+abi.register(square)`)
+
+	bcCtx := NewContext(nil, contractState, nil, tid, 100, 1234,
+		"node", true, aid, false, MaxGasLimit, 0)
+	contractCall(t, contractState, `{"Name":"mint", "Args":["alice", {"_bignum":"10000000000000000000000001"}]}`, bcCtx)
+	bcCtx = NewContext(nil, contractState, nil, tid, 100, 1234,
+		"node", true, aid, false, MaxGasLimit, 0)
+	contractCall(t, contractState, `{"Name":"transfer", "Args":["alice", "bob", {"_bignum":"1500000000000000000"}]}`, bcCtx)
+
+	snapshot, contractState := querySnapshot(t, sdb, contractState)
+	for _, c := range []struct{ call, ret string }{
+		{`{"Name":"balanceOf", "Args":["alice"]}`, `[{"_bignum":"9999998500000000000000001"}]`},
+		{`{"Name":"balanceOf", "Args":["bob"]}`, `[{"_bignum":"1500000000000000000"}]`},
+		{`{"Name":"supply", "Args":[]}`, `[{"_bignum":"21000000000000000000000000"}]`},
+		{`{"Name":"ops", "Args":[]}`, `["3","-1",-1,"1024"]`},
+		// a bignum is compared with a number by bignum.compare only
+		{`{"Name":"mixed", "Args":[]}`, `[false,false,0,true]`},
+	} {
+		ret, err := Query(aid, snapshot, []byte(c.call))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(ret) != c.ret {
+			t.Errorf("bignum ret error :%s, expected %s\n", ret, c.ret)
+		}
+	}
+	data, err := contractState.GetData(types.StateVarKey(aid, "balances", "bob"))
+	if err != nil || string(data) != `{"_bignum":"1500000000000000000"}` {
+		t.Errorf("bignum is not stored as JSON: %s, %v", data, err)
+	}
+
+	for _, fname := range []string{"divZero", "overflow", "fraction"} {
+		if _, err := Query(aid, snapshot, []byte(`{"Name":"`+fname+`", "Args":[]}`)); err == nil {
+			t.Errorf("%s does not fail", fname)
+		}
+	}
+
+	// the gas grows with the size of the operands
+	square := func(s string) uint64 {
+		bcCtx := NewContext(nil, contractState, nil, tid, 100, 1234,
+			"node", true, aid, false, MaxGasLimit, 0)
+		contractCall(t, contractState, `{"Name":"square", "Args":["`+s+`"]}`, bcCtx)
+		return types.NewReceiptFromBytes(DB.Get(tid)).GasUsed
+	}
+	small, large := square("2"), square("1"+strings.Repeat("0", 300))
+	// 16 words squared instead of one, and the parse of 16 words
+	if large < small+16*16 {
+		t.Errorf("the gas of large bignums is not scaled: %d, %d", small, large)
+	}
+}
+
+func TestContractLimits(t *testing.T) {
+	defer SetQueryTimeout(QueryTimeout)
+	SetQueryTimeout(time.Millisecond)
+
+	contractState := getSourceState(t, `
+function grow()
+	local t = {}
+	for i = 1, 1000000 do
+		t[i] = string.rep("x", 100) .. i
+	end
+	return #t
+end
+function catch()
+	return pcall(grow)
+end
+function loop()
+	while true do end
+end
+abi.register(grow)
+abi.register(catch)
+abi.register(loop)`)
+
+	for _, fname := range []string{"grow", "catch"} {
+		bcCtx := NewContext(nil, contractState, nil, tid, 100, 1234,
+			"node", true, aid, false, MaxGasLimit, 0)
+		bcCtx.memoryLimit = 1 << 20
+		dbTx := DB.NewTx(true)
+		err := Call(contractState, []byte(`{"Name":"`+fname+`", "Args":[]}`), aid, tid, bcCtx, dbTx)
+		dbTx.Commit()
+		if err != ErrMemoryLimit {
+			t.Errorf("%s does not exceed the memory limit: %v", fname, err)
+		}
+		receipt := types.NewReceiptFromBytes(DB.Get(tid))
+		if receipt.GetStatus() != ErrMemoryLimit.Error() {
+			t.Errorf("wrong status of %s: %s", fname, receipt.GetStatus())
+		}
+	}
+
+	snapshot, _ := querySnapshot(t, sdb, contractState)
+	if _, err := Query(aid, snapshot, []byte(`{"Name":"loop", "Args":[]}`)); err != ErrQueryTimeout {
+		t.Errorf("query does not time out: %v", err)
+	}
+}
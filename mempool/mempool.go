@@ -216,6 +216,7 @@ func (mp *MemPool) removeOnBlockArrival(blockNo types.BlockNo, txs ...*types.Tx)
 	accSet := map[types.AccountID]bool{}
 	mp.Lock()
 	defer mp.Unlock()
+	mp.curBestBlockNo = blockNo
 
 	// better to have account slice
 	for _, v := range txs {
@@ -243,10 +244,30 @@ func (mp *MemPool) removeOnBlockArrival(blockNo types.BlockNo, txs ...*types.Tx)
 			accSet[id] = true
 		}
 	}
+	mp.removeExpired(blockNo + 1)
 	return nil
 
 }
 
+// removeExpired evicts transactions which can not be included in the block of
+// blockNo or later
+func (mp *MemPool) removeExpired(blockNo types.BlockNo) {
+	for id, list := range mp.pool {
+		diff, delTxs := list.FilterByExpiry(blockNo)
+		if len(delTxs) == 0 {
+			continue
+		}
+		mp.orphan -= diff
+		if list.Empty() {
+			delete(mp.pool, id)
+		}
+		for _, tx := range delTxs {
+			delete(mp.cache, types.ToTxID(tx.Hash))
+		}
+		mp.Debug().Int("count", len(delTxs)).Uint64("blockNo", blockNo).Msg("expired txs evicted")
+	}
+}
+
 // check tx sanity
 // TODO sender's signiture
 // check if sender has enough balance
@@ -276,6 +297,26 @@ func (mp *MemPool) validate(tx *types.Tx) error {
 	if tx.GetBody().GetNonce() <= ns.Nonce {
 		return message.ErrTxNonceTooLow
 	}
+	if tx.GetBody().IsExpiredAt(mp.curBestBlockNo + 1) {
+		return message.ErrTxExpired
+	}
+	payerState := ns
+	if payer := tx.GetBody().GetFeePayer(); len(payer) > 0 {
+		payerState, err = mp.getAccountState(payer, false)
+		if err != nil {
+			return err
+		}
+	}
+	if tx.GetBody().RunsContract() && tx.GetBody().GetLimit() == 0 {
+		return message.ErrTxFormatInvalid
+	}
+	fee, err := tx.GetBody().MaxFee()
+	if err != nil {
+		return message.ErrTxFormatInvalid
+	}
+	if fee > payerState.Balance {
+		return message.ErrInsufficientBalance
+	}
 	return nil
 }
 
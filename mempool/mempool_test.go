@@ -12,6 +12,7 @@ import (
 
 	"github.com/aergoio/aergo/account/key"
 	"github.com/aergoio/aergo/config"
+	"github.com/aergoio/aergo/message"
 	"github.com/aergoio/aergo/types"
 	"github.com/btcsuite/btcd/btcec"
 )
@@ -158,6 +159,57 @@ func TestInvalidTransactions(t *testing.T) {
 		t.Errorf("put invalid tx should be failed")
 	}
 }
+func TestFeePayerTransaction(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+	tx := genTx(0, 1, 1, 1)
+	tx.Body.FeePayer = accs[2]
+	tx.Body.Limit = 100
+	tx.Body.Price = 1
+	key.SignTx(tx, sign[0])
+
+	err := pool.put(tx)
+	if err == nil {
+		t.Errorf("put tx without sign of fee payer should be failed")
+	}
+
+	key.SignFeePayerTx(tx, sign[2])
+	err = pool.put(tx)
+	if err != nil {
+		t.Errorf("put tx with fee payer failed: %s", err)
+	}
+
+	tx = genTx(1, 1, 1, 1)
+	tx.Body.FeePayer = accs[3]
+	tx.Body.Limit = defaultBalance + 1
+	tx.Body.Price = 1
+	key.SignTx(tx, sign[1])
+	key.SignFeePayerTx(tx, sign[3])
+	err = pool.put(tx)
+	if err != message.ErrInsufficientBalance {
+		t.Errorf("put tx should be failed by balance of fee payer, but %v", err)
+	}
+
+	// the fee overflows
+	tx = genTx(1, 1, 1, 1)
+	tx.Body.Limit = 1 << 32
+	tx.Body.Price = 1 << 32
+	key.SignTx(tx, sign[1])
+	err = pool.put(tx)
+	if err != message.ErrTxFormatInvalid {
+		t.Errorf("put tx should be failed by the overflow of the fee, but %v", err)
+	}
+
+	// a contract tx must have a gas limit
+	tx = genTx(1, 1, 1, 1)
+	tx.Body.Payload = []byte(`{"Name":"f"}`)
+	key.SignTx(tx, sign[1])
+	err = pool.put(tx)
+	if err != message.ErrTxFormatInvalid {
+		t.Errorf("put contract tx without gas limit should be failed, but %v", err)
+	}
+}
+
 func TestOrphanTransaction(t *testing.T) {
 	//	t.Errorf("Sum was incorrect, ")
 
@@ -370,6 +422,49 @@ func TestBasicDeleteOnBlockConnect(t *testing.T) {
 	}
 }
 
+func genTxWithWindow(acc int, nonce uint64, validAfter, validUntil types.BlockNo) *types.Tx {
+	tx := genTx(acc, 0, nonce, 1)
+	tx.Body.ValidAfter = validAfter
+	tx.Body.ValidUntil = validUntil
+	key.SignTx(tx, sign[acc])
+	return tx
+}
+
+func TestValidityWindow(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+
+	if err := pool.put(genTxWithWindow(0, 1, 0, 1)); err != message.ErrTxExpired {
+		t.Errorf("put expired tx should be failed, but %v", err)
+	}
+
+	expiring := genTxWithWindow(0, 1, 0, 3)
+	following := genTxWithWindow(0, 2, 0, 0)
+	notYet := genTxWithWindow(1, 1, 10, 0)
+	errs := pool.puts(expiring, following, notYet)
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("put tx failed: %s", err)
+		}
+	}
+
+	pool.removeOnBlockArrival(2)
+	if ps, o := pool.Size(); ps != 3 || o != 0 {
+		t.Errorf("pool should contain 3 txs without orphan, %d(%d)", ps, o)
+	}
+
+	pool.removeOnBlockArrival(3)
+	if pool.exists(expiring.Hash) != nil {
+		t.Error("expired tx should be evicted")
+	}
+	if pool.exists(following.Hash) == nil || pool.exists(notYet.Hash) == nil {
+		t.Error("valid tx should not be evicted")
+	}
+	if ps, o := pool.Size(); ps != 2 || o != 1 {
+		t.Errorf("tx following expired one should be orphan, %d(%d)", ps, o)
+	}
+}
+
 // suppose txs appended with orphan
 //
 func TestDeleteInvokeRearrange(t *testing.T) {
@@ -6,6 +6,7 @@
 package mempool
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/aergoio/aergo/message"
@@ -50,6 +51,10 @@ func (tl *TxList) Empty() bool {
 func (tl *TxList) Put(tx *types.Tx) (int, error) {
 	tl.Lock()
 	defer tl.Unlock()
+	return tl.put(tx)
+}
+
+func (tl *TxList) put(tx *types.Tx) (int, error) {
 	nonce := tx.GetBody().GetNonce()
 	if nonce < tl.min {
 		return 0, message.ErrTxNonceTooLow
@@ -107,6 +112,37 @@ func (tl *TxList) SetMinNonce(n uint64) (int, []*types.Tx) {
 	return delOrphan, delTxs
 }
 
+// FilterByExpiry will evict transactions that can not be included in the block of blockNo
+// transactions following an evicted one become orphans
+func (tl *TxList) FilterByExpiry(blockNo types.BlockNo) (int, []*types.Tx) {
+	tl.Lock()
+	defer tl.Unlock()
+
+	var alive, delTxs []*types.Tx
+	for _, tx := range tl.getAll() {
+		if tx.GetBody().IsExpiredAt(blockNo) {
+			delTxs = append(delTxs, tx)
+		} else {
+			alive = append(alive, tx)
+		}
+	}
+	if len(delTxs) == 0 {
+		return 0, nil
+	}
+	orphan := len(alive) + len(delTxs) - tl.len()
+
+	sort.Slice(alive, func(i, j int) bool {
+		return alive[i].GetBody().GetNonce() < alive[j].GetBody().GetNonce()
+	})
+	tl.list = nil
+	tl.deps = map[uint64][]*types.Tx{}
+	tl.parent = map[uint64]uint64{}
+	for _, tx := range alive {
+		tl.put(tx) // nolint: errcheck
+	}
+	return orphan - (len(alive) - tl.len()), delTxs
+}
+
 // FilterByPrice will evict transactions that needs more amount than balance
 func (tl *TxList) FilterByPrice(balance uint64) error {
 	tl.Lock()
@@ -125,6 +161,10 @@ func (tl *TxList) Get() []*types.Tx {
 func (tl *TxList) GetAll() []*types.Tx {
 	tl.Lock()
 	defer tl.Unlock()
+	return tl.getAll()
+}
+
+func (tl *TxList) getAll() []*types.Tx {
 	var all []*types.Tx
 	all = append(all, tl.list...)
 	for _, v := range tl.deps {
@@ -11,6 +11,10 @@ var (
 	ErrCouldNotRecoverPubKey  = errors.New("could not recover pubkey from sign")
 	ErrShouldUnlockAccount    = errors.New("should unlock account first")
 	ErrWrongAddressOrPassWord = errors.New("address or password is incorrect")
+	ErrInvalidMultiSig        = errors.New("invalid multi-signature threshold or pubkeys")
+	ErrNotMultiSigMember      = errors.New("key is not a member of multi-signature account")
+	ErrAlreadySigned          = errors.New("key already signed the transaction")
+	ErrNotEnoughSign          = errors.New("not enough signatures for multi-signature account")
 )
 
 const AccountsSvc = "AccountsSvc"
@@ -83,13 +83,12 @@ type GetABIRsp struct {
 	Err error
 }
 
-type GetQuery struct {
-	Contract  []byte
-	Queryinfo []byte
+type GetContractInfo struct {
+	Contract []byte
 }
-type GetQueryRsp struct {
-	Result []byte
-	Err    error
+type GetContractInfoRsp struct {
+	Info *types.ContractInfo
+	Err  error
 }
 
 // SyncBlockState is request to sync from remote peer. It returns sync result.
@@ -30,6 +30,9 @@ var (
 	//ErrTxNonceTooLow is returned by MemPool Service if transaction's nonce is already existed in block
 	ErrTxNonceTooLow = errors.New("nonce is too low")
 
+	//ErrTxExpired is returned by MemPool Service if transaction can not be included in any following block
+	ErrTxExpired = errors.New("tx is expired")
+
 	//ErrTxNonceToohigh is for internal use only
 	ErrTxNonceToohigh = errors.New("nonce is too high")
 )
@@ -459,6 +459,12 @@ func (s *Trie) Get(key []byte) ([]byte, error) {
 	return s.get(s.Root, key, s.TrieHeight)
 }
 
+// GetWithRoot fetches the value of a key by going down a past trie root, so
+// that the values are read as they were when the root was committed.
+func (s *Trie) GetWithRoot(key, root []byte) ([]byte, error) {
+	return s.get(root, key, s.TrieHeight)
+}
+
 // get fetches the value of a key given a trie root
 func (s *Trie) get(root []byte, key []byte, height uint64) ([]byte, error) {
 	if bytes.Equal(root, s.defaultHashes[height]) {
@@ -559,6 +565,5 @@ func (s *Trie) Commit() error {
 		s.pastTries = append(s.pastTries, s.Root)
 	}
 	s.db.commit()
-	s.db.updatedNodes = make(map[Hash][]byte, len(s.db.updatedNodes)*2)
 	return nil
 }
@@ -39,4 +39,7 @@ func (db *CacheDB) commit() {
 		txn.Set(node[:], value)
 	}
 	txn.Commit()
+	// the nodes are reset under the lock, as they are read by the gets
+	// running with the updates
+	db.updatedNodes = make(map[Hash][]byte, len(db.updatedNodes)*2)
 }
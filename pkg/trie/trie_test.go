@@ -280,6 +280,27 @@ func TestTrieCommit(t *testing.T) {
 	os.RemoveAll(".aergo")
 }
 
+func TestTrieGetWithRoot(t *testing.T) {
+	smt := NewTrie(32, hash, nil)
+	keys := getFreshData(10, 32)
+	values := getFreshData(10, 32)
+	root, _ := smt.Update(keys, values)
+	root = append([]byte{}, root...)
+
+	newValues := getFreshData(10, 32)
+	smt.Update(keys, newValues)
+	for i, key := range keys {
+		value, _ := smt.GetWithRoot(key, root)
+		if !bytes.Equal(values[i], value) {
+			t.Fatal("failed to get value of past root")
+		}
+		value, _ = smt.Get(key)
+		if !bytes.Equal(newValues[i], value) {
+			t.Fatal("failed to get value of current root")
+		}
+	}
+}
+
 func TestTrieRevert(t *testing.T) {
 	dbPath := path.Join(".aergo", "db")
 	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
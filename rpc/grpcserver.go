@@ -16,6 +16,8 @@ import (
 	"github.com/aergoio/aergo-actor/actor"
 
 	"github.com/aergoio/aergo-lib/log"
+	"github.com/aergoio/aergo/blockchain"
+	"github.com/aergoio/aergo/contract"
 	"github.com/aergoio/aergo/message"
 	"github.com/aergoio/aergo/p2p"
 	"github.com/aergoio/aergo/pkg/component"
@@ -32,9 +34,10 @@ var (
 
 // AergoRPCService implements GRPC server which is defined in rpc.proto
 type AergoRPCService struct {
-	hub         *component.ComponentHub
-	actorHelper p2p.ActorService
-	msgHelper   message.Helper
+	hub          *component.ComponentHub
+	actorHelper  p2p.ActorService
+	msgHelper    message.Helper
+	chainService *blockchain.ChainService
 }
 
 // FIXME remove redundant constants
@@ -477,17 +480,181 @@ func (rpc *AergoRPCService) GetABI(ctx context.Context, in *types.SingleBytes) (
 	return rsp.ABI, rsp.Err
 }
 
-func (rpc *AergoRPCService) QueryContract(ctx context.Context, in *types.Query) (*types.SingleBytes, error) {
+// GetContractInfo handle rpc request getcontractinfo
+func (rpc *AergoRPCService) GetContractInfo(ctx context.Context, in *types.SingleBytes) (*types.ContractInfo, error) {
 	result, err := rpc.hub.RequestFuture(message.ChainSvc,
-		&message.GetQuery{Contract: in.ContractAddress, Queryinfo: in.Queryinfo}, defaultActorTimeout, "rpc.(*AergoRPCService).QueryContract").Result()
+		&message.GetContractInfo{Contract: in.Value}, defaultActorTimeout, "rpc.(*AergoRPCService).GetContractInfo").Result()
 	if err != nil {
 		return nil, err
 	}
-	rsp, ok := result.(message.GetQueryRsp)
+	rsp, ok := result.(message.GetContractInfoRsp)
 	if !ok {
 		return nil, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
 	}
-	return &types.SingleBytes{Value: rsp.Result}, rsp.Err
+	return rsp.Info, rsp.Err
+}
+
+func (rpc *AergoRPCService) QueryContract(ctx context.Context, in *types.Query) (*types.SingleBytes, error) {
+	// the query runs in the goroutine of the request, not in the actor of the
+	// chain service
+	ret, err := rpc.chainService.QueryContract(in.ContractAddress, in.Queryinfo)
+	switch err {
+	case contract.ErrQueryTimeout:
+		return nil, status.Error(codes.DeadlineExceeded, err.Error())
+	case contract.ErrMemoryLimit:
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return &types.SingleBytes{Value: ret}, err
+}
+
+// VerifyContractSource handle rpc request verifycontractsource, which compiles
+// the source of a contract and compares it with the deployed code
+func (rpc *AergoRPCService) VerifyContractSource(ctx context.Context, in *types.SourceVerification) (*types.ABI, error) {
+	if len(in.Source) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "source is empty")
+	}
+	return rpc.chainService.VerifyContractSource(in.ContractAddress, in.Source)
+}
+
+// GetTokenBalance handle rpc request gettokenbalance, which reads the balance
+// of an account in a token contract
+func (rpc *AergoRPCService) GetTokenBalance(ctx context.Context, in *types.TokenBalanceRequest) (*types.TokenBalance, error) {
+	if len(in.Token) == 0 || len(in.Account) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "token or account is empty")
+	}
+	return rpc.chainService.GetTokenBalance(in.Token, in.Account)
+}
+
+// SimulateTX runs a tx on the best block state and returns its receipt, fee
+// and balance changes. Nothing is committed. Like a query, it runs in the
+// goroutine of the request, not in the actor of the chain service.
+func (rpc *AergoRPCService) SimulateTX(ctx context.Context, in *types.Tx) (*types.SimulateResult, error) {
+	result, err := rpc.chainService.SimulateTx(in)
+	if err == contract.ErrQueryTimeout {
+		return nil, status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return result, err
+}
+
+// TraceTX executes a tx again and returns its receipt with the steps of its
+// contract executions. It runs in the goroutine of the request, not in the
+// actor of the chain service.
+func (rpc *AergoRPCService) TraceTX(ctx context.Context, in *types.SingleBytes) (*types.Receipt, error) {
+	receipt, err := rpc.chainService.TraceTx(in.Value)
+	if err == contract.ErrQueryTimeout {
+		return nil, status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return receipt, err
+}
+
+// GetContractAddress handle rpc request getcontractaddress, which computes the
+// address of a contract before it is deployed
+func (rpc *AergoRPCService) GetContractAddress(ctx context.Context, in *types.ContractAddressRequest) (*types.SingleBytes, error) {
+	if len(in.Account) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "account is empty")
+	}
+	if len(in.Salt) > 0 {
+		if len(in.CodeHash) != types.CodeHashLength {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid code hash")
+		}
+		return &types.SingleBytes{Value: types.SaltedContractAddress(in.Account, in.Salt, in.CodeHash)}, nil
+	}
+	nonce := in.Nonce
+	if nonce == 0 {
+		state, err := rpc.GetState(ctx, &types.SingleBytes{Value: in.Account})
+		if err != nil {
+			return nil, err
+		}
+		nonce = state.GetNonce() + 1
+	}
+	return &types.SingleBytes{Value: types.ContractAddress(in.Account, nonce)}, nil
+}
+
+// ListEvents handle rpc request listevents. The blocks are searched in the
+// goroutine of the request, not in the actor of the chain service.
+func (rpc *AergoRPCService) ListEvents(ctx context.Context, in *types.FilterInfo) (*types.EventList, error) {
+	events, err := rpc.chainService.ListEvents(in)
+	if err != nil {
+		return nil, err
+	}
+	return &types.EventList{Events: events}, nil
+}
+
+// eventPollInterval is the interval to check new blocks for event streams
+const eventPollInterval = time.Second
+
+// maxEventStreamBlocks bounds the blocks searched at once by an event stream
+const maxEventStreamBlocks = 1000
+
+// ListEventStream sends the events matching the filter in the blocks added
+// after the request. If blockfrom is given, it starts from that block instead.
+// The stream ends after blockto, if given, or when the client cancels it.
+func (rpc *AergoRPCService) ListEventStream(in *types.FilterInfo, stream types.AergoRPCService_ListEventStreamServer) error {
+	next := in.Blockfrom
+	if next == 0 {
+		best, err := rpc.bestBlockNo()
+		if err != nil {
+			return err
+		}
+		next = best + 1
+	}
+
+	ticker := time.NewTicker(eventPollInterval)
+	defer ticker.Stop()
+	for {
+		if in.Blockto != 0 && next > in.Blockto {
+			return nil
+		}
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-ticker.C:
+		}
+
+		best, err := rpc.bestBlockNo()
+		if err != nil {
+			return err
+		}
+		if best < next {
+			continue
+		}
+		to := best
+		if to-next >= maxEventStreamBlocks {
+			to = next + maxEventStreamBlocks - 1
+		}
+		if in.Blockto != 0 && to > in.Blockto {
+			to = in.Blockto
+		}
+		filter := &types.FilterInfo{
+			ContractAddress: in.ContractAddress,
+			EventName:       in.EventName,
+			Blockfrom:       next,
+			Blockto:         to,
+		}
+		events, err := rpc.ListEvents(stream.Context(), filter)
+		if err != nil {
+			return err
+		}
+		for _, ev := range events.Events {
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		}
+		next = to + 1
+	}
+}
+
+func (rpc *AergoRPCService) bestBlockNo() (types.BlockNo, error) {
+	result, err := rpc.hub.RequestFuture(message.ChainSvc, &message.GetBestBlockNo{}, defaultActorTimeout,
+		"rpc.(*AergoRPCService).bestBlockNo").Result()
+	if err != nil {
+		return 0, err
+	}
+	rsp, ok := result.(message.GetBestBlockNoRsp)
+	if !ok {
+		return 0, status.Errorf(codes.Internal, "internal type (%v) error", reflect.TypeOf(result))
+	}
+	return rsp.BlockNo, nil
 }
 
 func toTimestamp(time time.Time) *timestamp.Timestamp {
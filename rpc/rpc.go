@@ -15,6 +15,7 @@ import (
 	"github.com/aergoio/aergo/types"
 
 	"github.com/aergoio/aergo-actor/actor"
+	"github.com/aergoio/aergo/blockchain"
 	"github.com/aergoio/aergo/config"
 	"github.com/aergoio/aergo/message"
 	"github.com/aergoio/aergo/pkg/component"
@@ -42,10 +43,11 @@ type RPC struct {
 //var _ component.IComponent = (*RPCComponent)(nil)
 
 // NewRPC create an rpc service
-func NewRPC(hub *component.ComponentHub, cfg *config.Config) *RPC {
+func NewRPC(hub *component.ComponentHub, cfg *config.Config, chainService *blockchain.ChainService) *RPC {
 	actualServer := &AergoRPCService{
-		hub:       hub,
-		msgHelper: message.GetHelper(),
+		hub:          hub,
+		msgHelper:    message.GetHelper(),
+		chainService: chainService,
 	}
 	opts := []grpc.ServerOption{
 		grpc.MaxRecvMsgSize(1024 * 1024 * 256),
@@ -42,6 +42,17 @@ type ContractState struct {
 	code    []byte
 	storage *trie.Trie
 	dbstore *db.DB
+	dryRun  bool
+}
+
+// SetDryRun keeps the code and the storage of st only in memory.
+func (st *ContractState) SetDryRun() {
+	st.dryRun = true
+}
+
+// IsDryRun returns true if the changes of st are never written.
+func (st *ContractState) IsDryRun() bool {
+	return st.dryRun
 }
 
 func (st *ContractState) SetNonce(nonce uint64) {
@@ -60,11 +71,14 @@ func (st *ContractState) GetBalance() uint64 {
 
 func (st *ContractState) SetCode(code []byte) error {
 	codeHash := sha256.Sum256(code)
-	err := saveData(st.dbstore, codeHash[:], &code)
-	if err != nil {
-		return err
+	if !st.dryRun {
+		err := saveData(st.dbstore, codeHash[:], &code)
+		if err != nil {
+			return err
+		}
 	}
 	st.State.CodeHash = codeHash[:]
+	st.code = code
 	return nil
 }
 func (st *ContractState) GetCode() ([]byte, error) {
@@ -94,6 +108,22 @@ func (st *ContractState) SetData(key, value []byte) error {
 	return nil
 }
 
+// DeleteData removes the data of key from the storage. The trie cannot delete
+// a key which is not in it, so a key without data is left as it is.
+func (st *ContractState) DeleteData(key []byte) error {
+	value, err := st.GetData(key)
+	if err != nil || value == nil {
+		return err
+	}
+	hkey := types.TrieHasher(key)
+	_, err = st.storage.Update(trie.DataArray{hkey[:]}, trie.DataArray{trie.DefaultLeaf})
+	if err != nil {
+		return err
+	}
+	st.State.StorageRoot = st.storage.Root
+	return nil
+}
+
 func (st *ContractState) GetData(key []byte) ([]byte, error) {
 	hkey := types.TrieHasher(key)
 	value, err := st.storage.Get(hkey[:])
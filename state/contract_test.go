@@ -67,6 +67,32 @@ func TestContractStateData(t *testing.T) {
 	}
 }
 
+func TestContractStateDeleteData(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+	contractState, err := chainStateDB.OpenContractStateAccount(types.ToAccountID([]byte("test_address")))
+	if err != nil {
+		t.Fatalf("counld not open contract state : %s", err.Error())
+	}
+	empty := contractState.State.StorageRoot
+	if err := contractState.SetData([]byte("test_key"), []byte("test_bytes")); err != nil {
+		t.Fatalf("counld set data to contract state : %s", err.Error())
+	}
+	if err := contractState.DeleteData([]byte("test_key")); err != nil {
+		t.Fatalf("counld delete data of contract state : %s", err.Error())
+	}
+	if res, _ := contractState.GetData([]byte("test_key")); res != nil {
+		t.Errorf("deleted data is left : %s", string(res))
+	}
+	if !bytes.Equal(contractState.State.StorageRoot, empty) {
+		t.Errorf("storage root is not the empty one after the delete")
+	}
+	// a key without data is not deleted again
+	if err := contractState.DeleteData([]byte("test_key")); err != nil {
+		t.Errorf("counld delete data of contract state : %s", err.Error())
+	}
+}
+
 func TestContractStateEmpty(t *testing.T) {
 	initTest(t)
 	defer deinitTest()
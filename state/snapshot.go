@@ -0,0 +1,82 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/aergoio/aergo/types"
+	"github.com/golang/protobuf/proto"
+)
+
+// Snapshot is a read-only view of the account states at the state root of a
+// block. The blocks applied after it is taken do not change it, so it can be
+// read by many goroutines while the chain goes on.
+type Snapshot struct {
+	sdb   *ChainStateDB
+	root  []byte
+	block types.BlockInfo
+}
+
+// GetSnapshot returns a snapshot of the latest state.
+func (sdb *ChainStateDB) GetSnapshot() *Snapshot {
+	sdb.RLock()
+	defer sdb.RUnlock()
+
+	return &Snapshot{
+		sdb:   sdb,
+		root:  append([]byte{}, sdb.trie.Root...),
+		block: *sdb.latest,
+	}
+}
+
+// BlockInfo returns the block whose state is in ss.
+func (ss *Snapshot) BlockInfo() types.BlockInfo {
+	return ss.block
+}
+
+// GetAccountStateClone returns the state of an account in ss, which is a new
+// state at every call.
+func (ss *Snapshot) GetAccountStateClone(aid types.AccountID) (*types.State, error) {
+	if aid == emptyAccountID {
+		return nil, fmt.Errorf("Failed to get snapshot account: invalid account id")
+	}
+	data, err := ss.sdb.trie.GetWithRoot(aid[:], ss.root)
+	if err != nil {
+		return nil, err
+	}
+	state := types.NewState()
+	if data != nil {
+		if err := proto.Unmarshal(data, state); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+// NewBlockState returns a block state for executing a block on ss. The
+// accounts it does not have are read at the state root of ss, whatever blocks
+// are applied meanwhile.
+func (ss *Snapshot) NewBlockState(blockInfo *types.BlockInfo) *types.BlockState {
+	bs := types.NewBlockState(blockInfo)
+	bs.BaseRoot = append([]byte{}, ss.root...)
+	return bs
+}
+
+// OpenContractStateAccount returns the contract state of an account in ss.
+func (ss *Snapshot) OpenContractStateAccount(aid types.AccountID) (*ContractState, error) {
+	st, err := ss.GetAccountStateClone(aid)
+	if err != nil {
+		return nil, err
+	}
+	return ss.OpenContractState(st)
+}
+
+// OpenContractState returns the contract state of st, whose storage is read
+// at the storage root of st.
+func (ss *Snapshot) OpenContractState(st *types.State) (*ContractState, error) {
+	return ss.sdb.OpenContractState(st)
+}
@@ -0,0 +1,80 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/aergoio/aergo/types"
+)
+
+func TestSnapshot(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+	aid := types.ToAccountID([]byte("test_address"))
+
+	putBalance := func(blockNo types.BlockNo, balance uint64) {
+		prev := chainStateDB.latest.BlockHash
+		bs := types.NewBlockState(types.NewBlockInfo(blockNo, types.ToBlockID([]byte{byte(blockNo)}), prev))
+		before, _ := chainStateDB.GetAccountStateClone(aid)
+		after := types.Clone(*before).(types.State)
+		after.Balance = balance
+		bs.PutAccount(aid, before, &after)
+		if err := chainStateDB.Apply(bs); err != nil {
+			t.Fatalf("failed to apply : %s", err.Error())
+		}
+	}
+	putBalance(1, 100)
+	snapshot := chainStateDB.GetSnapshot()
+	putBalance(2, 200)
+
+	st, err := snapshot.GetAccountStateClone(aid)
+	if err != nil {
+		t.Fatalf("could not get snapshot account : %s", err.Error())
+	}
+	if st.GetBalance() != 100 || snapshot.BlockInfo().BlockNo != 1 {
+		t.Errorf("snapshot changed : balance=%d, block=%d", st.GetBalance(), snapshot.BlockInfo().BlockNo)
+	}
+	st, _ = chainStateDB.GetSnapshot().GetAccountStateClone(aid)
+	if st.GetBalance() != 200 {
+		t.Errorf("latest snapshot has balance %d", st.GetBalance())
+	}
+	st, _ = snapshot.GetAccountStateClone(types.ToAccountID([]byte("none")))
+	if st.GetBalance() != 0 {
+		t.Errorf("unknown account has balance %d", st.GetBalance())
+	}
+}
+
+func TestRewindBlockState(t *testing.T) {
+	initTest(t)
+	defer deinitTest()
+	aid := types.ToAccountID([]byte("test_address"))
+
+	putBalance := func(blockNo types.BlockNo, balance uint64) {
+		prev := chainStateDB.latest.BlockHash
+		bs := types.NewBlockState(types.NewBlockInfo(blockNo, types.ToBlockID([]byte{byte(blockNo)}), prev))
+		before, _ := chainStateDB.GetAccountStateClone(aid)
+		after := types.Clone(*before).(types.State)
+		after.Balance = balance
+		bs.PutAccount(aid, before, &after)
+		if err := chainStateDB.Apply(bs); err != nil {
+			t.Fatalf("failed to apply : %s", err.Error())
+		}
+	}
+	putBalance(1, 100)
+	putBalance(2, 200)
+	putBalance(3, 300)
+
+	bs, err := chainStateDB.RewindBlockState(types.ToBlockID([]byte{2}))
+	if err != nil {
+		t.Fatalf("failed to rewind : %s", err.Error())
+	}
+	st, err := chainStateDB.GetBlockAccountClone(bs, aid)
+	if err != nil {
+		t.Fatalf("could not get rewound account : %s", err.Error())
+	}
+	if st.GetBalance() != 100 || bs.BlockNo != 2 {
+		t.Errorf("rewound state : balance=%d, block=%d", st.GetBalance(), bs.BlockNo)
+	}
+	if _, err := chainStateDB.RewindBlockState(types.ToBlockID([]byte{9})); err == nil {
+		t.Errorf("rewound a block not applied")
+	}
+}
@@ -76,6 +76,15 @@ func (sdb *ChainStateDB) Init(dataDir string) error {
 	return err
 }
 
+// InitMemory initializes sdb with a db in memory instead of a data
+// directory. The states are lost with sdb, so it is used by the tests which
+// run contracts without a node.
+func (sdb *ChainStateDB) InitMemory() error {
+	dbInst := db.NewDB(db.MemoryImpl, "")
+	sdb.statedb = &dbInst
+	return sdb.Init("")
+}
+
 func (sdb *ChainStateDB) Close() error {
 	sdb.Lock()
 	defer sdb.Unlock()
@@ -140,6 +149,10 @@ func (sdb *ChainStateDB) getBlockAccount(bs *types.BlockState, aid types.Account
 	if prev, ok := bs.GetAccount(aid); ok {
 		return prev, nil
 	}
+	if bs.BaseRoot != nil {
+		base := &Snapshot{sdb: sdb, root: bs.BaseRoot, block: bs.BlockInfo}
+		return base.GetAccountStateClone(aid)
+	}
 	return sdb.getAccountState(aid)
 }
 func (sdb *ChainStateDB) GetBlockAccountClone(bs *types.BlockState, aid types.AccountID) (*types.State, error) {
@@ -275,6 +288,30 @@ func (sdb *ChainStateDB) Rollback(blockNo types.BlockNo) error {
 	return err
 }
 
+// RewindBlockState returns a block state for executing the block of
+// blockHash again. The accounts it does not have are read at the state root
+// before the block, which is saved with the block state when the block is
+// applied, so it takes the same time for any applied block.
+func (sdb *ChainStateDB) RewindBlockState(blockHash types.BlockID) (*types.BlockState, error) {
+	sdb.RLock()
+	defer sdb.RUnlock()
+
+	bs, err := sdb.loadBlockState(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if bs.BlockHash != blockHash {
+		return nil, fmt.Errorf("Failed to rewind: block %v is not applied", blockHash)
+	}
+	rewound := types.NewBlockState(&bs.BlockInfo)
+	// a block on an empty state has an empty root, not a nil one
+	rewound.BaseRoot = []byte{}
+	if bs.Undo.StateRoot != emptyHashID {
+		rewound.BaseRoot = bs.Undo.StateRoot[:]
+	}
+	return rewound, nil
+}
+
 func (sdb *ChainStateDB) GetHash() []byte {
 	return sdb.trie.Root
 }
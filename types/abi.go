@@ -0,0 +1,158 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/mr-tron/base58/base58"
+)
+
+// TypedABIVersion is the version of the ABI which has the types of the
+// arguments and the return values, and the view and payable flags of the
+// functions. The calls to a contract with an older ABI are not validated.
+const TypedABIVersion = "0.2"
+
+// the types of the arguments and the return values in a typed ABI
+const (
+	ABITypeString  = "string"
+	ABITypeAddress = "address"
+	ABITypeInteger = "integer"
+	ABITypeNumber  = "number"
+	ABITypeBool    = "bool"
+	ABITypeBignum  = "bignum"
+	ABITypeAny     = "any"
+)
+
+// IsABIType returns true if typ is one of the types of a typed ABI.
+func IsABIType(typ string) bool {
+	switch typ {
+	case ABITypeString, ABITypeAddress, ABITypeInteger, ABITypeNumber, ABITypeBool, ABITypeBignum, ABITypeAny:
+		return true
+	}
+	return false
+}
+
+// the types of the state variables declared with state.var
+const (
+	StateVarValue = "value"
+	StateVarMap   = "map"
+	StateVarArray = "array"
+)
+
+// IsStateVarType returns true if typ is one of the types of the state
+// variables.
+func IsStateVarType(typ string) bool {
+	switch typ {
+	case StateVarValue, StateVarMap, StateVarArray:
+		return true
+	}
+	return false
+}
+
+// stateVarKeyPrefix is the prefix of the storage keys of the state variables
+const stateVarKeyPrefix = "_sv_"
+
+// StateVarKey returns the storage key of a state variable of a contract, which
+// is given to ContractState.GetData. sub is the key of a map entry or the
+// index of an array element, and empty for a state value. The item is a JSON
+// value.
+func StateVarKey(contractAddress []byte, name, sub string) []byte {
+	key := base58.Encode(contractAddress) + "_" + stateVarKeyPrefix + name
+	if sub != "" {
+		key += "-" + sub
+	}
+	return []byte(key)
+}
+
+// StateVarLengthKey returns the storage key of the length of an array state
+// variable of a contract.
+func StateVarLengthKey(contractAddress []byte, name string) []byte {
+	return []byte(base58.Encode(contractAddress) + "_" + stateVarKeyPrefix + name + "#")
+}
+
+// IsTyped returns true if the calls to the functions of abi are validated.
+func (abi *ABI) IsTyped() bool {
+	return abi.GetVersion() == TypedABIVersion
+}
+
+// GetFunction returns the function of the name, or nil if abi does not have
+// it.
+func (abi *ABI) GetFunction(name string) *Function {
+	for _, fn := range abi.GetFunctions() {
+		if fn.GetName() == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+// CheckArgs validates the arguments of a call against the types of fn.
+func (fn *Function) CheckArgs(args []interface{}) error {
+	if len(args) != len(fn.Arguments) {
+		return fmt.Errorf("function %s needs %d arguments, but %d given", fn.Name, len(fn.Arguments), len(args))
+	}
+	for i, arg := range fn.Arguments {
+		if err := checkABIValue(arg.Type, args[i]); err != nil {
+			return fmt.Errorf("argument %s of %s: %s", arg.Name, fn.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// CheckReturns validates the JSON encoded return values of fn against its
+// return types.
+func (fn *Function) CheckReturns(jsonRet string) error {
+	if len(fn.Returns) == 0 {
+		return nil
+	}
+	var rets []interface{}
+	if err := json.Unmarshal([]byte(jsonRet), &rets); err != nil {
+		return err
+	}
+	if len(rets) != len(fn.Returns) {
+		return fmt.Errorf("function %s returns %d values, but %d returned", fn.Name, len(fn.Returns), len(rets))
+	}
+	for i, typ := range fn.Returns {
+		if err := checkABIValue(typ, rets[i]); err != nil {
+			return fmt.Errorf("return value %d of %s: %s", i, fn.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+func checkABIValue(typ string, v interface{}) error {
+	ok := false
+	switch typ {
+	case "", ABITypeAny:
+		ok = true
+	case ABITypeString:
+		_, ok = v.(string)
+	case ABITypeAddress:
+		if s, isString := v.(string); isString {
+			address, err := base58.Decode(s)
+			ok = err == nil && len(address) > 0
+		}
+	case ABITypeInteger:
+		if n, isNumber := v.(float64); isNumber {
+			ok = n == math.Trunc(n)
+		}
+	case ABITypeNumber:
+		_, ok = v.(float64)
+	case ABITypeBool:
+		_, ok = v.(bool)
+	case ABITypeBignum:
+		_, ok = ParseBignumJSON(v)
+	default:
+		return fmt.Errorf("unknown type %s", typ)
+	}
+	if !ok {
+		return fmt.Errorf("%v is not %s", v, typ)
+	}
+	return nil
+}
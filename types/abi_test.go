@@ -0,0 +1,50 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/mr-tron/base58/base58"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestABICheckArgs(t *testing.T) {
+	abi := &ABI{
+		Version: TypedABIVersion,
+		Functions: []*Function{
+			{
+				Name: "transfer",
+				Arguments: []*FnArgument{
+					{Name: "to", Type: ABITypeAddress},
+					{Name: "amount", Type: ABITypeInteger},
+				},
+				Returns: []string{ABITypeBool},
+			},
+		},
+	}
+	assert.True(t, abi.IsTyped())
+	assert.Nil(t, abi.GetFunction("approve"))
+
+	fn := abi.GetFunction("transfer")
+	assert.NotNil(t, fn)
+	assert.NoError(t, fn.CheckArgs([]interface{}{"31KcyXb99xYD5tQ9Jpx4BMnhVh9a", float64(10)}))
+	assert.Error(t, fn.CheckArgs([]interface{}{"31KcyXb99xYD5tQ9Jpx4BMnhVh9a"}))
+	assert.Error(t, fn.CheckArgs([]interface{}{"31KcyXb99xYD5tQ9Jpx4BMnhVh9a", 1.5}))
+	assert.Error(t, fn.CheckArgs([]interface{}{"", float64(10)}))
+
+	assert.NoError(t, fn.CheckReturns(`[true]`))
+	assert.Error(t, fn.CheckReturns(`["true"]`))
+	assert.Error(t, fn.CheckReturns(`[]`))
+
+	assert.False(t, (&ABI{Version: "0.1"}).IsTyped())
+}
+
+func TestStateVarKey(t *testing.T) {
+	address := []byte{1, 2, 3}
+	prefix := base58.Encode(address) + "__sv_"
+	assert.Equal(t, prefix+"owner", string(StateVarKey(address, "owner", "")))
+	assert.Equal(t, prefix+"balances-alice", string(StateVarKey(address, "balances", "alice")))
+	assert.Equal(t, prefix+"holders-1", string(StateVarKey(address, "holders", "1")))
+	assert.Equal(t, prefix+"holders#", string(StateVarLengthKey(address, "holders")))
+	assert.True(t, IsStateVarType(StateVarMap))
+	assert.False(t, IsStateVarType("table"))
+}
@@ -0,0 +1,58 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package types
+
+import (
+	"strconv"
+
+	sha256 "github.com/minio/sha256-simd"
+)
+
+// ContractAddressLength is the length of a contract address, which is as long
+// as the address of an account made from a public key.
+const ContractAddressLength = 20
+
+// CodeHashLength is the length of the hash of the code of a contract.
+const CodeHashLength = sha256.Size
+
+// saltedAddressPrefix separates the salted addresses from the ones made from
+// a nonce, whose account starts with the prefix of a compressed public key
+const saltedAddressPrefix = 0xff
+
+// ContractAddress returns the address of the contract deployed by a tx of
+// account with nonce, which is the first 20 bytes of
+// sha256(account || decimal nonce). It can be computed before the deploy tx is
+// sent, from the next nonce of the account.
+func ContractAddress(account []byte, nonce uint64) []byte {
+	h := sha256.New()
+	h.Write(account)
+	h.Write([]byte(strconv.FormatUint(nonce, 10)))
+	return h.Sum(nil)[:ContractAddressLength]
+}
+
+// SaltedContractAddress returns the address of a contract deployed by
+// deployer with salt, which does not depend on the nonce of deployer, so that
+// a factory can predict the address of the contracts it deploys with
+// contract.deploy. The address
+// is the first 20 bytes of
+// sha256(0xff || deployer || sha256(salt) || codeHash), where codeHash is the
+// CodeHash of the code of the contract.
+func SaltedContractAddress(deployer, salt, codeHash []byte) []byte {
+	saltHash := sha256.Sum256(salt)
+	h := sha256.New()
+	h.Write([]byte{saltedAddressPrefix})
+	h.Write(deployer)
+	h.Write(saltHash[:])
+	h.Write(codeHash)
+	return h.Sum(nil)[:ContractAddressLength]
+}
+
+// CodeHash returns the hash of the code of a contract, which is the code hash
+// of its state.
+func CodeHash(code []byte) []byte {
+	h := sha256.Sum256(code)
+	return h[:]
+}
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	sha256 "github.com/minio/sha256-simd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractAddress(t *testing.T) {
+	account := bytes.Repeat([]byte{0x02}, 33)
+	h := sha256.Sum256(append(account, "12"...))
+	address := ContractAddress(account, 12)
+	assert.Equal(t, h[:ContractAddressLength], address)
+	assert.NotEqual(t, address, ContractAddress(account, 13))
+}
+
+func TestSaltedContractAddress(t *testing.T) {
+	deployer := bytes.Repeat([]byte{0x02}, 33)
+	codeHash := CodeHash([]byte("code"))
+	assert.Equal(t, "5694d08a2e53ffcae0c3103e5ad6f6076abd960eb1f8a56577040bc1028f702b", hex.EncodeToString(codeHash))
+
+	address := SaltedContractAddress(deployer, []byte("salt"), codeHash)
+	assert.Len(t, address, ContractAddressLength)
+	assert.Equal(t, address, SaltedContractAddress(deployer, []byte("salt"), codeHash))
+	assert.NotEqual(t, address, SaltedContractAddress(deployer, []byte("salt2"), codeHash))
+	assert.NotEqual(t, address, SaltedContractAddress(deployer, []byte("salt"), CodeHash([]byte("code2"))))
+	assert.NotEqual(t, address, SaltedContractAddress(deployer[1:], []byte("salt"), codeHash))
+}
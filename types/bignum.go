@@ -0,0 +1,72 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package types
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// BignumKey is the key of the JSON object encoding a big integer. The numbers
+// of JSON are float64 in the contract vm, so the arguments of contract calls,
+// the return values in receipts and the events have a big integer as the
+// object {"_bignum":"<decimal>"}, which is a bignum in a contract.
+const BignumKey = "_bignum"
+
+// BignumMaxBits is the maximum size of a bignum, which is enough for a
+// 256-bit amount with 18 decimals multiplied by another one.
+const BignumMaxBits = 1024
+
+var (
+	errBignumSyntax   = errors.New("invalid bignum")
+	errBignumOverflow = errors.New("bignum overflow")
+)
+
+// ParseBignum parses the decimal string of a bignum, which may have a sign.
+func ParseBignum(s string) (*big.Int, error) {
+	digits := strings.TrimPrefix(s, "-")
+	if len(digits) == 0 || strings.TrimLeft(digits, "0123456789") != "" {
+		return nil, errBignumSyntax
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, errBignumSyntax
+	}
+	if err := CheckBignum(n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// CheckBignum returns an error if n is larger than BignumMaxBits.
+func CheckBignum(n *big.Int) error {
+	if n.BitLen() > BignumMaxBits {
+		return errBignumOverflow
+	}
+	return nil
+}
+
+// NewBignumJSON returns the JSON value of n, which is given to json.Marshal
+// for the arguments of a contract call.
+func NewBignumJSON(n *big.Int) map[string]string {
+	return map[string]string{BignumKey: n.String()}
+}
+
+// ParseBignumJSON returns the big integer of a value decoded by json.Unmarshal
+// if it is the JSON object of a bignum.
+func ParseBignumJSON(v interface{}) (*big.Int, bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return nil, false
+	}
+	s, ok := m[BignumKey].(string)
+	if !ok {
+		return nil, false
+	}
+	n, err := ParseBignum(s)
+	return n, err == nil
+}
@@ -0,0 +1,50 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBignum(t *testing.T) {
+	n, err := ParseBignum("-1000000000000000000000000")
+	assert.NoError(t, err)
+	assert.Equal(t, "-1000000000000000000000000", n.String())
+
+	for _, s := range []string{"", "-", "+1", "1e18", "0x10", "1.5", " 1"} {
+		_, err = ParseBignum(s)
+		assert.Error(t, err, s)
+	}
+	_, err = ParseBignum(strings.Repeat("9", 400))
+	assert.Error(t, err)
+}
+
+func TestBignumJSON(t *testing.T) {
+	amount, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	b, err := json.Marshal([]interface{}{"to", NewBignumJSON(amount)})
+	assert.NoError(t, err)
+	assert.Equal(t, `["to",{"_bignum":"123456789012345678901234567890"}]`, string(b))
+
+	var args []interface{}
+	assert.NoError(t, json.Unmarshal(b, &args))
+	n, ok := ParseBignumJSON(args[1])
+	assert.True(t, ok)
+	assert.Equal(t, 0, amount.Cmp(n))
+	_, ok = ParseBignumJSON(args[0])
+	assert.False(t, ok)
+	_, ok = ParseBignumJSON(map[string]interface{}{BignumKey: "1", "x": "2"})
+	assert.False(t, ok)
+
+	fn := &Function{
+		Name:      "transfer",
+		Arguments: []*FnArgument{{Name: "to", Type: ABITypeString}, {Name: "amount", Type: ABITypeBignum}},
+		Returns:   []string{ABITypeBignum},
+	}
+	assert.NoError(t, fn.CheckArgs(args))
+	assert.Error(t, fn.CheckArgs([]interface{}{"to", float64(10)}))
+	assert.NoError(t, fn.CheckReturns(`[{"_bignum":"-5"}]`))
+	assert.Error(t, fn.CheckReturns(`[{"_bignum":"5.5"}]`))
+}
@@ -8,9 +8,12 @@ package types
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"io"
+	"math"
 	"reflect"
 
+	"github.com/golang/protobuf/proto"
 	sha256 "github.com/minio/sha256-simd"
 
 	"github.com/aergoio/aergo/internal/enc"
@@ -21,10 +24,25 @@ import (
 const (
 	// DefaultMaxBlockSize is the maximum block size (currently 1MiB)
 	DefaultMaxBlockSize = 1 << 20
+	// MaxContractMemory is the maximum memory of a contract execution with
+	// its nested calls (currently 64MiB). It is a rule of the chain, because
+	// the receipts depend on it.
+	MaxContractMemory = 64 << 20
+	// DefaultQueryTimeout is the timeout of a contract query in milliseconds
+	DefaultQueryTimeout = 3000
 
 	lastFieldOfBH = "Sign"
 )
 
+var (
+	// ErrFeeOverflow is returned when the fee of a transaction does not fit
+	// in an amount.
+	ErrFeeOverflow = errors.New("fee overflows")
+	// ErrNoGasLimit is returned when a transaction running contracts has no
+	// gas limit.
+	ErrNoGasLimit = errors.New("contract tx has no gas limit")
+)
+
 var lastIndexOfBH int
 
 func init() {
@@ -149,6 +167,31 @@ func writeBlockHeaderOld(w io.Writer, bh *BlockHeader) error {
 	return nil
 }
 
+// SetCoinbaseAccount sets the account credited with the fees of the txs of
+// the block. It must be set before the block is signed.
+func (block *Block) SetCoinbaseAccount(account []byte) {
+	block.Header.CoinbaseAccount = account
+}
+
+// SetEventBloom puts the bloom of the events emitted by the txs of the block
+// into its header. The block producer does not run the txs, so the bloom is
+// set by executing the block, and the block hash does not cover it. The bloom
+// of a received block cannot be trusted, so it is always overwritten.
+func (block *Block) SetEventBloom(bloom *Bloom) {
+	block.Header.EventsBloom = append([]byte(nil), bloom[:]...)
+}
+
+// ClearEventBloom removes the event bloom of a block which is not executed.
+func (block *Block) ClearEventBloom() {
+	block.Header.EventsBloom = nil
+}
+
+// EventBloom returns the bloom of the events of the block. It is nil if the
+// block is not executed.
+func (block *Block) EventBloom() *Bloom {
+	return BytesToBloom(block.GetHeader().GetEventsBloom())
+}
+
 // BlockHash returns block hash. It returns a calculated value if the hash is nil.
 func (block *Block) BlockHash() []byte {
 	hash := block.GetHash()
@@ -314,20 +357,51 @@ func NewTx() *Tx {
 }
 
 func (tx *Tx) CalculateTxHash() []byte {
-	txBody := tx.Body
 	digest := sha256.New()
-	binary.Write(digest, binary.LittleEndian, txBody.Nonce)
-	digest.Write(txBody.Account)
-	digest.Write(txBody.Recipient)
-	binary.Write(digest, binary.LittleEndian, txBody.Amount)
-	digest.Write(txBody.Payload)
-	binary.Write(digest, binary.LittleEndian, txBody.Limit)
-	binary.Write(digest, binary.LittleEndian, txBody.Price)
-	binary.Write(digest, binary.LittleEndian, txBody.Type)
-	digest.Write(txBody.Sign)
+	WriteTxBody(digest, tx.Body, true)
 	return digest.Sum(nil)
 }
 
+// WriteTxBody writes the fields of txBody to w to be hashed. The fields of
+// variable length are written with their lengths and every field is always
+// written, so that two different txs are never written the same. The signs
+// of the sender, the fee payer and the members of a multi-signature account
+// are written only with withSign.
+func WriteTxBody(w io.Writer, txBody *TxBody, withSign bool) {
+	writeBytes := func(b []byte) {
+		binary.Write(w, binary.LittleEndian, uint32(len(b)))
+		w.Write(b)
+	}
+	binary.Write(w, binary.LittleEndian, txBody.Nonce)
+	writeBytes(txBody.Account)
+	writeBytes(txBody.Recipient)
+	binary.Write(w, binary.LittleEndian, txBody.Amount)
+	writeBytes(txBody.Payload)
+	binary.Write(w, binary.LittleEndian, txBody.Limit)
+	binary.Write(w, binary.LittleEndian, txBody.Price)
+	binary.Write(w, binary.LittleEndian, txBody.Type)
+	writeBytes(txBody.FeePayer)
+	binary.Write(w, binary.LittleEndian, txBody.ValidAfter)
+	binary.Write(w, binary.LittleEndian, txBody.ValidUntil)
+	ms := txBody.MultiSig
+	if ms == nil {
+		ms = &MultiSig{}
+	}
+	binary.Write(w, binary.LittleEndian, ms.Threshold)
+	binary.Write(w, binary.LittleEndian, uint32(len(ms.PubKeys)))
+	for _, pubKey := range ms.PubKeys {
+		writeBytes(pubKey)
+	}
+	if withSign {
+		writeBytes(txBody.Sign)
+		writeBytes(txBody.FeePayerSign)
+		binary.Write(w, binary.LittleEndian, uint32(len(ms.Signs)))
+		for _, sign := range ms.Signs {
+			writeBytes(sign)
+		}
+	}
+}
+
 func (tx *Tx) Clone() *Tx {
 	if tx == nil {
 		return nil
@@ -336,15 +410,20 @@ func (tx *Tx) Clone() *Tx {
 		return &Tx{}
 	}
 	body := &TxBody{
-		Nonce:     tx.Body.Nonce,
-		Account:   Clone(tx.Body.Account).([]byte),
-		Recipient: Clone(tx.Body.Recipient).([]byte),
-		Amount:    tx.Body.Amount,
-		Payload:   Clone(tx.Body.Payload).([]byte),
-		Limit:     tx.Body.Limit,
-		Price:     tx.Body.Price,
-		Sign:      Clone(tx.Body.Sign).([]byte),
-		Type:      tx.Body.Type,
+		Nonce:        tx.Body.Nonce,
+		Account:      Clone(tx.Body.Account).([]byte),
+		Recipient:    Clone(tx.Body.Recipient).([]byte),
+		Amount:       tx.Body.Amount,
+		Payload:      Clone(tx.Body.Payload).([]byte),
+		Limit:        tx.Body.Limit,
+		Price:        tx.Body.Price,
+		Sign:         Clone(tx.Body.Sign).([]byte),
+		Type:         tx.Body.Type,
+		MultiSig:     tx.Body.MultiSig.Clone(),
+		FeePayer:     Clone(tx.Body.FeePayer).([]byte),
+		FeePayerSign: Clone(tx.Body.FeePayerSign).([]byte),
+		ValidAfter:   tx.Body.ValidAfter,
+		ValidUntil:   tx.Body.ValidUntil,
 	}
 	res := &Tx{
 		Body: body,
@@ -352,3 +431,85 @@ func (tx *Tx) Clone() *Tx {
 	res.Hash = tx.CalculateTxHash()
 	return res
 }
+
+// MaxFee returns the greatest fee of the transaction, which is its gas limit
+// times its gas price. The payer must have it to pay for the transaction.
+func (tb *TxBody) MaxFee() (uint64, error) {
+	return tb.GasFee(tb.GetLimit())
+}
+
+// GasFee returns the fee of gasUsed at the gas price of the transaction. It
+// is charged to the fee payer if the transaction has one, otherwise to the
+// sender. The gas used beyond the gas limit is not charged.
+func (tb *TxBody) GasFee(gasUsed uint64) (uint64, error) {
+	if gasUsed > tb.GetLimit() {
+		gasUsed = tb.GetLimit()
+	}
+	price := tb.GetPrice()
+	if price != 0 && gasUsed > math.MaxUint64/price {
+		return 0, ErrFeeOverflow
+	}
+	return gasUsed * price, nil
+}
+
+// RunsContract reports whether the transaction runs contracts, which use the
+// gas up to its gas limit. A deployment or a call does, and so does a batch
+// with a call.
+func (tb *TxBody) RunsContract() bool {
+	switch tb.GetType() {
+	case TxType_NORMAL:
+		return tb.GetPayload() != nil
+	case TxType_BATCH:
+		var ops Batch
+		if err := proto.Unmarshal(tb.GetPayload(), &ops); err != nil {
+			return false
+		}
+		for _, op := range ops.GetOperations() {
+			if op.GetPayload() != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Payer returns the account which pays the fee of the transaction.
+func (tb *TxBody) Payer() []byte {
+	if len(tb.GetFeePayer()) > 0 {
+		return tb.GetFeePayer()
+	}
+	return tb.GetAccount()
+}
+
+// IsValidAt reports whether the transaction can be included in the block of
+// blockNo. Zero ValidAfter or ValidUntil means that the window is open on that
+// side.
+func (tb *TxBody) IsValidAt(blockNo BlockNo) bool {
+	return blockNo > tb.GetValidAfter() && !tb.IsExpiredAt(blockNo)
+}
+
+// IsExpiredAt reports whether the validity window of the transaction is
+// already over at the block of blockNo.
+func (tb *TxBody) IsExpiredAt(blockNo BlockNo) bool {
+	return tb.GetValidUntil() != 0 && blockNo > tb.GetValidUntil()
+}
+
+// Clone returns a copy of the multi-signature, including the partial signatures
+// collected so far.
+func (ms *MultiSig) Clone() *MultiSig {
+	if ms == nil {
+		return nil
+	}
+	res := &MultiSig{
+		Threshold: ms.Threshold,
+		PubKeys:   make([][]byte, len(ms.PubKeys)),
+		Signs:     make([][]byte, len(ms.Signs)),
+	}
+	for i, pubKey := range ms.PubKeys {
+		res.PubKeys[i] = Clone(pubKey).([]byte)
+	}
+	for i, sign := range ms.Signs {
+		res.Signs[i] = Clone(sign).([]byte)
+	}
+	return res
+}
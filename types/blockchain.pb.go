@@ -23,16 +23,22 @@ type TxType int32
 const (
 	TxType_NORMAL     TxType = 0
 	TxType_GOVERNANCE TxType = 1
+	TxType_BATCH      TxType = 2
+	TxType_UPGRADE    TxType = 3
 )
 
 var TxType_name = map[int32]string{
 	0: "NORMAL",
 	1: "GOVERNANCE",
+	2: "BATCH",
+	3: "UPGRADE",
 }
 
 var TxType_value = map[string]int32{
 	"NORMAL":     0,
 	"GOVERNANCE": 1,
+	"BATCH":      2,
+	"UPGRADE":    3,
 }
 
 func (x TxType) String() string {
@@ -98,14 +104,18 @@ func (m *Block) GetBody() *BlockBody {
 }
 
 type BlockHeader struct {
-	PrevBlockHash        []byte   `protobuf:"bytes,1,opt,name=prevBlockHash,proto3" json:"prevBlockHash,omitempty"`
-	BlockNo              uint64   `protobuf:"varint,2,opt,name=blockNo,proto3" json:"blockNo,omitempty"`
-	Timestamp            int64    `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	BlocksRootHash       []byte   `protobuf:"bytes,4,opt,name=blocksRootHash,proto3" json:"blocksRootHash,omitempty"`
-	TxsRootHash          []byte   `protobuf:"bytes,5,opt,name=txsRootHash,proto3" json:"txsRootHash,omitempty"`
-	Confirms             uint64   `protobuf:"varint,6,opt,name=confirms,proto3" json:"confirms,omitempty"`
-	PubKey               []byte   `protobuf:"bytes,7,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
-	Sign                 []byte   `protobuf:"bytes,8,opt,name=sign,proto3" json:"sign,omitempty"`
+	PrevBlockHash   []byte `protobuf:"bytes,1,opt,name=prevBlockHash,proto3" json:"prevBlockHash,omitempty"`
+	BlockNo         uint64 `protobuf:"varint,2,opt,name=blockNo,proto3" json:"blockNo,omitempty"`
+	Timestamp       int64  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	BlocksRootHash  []byte `protobuf:"bytes,4,opt,name=blocksRootHash,proto3" json:"blocksRootHash,omitempty"`
+	TxsRootHash     []byte `protobuf:"bytes,5,opt,name=txsRootHash,proto3" json:"txsRootHash,omitempty"`
+	Confirms        uint64 `protobuf:"varint,6,opt,name=confirms,proto3" json:"confirms,omitempty"`
+	PubKey          []byte `protobuf:"bytes,7,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	CoinbaseAccount []byte `protobuf:"bytes,9,opt,name=coinbaseAccount,proto3" json:"coinbaseAccount,omitempty"`
+	Sign            []byte `protobuf:"bytes,8,opt,name=sign,proto3" json:"sign,omitempty"`
+	// the bloom filter of the events of the txs, set by executing the
+	// block. It follows sign, so the block hash does not cover it.
+	EventsBloom          []byte   `protobuf:"bytes,10,opt,name=eventsBloom,proto3" json:"eventsBloom,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -184,6 +194,13 @@ func (m *BlockHeader) GetPubKey() []byte {
 	return nil
 }
 
+func (m *BlockHeader) GetCoinbaseAccount() []byte {
+	if m != nil {
+		return m.CoinbaseAccount
+	}
+	return nil
+}
+
 func (m *BlockHeader) GetSign() []byte {
 	if m != nil {
 		return m.Sign
@@ -191,6 +208,13 @@ func (m *BlockHeader) GetSign() []byte {
 	return nil
 }
 
+func (m *BlockHeader) GetEventsBloom() []byte {
+	if m != nil {
+		return m.EventsBloom
+	}
+	return nil
+}
+
 type BlockBody struct {
 	Txs                  []*Tx    `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -314,18 +338,23 @@ func (m *Tx) GetBody() *TxBody {
 }
 
 type TxBody struct {
-	Nonce                uint64   `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
-	Account              []byte   `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
-	Recipient            []byte   `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
-	Amount               uint64   `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
-	Payload              []byte   `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
-	Limit                uint64   `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
-	Price                uint64   `protobuf:"varint,7,opt,name=price,proto3" json:"price,omitempty"`
-	Type                 TxType   `protobuf:"varint,8,opt,name=type,proto3,enum=types.TxType" json:"type,omitempty"`
-	Sign                 []byte   `protobuf:"bytes,9,opt,name=sign,proto3" json:"sign,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Nonce                uint64    `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Account              []byte    `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	Recipient            []byte    `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Amount               uint64    `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Payload              []byte    `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
+	Limit                uint64    `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	Price                uint64    `protobuf:"varint,7,opt,name=price,proto3" json:"price,omitempty"`
+	Type                 TxType    `protobuf:"varint,8,opt,name=type,proto3,enum=types.TxType" json:"type,omitempty"`
+	Sign                 []byte    `protobuf:"bytes,9,opt,name=sign,proto3" json:"sign,omitempty"`
+	MultiSig             *MultiSig `protobuf:"bytes,10,opt,name=multiSig,proto3" json:"multiSig,omitempty"`
+	FeePayer             []byte    `protobuf:"bytes,11,opt,name=feePayer,proto3" json:"feePayer,omitempty"`
+	FeePayerSign         []byte    `protobuf:"bytes,12,opt,name=feePayerSign,proto3" json:"feePayerSign,omitempty"`
+	ValidAfter           uint64    `protobuf:"varint,13,opt,name=validAfter,proto3" json:"validAfter,omitempty"`
+	ValidUntil           uint64    `protobuf:"varint,14,opt,name=validUntil,proto3" json:"validUntil,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *TxBody) Reset()         { *m = TxBody{} }
@@ -415,6 +444,188 @@ func (m *TxBody) GetSign() []byte {
 	return nil
 }
 
+func (m *TxBody) GetMultiSig() *MultiSig {
+	if m != nil {
+		return m.MultiSig
+	}
+	return nil
+}
+
+func (m *TxBody) GetFeePayer() []byte {
+	if m != nil {
+		return m.FeePayer
+	}
+	return nil
+}
+
+func (m *TxBody) GetFeePayerSign() []byte {
+	if m != nil {
+		return m.FeePayerSign
+	}
+	return nil
+}
+
+func (m *TxBody) GetValidAfter() uint64 {
+	if m != nil {
+		return m.ValidAfter
+	}
+	return 0
+}
+
+func (m *TxBody) GetValidUntil() uint64 {
+	if m != nil {
+		return m.ValidUntil
+	}
+	return 0
+}
+
+type MultiSig struct {
+	Threshold            uint32   `protobuf:"varint,1,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	PubKeys              [][]byte `protobuf:"bytes,2,rep,name=pubKeys,proto3" json:"pubKeys,omitempty"`
+	Signs                [][]byte `protobuf:"bytes,3,rep,name=signs,proto3" json:"signs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MultiSig) Reset()         { *m = MultiSig{} }
+func (m *MultiSig) String() string { return proto.CompactTextString(m) }
+func (*MultiSig) ProtoMessage()    {}
+func (*MultiSig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9ac6287ce250c9a, []int{6}
+}
+func (m *MultiSig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MultiSig.Unmarshal(m, b)
+}
+func (m *MultiSig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MultiSig.Marshal(b, m, deterministic)
+}
+func (m *MultiSig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MultiSig.Merge(m, src)
+}
+func (m *MultiSig) XXX_Size() int {
+	return xxx_messageInfo_MultiSig.Size(m)
+}
+func (m *MultiSig) XXX_DiscardUnknown() {
+	xxx_messageInfo_MultiSig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MultiSig proto.InternalMessageInfo
+
+func (m *MultiSig) GetThreshold() uint32 {
+	if m != nil {
+		return m.Threshold
+	}
+	return 0
+}
+
+func (m *MultiSig) GetPubKeys() [][]byte {
+	if m != nil {
+		return m.PubKeys
+	}
+	return nil
+}
+
+func (m *MultiSig) GetSigns() [][]byte {
+	if m != nil {
+		return m.Signs
+	}
+	return nil
+}
+
+// payload of BATCH transaction
+type Batch struct {
+	Operations           []*BatchOperation `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Batch) Reset()         { *m = Batch{} }
+func (m *Batch) String() string { return proto.CompactTextString(m) }
+func (*Batch) ProtoMessage()    {}
+func (*Batch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9ac6287ce250c9a, []int{7}
+}
+func (m *Batch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Batch.Unmarshal(m, b)
+}
+func (m *Batch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Batch.Marshal(b, m, deterministic)
+}
+func (m *Batch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Batch.Merge(m, src)
+}
+func (m *Batch) XXX_Size() int {
+	return xxx_messageInfo_Batch.Size(m)
+}
+func (m *Batch) XXX_DiscardUnknown() {
+	xxx_messageInfo_Batch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Batch proto.InternalMessageInfo
+
+func (m *Batch) GetOperations() []*BatchOperation {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+type BatchOperation struct {
+	Recipient            []byte   `protobuf:"bytes,1,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Amount               uint64   `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Payload              []byte   `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchOperation) Reset()         { *m = BatchOperation{} }
+func (m *BatchOperation) String() string { return proto.CompactTextString(m) }
+func (*BatchOperation) ProtoMessage()    {}
+func (*BatchOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9ac6287ce250c9a, []int{8}
+}
+func (m *BatchOperation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchOperation.Unmarshal(m, b)
+}
+func (m *BatchOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchOperation.Marshal(b, m, deterministic)
+}
+func (m *BatchOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchOperation.Merge(m, src)
+}
+func (m *BatchOperation) XXX_Size() int {
+	return xxx_messageInfo_BatchOperation.Size(m)
+}
+func (m *BatchOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchOperation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchOperation proto.InternalMessageInfo
+
+func (m *BatchOperation) GetRecipient() []byte {
+	if m != nil {
+		return m.Recipient
+	}
+	return nil
+}
+
+func (m *BatchOperation) GetAmount() uint64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *BatchOperation) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
 type TxIdx struct {
 	BlockHash            []byte   `protobuf:"bytes,1,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
 	Idx                  int32    `protobuf:"varint,2,opt,name=idx,proto3" json:"idx,omitempty"`
@@ -427,7 +638,7 @@ func (m *TxIdx) Reset()         { *m = TxIdx{} }
 func (m *TxIdx) String() string { return proto.CompactTextString(m) }
 func (*TxIdx) ProtoMessage()    {}
 func (*TxIdx) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9ac6287ce250c9a, []int{6}
+	return fileDescriptor_e9ac6287ce250c9a, []int{9}
 }
 func (m *TxIdx) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxIdx.Unmarshal(m, b)
@@ -473,7 +684,7 @@ func (m *TxInBlock) Reset()         { *m = TxInBlock{} }
 func (m *TxInBlock) String() string { return proto.CompactTextString(m) }
 func (*TxInBlock) ProtoMessage()    {}
 func (*TxInBlock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9ac6287ce250c9a, []int{7}
+	return fileDescriptor_e9ac6287ce250c9a, []int{10}
 }
 func (m *TxInBlock) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxInBlock.Unmarshal(m, b)
@@ -521,7 +732,7 @@ func (m *State) Reset()         { *m = State{} }
 func (m *State) String() string { return proto.CompactTextString(m) }
 func (*State) ProtoMessage()    {}
 func (*State) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9ac6287ce250c9a, []int{8}
+	return fileDescriptor_e9ac6287ce250c9a, []int{11}
 }
 func (m *State) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_State.Unmarshal(m, b)
@@ -570,19 +781,23 @@ func (m *State) GetStorageRoot() []byte {
 }
 
 type Receipt struct {
-	ContractAddress      []byte   `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
-	Status               string   `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	Ret                  string   `protobuf:"bytes,3,opt,name=ret,proto3" json:"ret,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	ContractAddress      []byte       `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
+	Status               string       `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Ret                  string       `protobuf:"bytes,3,opt,name=ret,proto3" json:"ret,omitempty"`
+	GasUsed              uint64       `protobuf:"varint,4,opt,name=gasUsed,proto3" json:"gasUsed,omitempty"`
+	CallTrace            []*CallTrace `protobuf:"bytes,5,rep,name=callTrace,proto3" json:"callTrace,omitempty"`
+	Events               []*Event     `protobuf:"bytes,6,rep,name=events,proto3" json:"events,omitempty"`
+	Steps                []*TraceStep `protobuf:"bytes,7,rep,name=steps,proto3" json:"steps,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
 func (m *Receipt) Reset()         { *m = Receipt{} }
 func (m *Receipt) String() string { return proto.CompactTextString(m) }
 func (*Receipt) ProtoMessage()    {}
 func (*Receipt) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9ac6287ce250c9a, []int{9}
+	return fileDescriptor_e9ac6287ce250c9a, []int{12}
 }
 func (m *Receipt) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Receipt.Unmarshal(m, b)
@@ -623,133 +838,581 @@ func (m *Receipt) GetRet() string {
 	return ""
 }
 
-type Vote struct {
-	Candidate            []byte   `protobuf:"bytes,1,opt,name=candidate,proto3" json:"candidate,omitempty"`
-	Amount               uint64   `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+func (m *Receipt) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+func (m *Receipt) GetCallTrace() []*CallTrace {
+	if m != nil {
+		return m.CallTrace
+	}
+	return nil
+}
+
+func (m *Receipt) GetEvents() []*Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *Receipt) GetSteps() []*TraceStep {
+	if m != nil {
+		return m.Steps
+	}
+	return nil
+}
+
+type CallTrace struct {
+	Caller               string   `protobuf:"bytes,1,opt,name=caller,proto3" json:"caller,omitempty"`
+	Contract             string   `protobuf:"bytes,2,opt,name=contract,proto3" json:"contract,omitempty"`
+	Function             string   `protobuf:"bytes,3,opt,name=function,proto3" json:"function,omitempty"`
+	Args                 string   `protobuf:"bytes,4,opt,name=args,proto3" json:"args,omitempty"`
+	Delegate             bool     `protobuf:"varint,5,opt,name=delegate,proto3" json:"delegate,omitempty"`
+	Depth                uint32   `protobuf:"varint,6,opt,name=depth,proto3" json:"depth,omitempty"`
+	Status               string   `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	Ret                  string   `protobuf:"bytes,8,opt,name=ret,proto3" json:"ret,omitempty"`
+	GasUsed              uint64   `protobuf:"varint,9,opt,name=gasUsed,proto3" json:"gasUsed,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Vote) Reset()         { *m = Vote{} }
-func (m *Vote) String() string { return proto.CompactTextString(m) }
-func (*Vote) ProtoMessage()    {}
-func (*Vote) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9ac6287ce250c9a, []int{10}
+func (m *CallTrace) Reset()         { *m = CallTrace{} }
+func (m *CallTrace) String() string { return proto.CompactTextString(m) }
+func (*CallTrace) ProtoMessage()    {}
+func (*CallTrace) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9ac6287ce250c9a, []int{13}
 }
-func (m *Vote) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Vote.Unmarshal(m, b)
+func (m *CallTrace) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CallTrace.Unmarshal(m, b)
 }
-func (m *Vote) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Vote.Marshal(b, m, deterministic)
+func (m *CallTrace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CallTrace.Marshal(b, m, deterministic)
 }
-func (m *Vote) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Vote.Merge(m, src)
+func (m *CallTrace) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CallTrace.Merge(m, src)
 }
-func (m *Vote) XXX_Size() int {
-	return xxx_messageInfo_Vote.Size(m)
+func (m *CallTrace) XXX_Size() int {
+	return xxx_messageInfo_CallTrace.Size(m)
 }
-func (m *Vote) XXX_DiscardUnknown() {
-	xxx_messageInfo_Vote.DiscardUnknown(m)
+func (m *CallTrace) XXX_DiscardUnknown() {
+	xxx_messageInfo_CallTrace.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Vote proto.InternalMessageInfo
+var xxx_messageInfo_CallTrace proto.InternalMessageInfo
 
-func (m *Vote) GetCandidate() []byte {
+func (m *CallTrace) GetCaller() string {
 	if m != nil {
-		return m.Candidate
+		return m.Caller
 	}
-	return nil
+	return ""
 }
 
-func (m *Vote) GetAmount() uint64 {
+func (m *CallTrace) GetContract() string {
 	if m != nil {
-		return m.Amount
+		return m.Contract
 	}
-	return 0
+	return ""
 }
 
-type VoteList struct {
-	Votes                []*Vote  `protobuf:"bytes,1,rep,name=votes,proto3" json:"votes,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *CallTrace) GetFunction() string {
+	if m != nil {
+		return m.Function
+	}
+	return ""
 }
 
-func (m *VoteList) Reset()         { *m = VoteList{} }
-func (m *VoteList) String() string { return proto.CompactTextString(m) }
-func (*VoteList) ProtoMessage()    {}
-func (*VoteList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9ac6287ce250c9a, []int{11}
-}
-func (m *VoteList) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_VoteList.Unmarshal(m, b)
-}
-func (m *VoteList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_VoteList.Marshal(b, m, deterministic)
+func (m *CallTrace) GetArgs() string {
+	if m != nil {
+		return m.Args
+	}
+	return ""
 }
-func (m *VoteList) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_VoteList.Merge(m, src)
+
+func (m *CallTrace) GetDelegate() bool {
+	if m != nil {
+		return m.Delegate
+	}
+	return false
 }
-func (m *VoteList) XXX_Size() int {
-	return xxx_messageInfo_VoteList.Size(m)
+
+func (m *CallTrace) GetDepth() uint32 {
+	if m != nil {
+		return m.Depth
+	}
+	return 0
 }
-func (m *VoteList) XXX_DiscardUnknown() {
-	xxx_messageInfo_VoteList.DiscardUnknown(m)
+
+func (m *CallTrace) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
 }
 
-var xxx_messageInfo_VoteList proto.InternalMessageInfo
+func (m *CallTrace) GetRet() string {
+	if m != nil {
+		return m.Ret
+	}
+	return ""
+}
 
-func (m *VoteList) GetVotes() []*Vote {
+func (m *CallTrace) GetGasUsed() uint64 {
 	if m != nil {
-		return m.Votes
+		return m.GasUsed
 	}
-	return nil
+	return 0
 }
 
-type FnArgument struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+// TraceStep is a step of a traced contract execution. The op is one of enter,
+// call, return, getItem, setItem and print. The gas is the gas used from the
+// step to the next one in the same contract.
+type TraceStep struct {
+	Op                   string   `protobuf:"bytes,1,opt,name=op,proto3" json:"op,omitempty"`
+	Contract             string   `protobuf:"bytes,2,opt,name=contract,proto3" json:"contract,omitempty"`
+	Depth                uint32   `protobuf:"varint,3,opt,name=depth,proto3" json:"depth,omitempty"`
+	Function             string   `protobuf:"bytes,4,opt,name=function,proto3" json:"function,omitempty"`
+	Line                 int32    `protobuf:"varint,5,opt,name=line,proto3" json:"line,omitempty"`
+	Key                  string   `protobuf:"bytes,6,opt,name=key,proto3" json:"key,omitempty"`
+	OldValue             string   `protobuf:"bytes,7,opt,name=oldValue,proto3" json:"oldValue,omitempty"`
+	Value                string   `protobuf:"bytes,8,opt,name=value,proto3" json:"value,omitempty"`
+	Message              string   `protobuf:"bytes,9,opt,name=message,proto3" json:"message,omitempty"`
+	Gas                  uint64   `protobuf:"varint,10,opt,name=gas,proto3" json:"gas,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *FnArgument) Reset()         { *m = FnArgument{} }
-func (m *FnArgument) String() string { return proto.CompactTextString(m) }
-func (*FnArgument) ProtoMessage()    {}
-func (*FnArgument) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9ac6287ce250c9a, []int{12}
+func (m *TraceStep) Reset()         { *m = TraceStep{} }
+func (m *TraceStep) String() string { return proto.CompactTextString(m) }
+func (*TraceStep) ProtoMessage()    {}
+func (*TraceStep) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9ac6287ce250c9a, []int{14}
 }
-func (m *FnArgument) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_FnArgument.Unmarshal(m, b)
+func (m *TraceStep) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TraceStep.Unmarshal(m, b)
 }
-func (m *FnArgument) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_FnArgument.Marshal(b, m, deterministic)
+func (m *TraceStep) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TraceStep.Marshal(b, m, deterministic)
 }
-func (m *FnArgument) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FnArgument.Merge(m, src)
+func (m *TraceStep) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceStep.Merge(m, src)
 }
-func (m *FnArgument) XXX_Size() int {
-	return xxx_messageInfo_FnArgument.Size(m)
+func (m *TraceStep) XXX_Size() int {
+	return xxx_messageInfo_TraceStep.Size(m)
 }
-func (m *FnArgument) XXX_DiscardUnknown() {
-	xxx_messageInfo_FnArgument.DiscardUnknown(m)
+func (m *TraceStep) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceStep.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_FnArgument proto.InternalMessageInfo
+var xxx_messageInfo_TraceStep proto.InternalMessageInfo
 
-func (m *FnArgument) GetName() string {
+func (m *TraceStep) GetOp() string {
 	if m != nil {
-		return m.Name
+		return m.Op
 	}
 	return ""
 }
 
-type Function struct {
-	Name                 string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Arguments            []*FnArgument `protobuf:"bytes,2,rep,name=arguments,proto3" json:"arguments,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
+func (m *TraceStep) GetContract() string {
+	if m != nil {
+		return m.Contract
+	}
+	return ""
+}
+
+func (m *TraceStep) GetDepth() uint32 {
+	if m != nil {
+		return m.Depth
+	}
+	return 0
+}
+
+func (m *TraceStep) GetFunction() string {
+	if m != nil {
+		return m.Function
+	}
+	return ""
+}
+
+func (m *TraceStep) GetLine() int32 {
+	if m != nil {
+		return m.Line
+	}
+	return 0
+}
+
+func (m *TraceStep) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *TraceStep) GetOldValue() string {
+	if m != nil {
+		return m.OldValue
+	}
+	return ""
+}
+
+func (m *TraceStep) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *TraceStep) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *TraceStep) GetGas() uint64 {
+	if m != nil {
+		return m.Gas
+	}
+	return 0
+}
+
+type Event struct {
+	ContractAddress      []byte   `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
+	EventName            string   `protobuf:"bytes,2,opt,name=eventName,proto3" json:"eventName,omitempty"`
+	JsonArgs             string   `protobuf:"bytes,3,opt,name=jsonArgs,proto3" json:"jsonArgs,omitempty"`
+	EventIdx             int32    `protobuf:"varint,4,opt,name=eventIdx,proto3" json:"eventIdx,omitempty"`
+	TxHash               []byte   `protobuf:"bytes,5,opt,name=txHash,proto3" json:"txHash,omitempty"`
+	BlockHash            []byte   `protobuf:"bytes,6,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	BlockNo              uint64   `protobuf:"varint,7,opt,name=blockNo,proto3" json:"blockNo,omitempty"`
+	TxIndex              int32    `protobuf:"varint,8,opt,name=txIndex,proto3" json:"txIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9ac6287ce250c9a, []int{15}
+}
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetContractAddress() []byte {
+	if m != nil {
+		return m.ContractAddress
+	}
+	return nil
+}
+
+func (m *Event) GetEventName() string {
+	if m != nil {
+		return m.EventName
+	}
+	return ""
+}
+
+func (m *Event) GetJsonArgs() string {
+	if m != nil {
+		return m.JsonArgs
+	}
+	return ""
+}
+
+func (m *Event) GetEventIdx() int32 {
+	if m != nil {
+		return m.EventIdx
+	}
+	return 0
+}
+
+func (m *Event) GetTxHash() []byte {
+	if m != nil {
+		return m.TxHash
+	}
+	return nil
+}
+
+func (m *Event) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *Event) GetBlockNo() uint64 {
+	if m != nil {
+		return m.BlockNo
+	}
+	return 0
+}
+
+func (m *Event) GetTxIndex() int32 {
+	if m != nil {
+		return m.TxIndex
+	}
+	return 0
+}
+
+type EventList struct {
+	Events               []*Event `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EventList) Reset()         { *m = EventList{} }
+func (m *EventList) String() string { return proto.CompactTextString(m) }
+func (*EventList) ProtoMessage()    {}
+func (*EventList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9ac6287ce250c9a, []int{16}
+}
+func (m *EventList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventList.Unmarshal(m, b)
+}
+func (m *EventList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventList.Marshal(b, m, deterministic)
+}
+func (m *EventList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventList.Merge(m, src)
+}
+func (m *EventList) XXX_Size() int {
+	return xxx_messageInfo_EventList.Size(m)
+}
+func (m *EventList) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventList proto.InternalMessageInfo
+
+func (m *EventList) GetEvents() []*Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type FilterInfo struct {
+	ContractAddress      []byte   `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
+	EventName            string   `protobuf:"bytes,2,opt,name=eventName,proto3" json:"eventName,omitempty"`
+	Blockfrom            uint64   `protobuf:"varint,3,opt,name=blockfrom,proto3" json:"blockfrom,omitempty"`
+	Blockto              uint64   `protobuf:"varint,4,opt,name=blockto,proto3" json:"blockto,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FilterInfo) Reset()         { *m = FilterInfo{} }
+func (m *FilterInfo) String() string { return proto.CompactTextString(m) }
+func (*FilterInfo) ProtoMessage()    {}
+func (*FilterInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9ac6287ce250c9a, []int{17}
+}
+func (m *FilterInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FilterInfo.Unmarshal(m, b)
+}
+func (m *FilterInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FilterInfo.Marshal(b, m, deterministic)
+}
+func (m *FilterInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FilterInfo.Merge(m, src)
+}
+func (m *FilterInfo) XXX_Size() int {
+	return xxx_messageInfo_FilterInfo.Size(m)
+}
+func (m *FilterInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_FilterInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FilterInfo proto.InternalMessageInfo
+
+func (m *FilterInfo) GetContractAddress() []byte {
+	if m != nil {
+		return m.ContractAddress
+	}
+	return nil
+}
+
+func (m *FilterInfo) GetEventName() string {
+	if m != nil {
+		return m.EventName
+	}
+	return ""
+}
+
+func (m *FilterInfo) GetBlockfrom() uint64 {
+	if m != nil {
+		return m.Blockfrom
+	}
+	return 0
+}
+
+func (m *FilterInfo) GetBlockto() uint64 {
+	if m != nil {
+		return m.Blockto
+	}
+	return 0
+}
+
+type Vote struct {
+	Candidate            []byte   `protobuf:"bytes,1,opt,name=candidate,proto3" json:"candidate,omitempty"`
+	Amount               uint64   `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Vote) Reset()         { *m = Vote{} }
+func (m *Vote) String() string { return proto.CompactTextString(m) }
+func (*Vote) ProtoMessage()    {}
+func (*Vote) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9ac6287ce250c9a, []int{18}
+}
+func (m *Vote) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Vote.Unmarshal(m, b)
+}
+func (m *Vote) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Vote.Marshal(b, m, deterministic)
+}
+func (m *Vote) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Vote.Merge(m, src)
+}
+func (m *Vote) XXX_Size() int {
+	return xxx_messageInfo_Vote.Size(m)
+}
+func (m *Vote) XXX_DiscardUnknown() {
+	xxx_messageInfo_Vote.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Vote proto.InternalMessageInfo
+
+func (m *Vote) GetCandidate() []byte {
+	if m != nil {
+		return m.Candidate
+	}
+	return nil
+}
+
+func (m *Vote) GetAmount() uint64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+type VoteList struct {
+	Votes                []*Vote  `protobuf:"bytes,1,rep,name=votes,proto3" json:"votes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VoteList) Reset()         { *m = VoteList{} }
+func (m *VoteList) String() string { return proto.CompactTextString(m) }
+func (*VoteList) ProtoMessage()    {}
+func (*VoteList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9ac6287ce250c9a, []int{19}
+}
+func (m *VoteList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VoteList.Unmarshal(m, b)
+}
+func (m *VoteList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VoteList.Marshal(b, m, deterministic)
+}
+func (m *VoteList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VoteList.Merge(m, src)
+}
+func (m *VoteList) XXX_Size() int {
+	return xxx_messageInfo_VoteList.Size(m)
+}
+func (m *VoteList) XXX_DiscardUnknown() {
+	xxx_messageInfo_VoteList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VoteList proto.InternalMessageInfo
+
+func (m *VoteList) GetVotes() []*Vote {
+	if m != nil {
+		return m.Votes
+	}
+	return nil
+}
+
+type FnArgument struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FnArgument) Reset()         { *m = FnArgument{} }
+func (m *FnArgument) String() string { return proto.CompactTextString(m) }
+func (*FnArgument) ProtoMessage()    {}
+func (*FnArgument) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9ac6287ce250c9a, []int{20}
+}
+func (m *FnArgument) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FnArgument.Unmarshal(m, b)
+}
+func (m *FnArgument) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FnArgument.Marshal(b, m, deterministic)
+}
+func (m *FnArgument) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FnArgument.Merge(m, src)
+}
+func (m *FnArgument) XXX_Size() int {
+	return xxx_messageInfo_FnArgument.Size(m)
+}
+func (m *FnArgument) XXX_DiscardUnknown() {
+	xxx_messageInfo_FnArgument.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FnArgument proto.InternalMessageInfo
+
+func (m *FnArgument) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FnArgument) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+type Function struct {
+	Name                 string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Arguments            []*FnArgument `protobuf:"bytes,2,rep,name=arguments,proto3" json:"arguments,omitempty"`
+	Returns              []string      `protobuf:"bytes,3,rep,name=returns,proto3" json:"returns,omitempty"`
+	View                 bool          `protobuf:"varint,4,opt,name=view,proto3" json:"view,omitempty"`
+	Payable              bool          `protobuf:"varint,5,opt,name=payable,proto3" json:"payable,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
 	XXX_sizecache        int32         `json:"-"`
 }
 
@@ -757,7 +1420,7 @@ func (m *Function) Reset()         { *m = Function{} }
 func (m *Function) String() string { return proto.CompactTextString(m) }
 func (*Function) ProtoMessage()    {}
 func (*Function) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9ac6287ce250c9a, []int{13}
+	return fileDescriptor_e9ac6287ce250c9a, []int{21}
 }
 func (m *Function) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Function.Unmarshal(m, b)
@@ -791,10 +1454,36 @@ func (m *Function) GetArguments() []*FnArgument {
 	return nil
 }
 
+func (m *Function) GetReturns() []string {
+	if m != nil {
+		return m.Returns
+	}
+	return nil
+}
+
+func (m *Function) GetView() bool {
+	if m != nil {
+		return m.View
+	}
+	return false
+}
+
+func (m *Function) GetPayable() bool {
+	if m != nil {
+		return m.Payable
+	}
+	return false
+}
+
 type ABI struct {
 	Version              string      `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
 	Language             string      `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
 	Functions            []*Function `protobuf:"bytes,3,rep,name=functions,proto3" json:"functions,omitempty"`
+	Upgradable           bool        `protobuf:"varint,4,opt,name=upgradable,proto3" json:"upgradable,omitempty"`
+	StateVariables       []*StateVar `protobuf:"bytes,5,rep,name=stateVariables,proto3" json:"stateVariables,omitempty"`
+	Compiler             string      `protobuf:"bytes,6,opt,name=compiler,proto3" json:"compiler,omitempty"`
+	Source               string      `protobuf:"bytes,7,opt,name=source,proto3" json:"source,omitempty"`
+	Library              bool        `protobuf:"varint,8,opt,name=library,proto3" json:"library,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
 	XXX_unrecognized     []byte      `json:"-"`
 	XXX_sizecache        int32       `json:"-"`
@@ -804,7 +1493,7 @@ func (m *ABI) Reset()         { *m = ABI{} }
 func (m *ABI) String() string { return proto.CompactTextString(m) }
 func (*ABI) ProtoMessage()    {}
 func (*ABI) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9ac6287ce250c9a, []int{14}
+	return fileDescriptor_e9ac6287ce250c9a, []int{22}
 }
 func (m *ABI) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ABI.Unmarshal(m, b)
@@ -845,6 +1534,219 @@ func (m *ABI) GetFunctions() []*Function {
 	return nil
 }
 
+func (m *ABI) GetUpgradable() bool {
+	if m != nil {
+		return m.Upgradable
+	}
+	return false
+}
+
+func (m *ABI) GetStateVariables() []*StateVar {
+	if m != nil {
+		return m.StateVariables
+	}
+	return nil
+}
+
+func (m *ABI) GetCompiler() string {
+	if m != nil {
+		return m.Compiler
+	}
+	return ""
+}
+
+func (m *ABI) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *ABI) GetLibrary() bool {
+	if m != nil {
+		return m.Library
+	}
+	return false
+}
+
+type StateVar struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateVar) Reset()         { *m = StateVar{} }
+func (m *StateVar) String() string { return proto.CompactTextString(m) }
+func (*StateVar) ProtoMessage()    {}
+func (*StateVar) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9ac6287ce250c9a, []int{23}
+}
+func (m *StateVar) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateVar.Unmarshal(m, b)
+}
+func (m *StateVar) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateVar.Marshal(b, m, deterministic)
+}
+func (m *StateVar) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateVar.Merge(m, src)
+}
+func (m *StateVar) XXX_Size() int {
+	return xxx_messageInfo_StateVar.Size(m)
+}
+func (m *StateVar) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateVar.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateVar proto.InternalMessageInfo
+
+func (m *StateVar) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *StateVar) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+type ContractVersion struct {
+	Version              uint32   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	CodeHash             []byte   `protobuf:"bytes,2,opt,name=codeHash,proto3" json:"codeHash,omitempty"`
+	BlockNo              uint64   `protobuf:"varint,3,opt,name=blockNo,proto3" json:"blockNo,omitempty"`
+	TxHash               []byte   `protobuf:"bytes,4,opt,name=txHash,proto3" json:"txHash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ContractVersion) Reset()         { *m = ContractVersion{} }
+func (m *ContractVersion) String() string { return proto.CompactTextString(m) }
+func (*ContractVersion) ProtoMessage()    {}
+func (*ContractVersion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9ac6287ce250c9a, []int{24}
+}
+func (m *ContractVersion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContractVersion.Unmarshal(m, b)
+}
+func (m *ContractVersion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContractVersion.Marshal(b, m, deterministic)
+}
+func (m *ContractVersion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContractVersion.Merge(m, src)
+}
+func (m *ContractVersion) XXX_Size() int {
+	return xxx_messageInfo_ContractVersion.Size(m)
+}
+func (m *ContractVersion) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContractVersion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContractVersion proto.InternalMessageInfo
+
+func (m *ContractVersion) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ContractVersion) GetCodeHash() []byte {
+	if m != nil {
+		return m.CodeHash
+	}
+	return nil
+}
+
+func (m *ContractVersion) GetBlockNo() uint64 {
+	if m != nil {
+		return m.BlockNo
+	}
+	return 0
+}
+
+func (m *ContractVersion) GetTxHash() []byte {
+	if m != nil {
+		return m.TxHash
+	}
+	return nil
+}
+
+type ContractInfo struct {
+	Address              []byte             `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Owner                []byte             `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Upgradable           bool               `protobuf:"varint,3,opt,name=upgradable,proto3" json:"upgradable,omitempty"`
+	Destructed           bool               `protobuf:"varint,4,opt,name=destructed,proto3" json:"destructed,omitempty"`
+	Versions             []*ContractVersion `protobuf:"bytes,5,rep,name=versions,proto3" json:"versions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ContractInfo) Reset()         { *m = ContractInfo{} }
+func (m *ContractInfo) String() string { return proto.CompactTextString(m) }
+func (*ContractInfo) ProtoMessage()    {}
+func (*ContractInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9ac6287ce250c9a, []int{25}
+}
+func (m *ContractInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContractInfo.Unmarshal(m, b)
+}
+func (m *ContractInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContractInfo.Marshal(b, m, deterministic)
+}
+func (m *ContractInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContractInfo.Merge(m, src)
+}
+func (m *ContractInfo) XXX_Size() int {
+	return xxx_messageInfo_ContractInfo.Size(m)
+}
+func (m *ContractInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContractInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContractInfo proto.InternalMessageInfo
+
+func (m *ContractInfo) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *ContractInfo) GetOwner() []byte {
+	if m != nil {
+		return m.Owner
+	}
+	return nil
+}
+
+func (m *ContractInfo) GetUpgradable() bool {
+	if m != nil {
+		return m.Upgradable
+	}
+	return false
+}
+
+func (m *ContractInfo) GetDestructed() bool {
+	if m != nil {
+		return m.Destructed
+	}
+	return false
+}
+
+func (m *ContractInfo) GetVersions() []*ContractVersion {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
 type Query struct {
 	ContractAddress      []byte   `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
 	Queryinfo            []byte   `protobuf:"bytes,2,opt,name=queryinfo,proto3" json:"queryinfo,omitempty"`
@@ -857,7 +1759,7 @@ func (m *Query) Reset()         { *m = Query{} }
 func (m *Query) String() string { return proto.CompactTextString(m) }
 func (*Query) ProtoMessage()    {}
 func (*Query) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9ac6287ce250c9a, []int{15}
+	return fileDescriptor_e9ac6287ce250c9a, []int{26}
 }
 func (m *Query) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Query.Unmarshal(m, b)
@@ -898,15 +1800,26 @@ func init() {
 	proto.RegisterType((*TxList)(nil), "types.TxList")
 	proto.RegisterType((*Tx)(nil), "types.Tx")
 	proto.RegisterType((*TxBody)(nil), "types.TxBody")
+	proto.RegisterType((*MultiSig)(nil), "types.MultiSig")
+	proto.RegisterType((*Batch)(nil), "types.Batch")
+	proto.RegisterType((*BatchOperation)(nil), "types.BatchOperation")
 	proto.RegisterType((*TxIdx)(nil), "types.TxIdx")
 	proto.RegisterType((*TxInBlock)(nil), "types.TxInBlock")
 	proto.RegisterType((*State)(nil), "types.State")
 	proto.RegisterType((*Receipt)(nil), "types.Receipt")
+	proto.RegisterType((*CallTrace)(nil), "types.CallTrace")
+	proto.RegisterType((*TraceStep)(nil), "types.TraceStep")
+	proto.RegisterType((*Event)(nil), "types.Event")
+	proto.RegisterType((*EventList)(nil), "types.EventList")
+	proto.RegisterType((*FilterInfo)(nil), "types.FilterInfo")
 	proto.RegisterType((*Vote)(nil), "types.Vote")
 	proto.RegisterType((*VoteList)(nil), "types.VoteList")
 	proto.RegisterType((*FnArgument)(nil), "types.FnArgument")
 	proto.RegisterType((*Function)(nil), "types.Function")
 	proto.RegisterType((*ABI)(nil), "types.ABI")
+	proto.RegisterType((*StateVar)(nil), "types.StateVar")
+	proto.RegisterType((*ContractVersion)(nil), "types.ContractVersion")
+	proto.RegisterType((*ContractInfo)(nil), "types.ContractInfo")
 	proto.RegisterType((*Query)(nil), "types.Query")
 	proto.RegisterEnum("types.TxType", TxType_name, TxType_value)
 }
@@ -914,54 +1827,102 @@ func init() {
 func init() { proto.RegisterFile("blockchain.proto", fileDescriptor_e9ac6287ce250c9a) }
 
 var fileDescriptor_e9ac6287ce250c9a = []byte{
-	// 773 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x5d, 0x8f, 0xe3, 0x34,
-	0x14, 0xa5, 0x6d, 0xd2, 0x99, 0xdc, 0xce, 0xce, 0x16, 0x0b, 0xa1, 0x00, 0x2b, 0xd4, 0x8d, 0x06,
-	0x54, 0xad, 0xb4, 0x1d, 0x69, 0x78, 0xe0, 0x01, 0x5e, 0x5a, 0xb4, 0x0b, 0x85, 0xa5, 0x15, 0xa6,
-	0xda, 0x07, 0x24, 0x1e, 0xdc, 0xc4, 0xd3, 0x1a, 0x1a, 0x3b, 0x24, 0xce, 0x28, 0xfd, 0xcd, 0xfc,
-	0x06, 0x24, 0x74, 0x6f, 0x9c, 0x0f, 0x46, 0x0b, 0x12, 0x4f, 0xf1, 0x39, 0xf7, 0xd3, 0xe7, 0x5e,
-	0x2b, 0x30, 0xdd, 0x9f, 0x4c, 0xfc, 0x7b, 0x7c, 0x14, 0x4a, 0x2f, 0xb2, 0xdc, 0x58, 0xc3, 0x7c,
-	0x7b, 0xce, 0x64, 0x11, 0xa5, 0xe0, 0xaf, 0xd0, 0xc4, 0x18, 0x78, 0x47, 0x51, 0x1c, 0xc3, 0xc1,
-	0x6c, 0x30, 0xbf, 0xe2, 0x74, 0x66, 0x2f, 0x60, 0x7c, 0x94, 0x22, 0x91, 0x79, 0x38, 0x9c, 0x0d,
-	0xe6, 0x93, 0x3b, 0xb6, 0xa0, 0xa0, 0x05, 0x45, 0x7c, 0x47, 0x16, 0xee, 0x3c, 0xd8, 0x0d, 0x78,
-	0x7b, 0x93, 0x9c, 0xc3, 0x11, 0x79, 0x4e, 0xfb, 0x9e, 0x2b, 0x93, 0x9c, 0x39, 0x59, 0xa3, 0xbf,
-	0x06, 0x30, 0xe9, 0x45, 0xb3, 0x1b, 0x78, 0x92, 0xe5, 0xf2, 0xa1, 0xa6, 0xba, 0xf2, 0xff, 0x24,
-	0x59, 0x08, 0x17, 0xd4, 0xff, 0xc6, 0x50, 0x23, 0x1e, 0x6f, 0x20, 0x7b, 0x06, 0x81, 0x55, 0xa9,
-	0x2c, 0xac, 0x48, 0x33, 0x2a, 0x3d, 0xe2, 0x1d, 0xc1, 0x3e, 0x87, 0x6b, 0x72, 0x2c, 0xb8, 0x31,
-	0x96, 0xd2, 0x7b, 0x94, 0xfe, 0x11, 0xcb, 0x66, 0x30, 0xb1, 0x55, 0xe7, 0xe4, 0x93, 0x53, 0x9f,
-	0x62, 0x1f, 0xc3, 0x65, 0x6c, 0xf4, 0xbd, 0xca, 0xd3, 0x22, 0x1c, 0x53, 0x0b, 0x2d, 0x66, 0x1f,
-	0xc2, 0x38, 0x2b, 0xf7, 0x3f, 0xc8, 0x73, 0x78, 0x41, 0x81, 0x0e, 0xa1, 0xa2, 0x85, 0x3a, 0xe8,
-	0xf0, 0xb2, 0x56, 0x14, 0xcf, 0xd1, 0x1c, 0x82, 0x56, 0x12, 0xf6, 0x09, 0x8c, 0x6c, 0x55, 0x84,
-	0x83, 0xd9, 0x68, 0x3e, 0xb9, 0x0b, 0x9c, 0x62, 0xbb, 0x8a, 0x23, 0x1b, 0x7d, 0x06, 0xe3, 0x5d,
-	0xf5, 0x46, 0x15, 0xf6, 0xbf, 0xdd, 0xbe, 0x82, 0xe1, 0xae, 0x7a, 0xe7, 0xf0, 0x9e, 0xbb, 0x81,
-	0xd4, 0xa3, 0x7b, 0xd2, 0xc6, 0xf5, 0xa6, 0xf1, 0xe7, 0x00, 0x8b, 0x50, 0x2f, 0x1f, 0x80, 0xaf,
-	0x8d, 0x8e, 0x25, 0xa5, 0xf0, 0x78, 0x0d, 0x50, 0x78, 0x11, 0xc7, 0xa6, 0xd4, 0x96, 0xd2, 0x5c,
-	0xf1, 0x06, 0xa2, 0xf0, 0xb9, 0x8c, 0x55, 0xa6, 0xa4, 0xb6, 0x24, 0xfc, 0x15, 0xef, 0x08, 0x94,
-	0x44, 0xa4, 0x14, 0xe6, 0x51, 0x3a, 0x87, 0x30, 0x5f, 0x26, 0xce, 0x27, 0x23, 0x12, 0x27, 0x72,
-	0x03, 0xb1, 0xfe, 0x49, 0xa5, 0xca, 0x3a, 0x75, 0x6b, 0x80, 0x6c, 0x96, 0xab, 0x58, 0x92, 0xb2,
-	0x1e, 0xaf, 0x01, 0xde, 0x0c, 0x2f, 0x43, 0xc2, 0x5e, 0xf7, 0x6e, 0xb6, 0x3b, 0x67, 0x92, 0x93,
-	0xa9, 0xd5, 0x3e, 0xe8, 0x69, 0xff, 0x25, 0xf8, 0xbb, 0x6a, 0x9d, 0x54, 0xd8, 0xfb, 0xfe, 0xd1,
-	0xc2, 0x75, 0x04, 0x9b, 0xc2, 0x48, 0x25, 0x15, 0xdd, 0xd7, 0xe7, 0x78, 0x8c, 0xbe, 0x87, 0x60,
-	0x57, 0xad, 0x75, 0xfd, 0x4e, 0x22, 0xf0, 0x2d, 0x66, 0xa1, 0xc0, 0xc9, 0xdd, 0x55, 0x5b, 0x7d,
-	0x9d, 0x54, 0xbc, 0x36, 0xb1, 0x8f, 0x60, 0x68, 0x2b, 0x27, 0x7c, 0x6f, 0x60, 0x43, 0x5b, 0x45,
-	0x25, 0xf8, 0x3f, 0x5b, 0x61, 0xe5, 0xbf, 0x0b, 0xbe, 0x17, 0x27, 0x81, 0x7c, 0xb3, 0xe9, 0x35,
-	0xac, 0x37, 0x30, 0x91, 0xd4, 0x73, 0xad, 0x77, 0x8b, 0x71, 0x7f, 0x0b, 0x6b, 0x72, 0x71, 0x90,
-	0xb8, 0xb0, 0x6e, 0xc9, 0xfb, 0x54, 0xf4, 0x2b, 0x5c, 0x70, 0x19, 0x4b, 0x95, 0x59, 0x36, 0x87,
-	0xa7, 0xb1, 0xd1, 0x36, 0x17, 0xb1, 0x5d, 0x26, 0x49, 0x2e, 0x8b, 0xc2, 0x69, 0xf0, 0x98, 0xc6,
-	0x29, 0x16, 0x56, 0xd8, 0xb2, 0xa0, 0x5e, 0x02, 0xee, 0x10, 0x2a, 0x94, 0xcb, 0x7a, 0xea, 0x01,
-	0xc7, 0x63, 0xf4, 0x35, 0x78, 0x6f, 0x8d, 0x95, 0xa8, 0x6c, 0x2c, 0x74, 0xa2, 0x12, 0x61, 0x65,
-	0xa3, 0x6c, 0x4b, 0xf4, 0xb6, 0x62, 0xd8, 0xdf, 0x8a, 0xe8, 0x25, 0x5c, 0x62, 0x34, 0x2d, 0xfb,
-	0x73, 0xf0, 0x1f, 0x8c, 0x95, 0xcd, 0xba, 0x4f, 0x9c, 0x7a, 0x68, 0xe7, 0xb5, 0x25, 0x9a, 0x01,
-	0xbc, 0xd6, 0xcb, 0xfc, 0x50, 0xa6, 0xb8, 0x6a, 0x0c, 0x3c, 0x2d, 0xd2, 0xba, 0x5a, 0xc0, 0xe9,
-	0x1c, 0x6d, 0xe1, 0xf2, 0x75, 0xa9, 0x63, 0xab, 0x8c, 0x7e, 0x97, 0x9d, 0xdd, 0x42, 0x20, 0x5c,
-	0x3c, 0xde, 0x0d, 0x0b, 0xbd, 0xef, 0x0a, 0x75, 0x99, 0x79, 0xe7, 0x13, 0xfd, 0x06, 0xa3, 0xe5,
-	0x6a, 0x8d, 0xd3, 0x79, 0x90, 0x79, 0xa1, 0x8c, 0x76, 0xe9, 0x1a, 0x88, 0xd3, 0x39, 0x09, 0x7d,
-	0x28, 0xc5, 0x41, 0x3a, 0xb1, 0x5a, 0xcc, 0x5e, 0x42, 0x70, 0xef, 0xba, 0x29, 0xc2, 0x11, 0x55,
-	0x7b, 0xda, 0x54, 0x73, 0x3c, 0xef, 0x3c, 0xa2, 0x2d, 0xf8, 0x3f, 0x95, 0x32, 0x3f, 0xff, 0x8f,
-	0x41, 0x3d, 0x83, 0xe0, 0x0f, 0x0c, 0x51, 0xfa, 0xde, 0xb8, 0x87, 0xda, 0x11, 0x2f, 0x6e, 0xf0,
-	0x91, 0xe3, 0xdb, 0x60, 0x00, 0xe3, 0xcd, 0x96, 0xff, 0xb8, 0x7c, 0x33, 0x7d, 0x8f, 0x5d, 0x03,
-	0x7c, 0xbb, 0x7d, 0xfb, 0x8a, 0x6f, 0x96, 0x9b, 0x6f, 0x5e, 0x4d, 0x07, 0xab, 0xd9, 0x2f, 0x9f,
-	0x1e, 0x94, 0x3d, 0x96, 0xfb, 0x45, 0x6c, 0xd2, 0x5b, 0x21, 0xf3, 0x83, 0x51, 0xa6, 0xfe, 0xde,
-	0x52, 0xb3, 0xfb, 0x31, 0xfd, 0x38, 0xbe, 0xf8, 0x3b, 0x00, 0x00, 0xff, 0xff, 0x47, 0x9e, 0xc8,
-	0x56, 0x4c, 0x06, 0x00, 0x00,
+	// 1541 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0xcd, 0x6e, 0x1b, 0x47,
+	0x12, 0x5e, 0x72, 0x38, 0x24, 0xa7, 0x28, 0xc9, 0xda, 0xc6, 0xae, 0x31, 0xbb, 0x6b, 0x18, 0xf4,
+	0xc0, 0x6b, 0x08, 0x5e, 0x58, 0xc6, 0x6a, 0x77, 0xe1, 0xc3, 0x1a, 0x01, 0x28, 0x45, 0xb6, 0x95,
+	0xd8, 0x92, 0xd3, 0xa2, 0x85, 0x20, 0xa7, 0x34, 0x67, 0x5a, 0xe4, 0xc4, 0xc3, 0xe9, 0xc9, 0x4c,
+	0x53, 0x26, 0xdf, 0x21, 0x97, 0x9c, 0x92, 0xe7, 0xc8, 0x5b, 0xe4, 0x41, 0x72, 0xcc, 0x21, 0x87,
+	0xdc, 0x83, 0xaa, 0xee, 0xf9, 0x21, 0x6d, 0x19, 0x31, 0x90, 0x13, 0xfb, 0xab, 0xae, 0xae, 0xae,
+	0xaf, 0xfe, 0x7a, 0x08, 0xbb, 0x93, 0x44, 0x85, 0xaf, 0xc3, 0x99, 0x88, 0xd3, 0xfd, 0x2c, 0x57,
+	0x5a, 0x31, 0x57, 0xaf, 0x32, 0x59, 0x04, 0x73, 0x70, 0x0f, 0x71, 0x8b, 0x31, 0xe8, 0xcc, 0x44,
+	0x31, 0xf3, 0x5b, 0xc3, 0xd6, 0xde, 0x16, 0xa7, 0x35, 0xbb, 0x0f, 0xdd, 0x99, 0x14, 0x91, 0xcc,
+	0xfd, 0xf6, 0xb0, 0xb5, 0x37, 0x38, 0x60, 0xfb, 0x74, 0x68, 0x9f, 0x4e, 0x3c, 0xa3, 0x1d, 0x6e,
+	0x35, 0xd8, 0x5d, 0xe8, 0x4c, 0x54, 0xb4, 0xf2, 0x1d, 0xd2, 0xdc, 0x6d, 0x6a, 0x1e, 0xaa, 0x68,
+	0xc5, 0x69, 0x37, 0xf8, 0xb1, 0x0d, 0x83, 0xc6, 0x69, 0x76, 0x17, 0xb6, 0xb3, 0x5c, 0x5e, 0x19,
+	0x51, 0x7d, 0xfd, 0xba, 0x90, 0xf9, 0xd0, 0x23, 0xff, 0x4f, 0x15, 0x39, 0xd2, 0xe1, 0x25, 0x64,
+	0xb7, 0xc0, 0xd3, 0xf1, 0x5c, 0x16, 0x5a, 0xcc, 0x33, 0xba, 0xda, 0xe1, 0xb5, 0x80, 0xdd, 0x83,
+	0x1d, 0x52, 0x2c, 0xb8, 0x52, 0x9a, 0xcc, 0x77, 0xc8, 0xfc, 0x86, 0x94, 0x0d, 0x61, 0xa0, 0x97,
+	0xb5, 0x92, 0x4b, 0x4a, 0x4d, 0x11, 0xfb, 0x3b, 0xf4, 0x43, 0x95, 0x5e, 0xc6, 0xf9, 0xbc, 0xf0,
+	0xbb, 0xe4, 0x42, 0x85, 0xd9, 0x4d, 0xe8, 0x66, 0x8b, 0xc9, 0xa7, 0x72, 0xe5, 0xf7, 0xe8, 0xa0,
+	0x45, 0x6c, 0x0f, 0x6e, 0x84, 0x2a, 0x4e, 0x27, 0xa2, 0x90, 0xa3, 0x30, 0x54, 0x8b, 0x54, 0xfb,
+	0x1e, 0x29, 0x6c, 0x8a, 0x31, 0xf6, 0x45, 0x3c, 0x4d, 0xfd, 0xbe, 0x89, 0x3d, 0xae, 0xd1, 0x27,
+	0x79, 0x25, 0x53, 0x5d, 0x1c, 0x26, 0x4a, 0xcd, 0x7d, 0x30, 0x3e, 0x35, 0x44, 0xc1, 0x1e, 0x78,
+	0x55, 0x78, 0xd9, 0x3f, 0xc0, 0xd1, 0xcb, 0xc2, 0x6f, 0x0d, 0x9d, 0xbd, 0xc1, 0x81, 0x67, 0xa3,
+	0x3f, 0x5e, 0x72, 0x94, 0x06, 0xff, 0x84, 0xee, 0x78, 0xf9, 0x3c, 0x2e, 0xf4, 0xfb, 0xd5, 0xfe,
+	0x0f, 0xed, 0xf1, 0xf2, 0x9d, 0x85, 0x70, 0xc7, 0x26, 0xd7, 0x94, 0xc1, 0x76, 0x75, 0xae, 0x91,
+	0xd9, 0xef, 0x1d, 0xe8, 0x1a, 0x01, 0xfb, 0x0b, 0xb8, 0xa9, 0x4a, 0x43, 0x49, 0x26, 0x3a, 0xdc,
+	0x00, 0x4c, 0xa2, 0xb0, 0x61, 0x68, 0x93, 0xe9, 0x12, 0x62, 0x12, 0x73, 0x19, 0xc6, 0x59, 0x2c,
+	0x53, 0x4d, 0x49, 0xdc, 0xe2, 0xb5, 0x00, 0xc3, 0x2b, 0xe6, 0x74, 0xac, 0x43, 0xe6, 0x2c, 0x42,
+	0x7b, 0x99, 0x58, 0x25, 0x4a, 0x44, 0x36, 0x61, 0x25, 0xc4, 0xfb, 0x93, 0x78, 0x1e, 0x6b, 0x9b,
+	0x29, 0x03, 0x50, 0x9a, 0xe5, 0x71, 0x28, 0x29, 0x4b, 0x1d, 0x6e, 0x00, 0x32, 0x43, 0x32, 0x14,
+	0xfa, 0x9d, 0x06, 0xb3, 0xf1, 0x2a, 0x93, 0x9c, 0xb6, 0xaa, 0xec, 0x78, 0x8d, 0xec, 0xfc, 0x0b,
+	0xfa, 0xf3, 0x45, 0xa2, 0xe3, 0xf3, 0x78, 0x4a, 0xa9, 0x19, 0x1c, 0xdc, 0xb0, 0x47, 0x5f, 0x58,
+	0x31, 0xaf, 0x14, 0xb0, 0x78, 0x2e, 0xa5, 0x7c, 0x29, 0x56, 0x32, 0xf7, 0x07, 0x64, 0xa4, 0xc2,
+	0x2c, 0x80, 0xad, 0x72, 0x7d, 0x8e, 0x97, 0x6c, 0xd1, 0xfe, 0x9a, 0x8c, 0xdd, 0x06, 0xb8, 0x12,
+	0x49, 0x1c, 0x8d, 0x2e, 0xb5, 0xcc, 0xfd, 0x6d, 0x72, 0xbf, 0x21, 0xa9, 0xf6, 0x5f, 0xa5, 0x3a,
+	0x4e, 0xfc, 0x9d, 0xc6, 0x3e, 0x49, 0x82, 0xcf, 0xa1, 0x5f, 0x7a, 0x45, 0x0d, 0x33, 0xcb, 0x65,
+	0x31, 0x53, 0x49, 0x44, 0xf9, 0xd9, 0xe6, 0xb5, 0x80, 0x62, 0x4a, 0xc5, 0x5b, 0xf8, 0xed, 0xa1,
+	0x43, 0x31, 0x35, 0x10, 0xa3, 0x87, 0xc4, 0x0b, 0xdf, 0x21, 0xb9, 0x01, 0xc1, 0x47, 0xe0, 0x1e,
+	0x0a, 0x1d, 0xce, 0xd8, 0xff, 0x00, 0x54, 0x26, 0x73, 0xa1, 0x63, 0x95, 0x96, 0xe5, 0xf5, 0xd7,
+	0x72, 0x06, 0xa0, 0xc6, 0x59, 0xb9, 0xcb, 0x1b, 0x8a, 0xc1, 0x97, 0xb0, 0xb3, 0xbe, 0xbb, 0x5e,
+	0x0b, 0xad, 0xeb, 0x6b, 0xa1, 0x7d, 0x5d, 0x2d, 0x38, 0x6b, 0xb5, 0x10, 0x3c, 0x02, 0x77, 0xbc,
+	0x3c, 0x89, 0x96, 0x68, 0x78, 0xb2, 0x31, 0x65, 0x6a, 0x01, 0xdb, 0x05, 0x27, 0x8e, 0x96, 0x64,
+	0xd5, 0xe5, 0xb8, 0x0c, 0x3e, 0x01, 0x6f, 0xbc, 0x3c, 0x49, 0xcd, 0x70, 0x0c, 0xc0, 0xd5, 0x68,
+	0x85, 0x0e, 0x0e, 0x0e, 0xb6, 0xaa, 0x32, 0x39, 0x89, 0x96, 0xdc, 0x6c, 0xb1, 0xbf, 0x41, 0x5b,
+	0x2f, 0x6d, 0x87, 0x34, 0x3a, 0xab, 0xad, 0x97, 0xc1, 0x02, 0xdc, 0x73, 0x2d, 0xb4, 0xbc, 0xbe,
+	0x33, 0x26, 0x22, 0x11, 0x28, 0x2f, 0xc7, 0x9b, 0x81, 0x66, 0xec, 0x44, 0x92, 0x7c, 0x36, 0xc4,
+	0x2a, 0x8c, 0x03, 0xa2, 0xd0, 0x2a, 0x17, 0x53, 0x89, 0x53, 0xca, 0x4e, 0xb6, 0xa6, 0x28, 0xf8,
+	0xb5, 0x05, 0x3d, 0x2e, 0x43, 0x19, 0x67, 0xda, 0x0c, 0xa3, 0x54, 0xe7, 0x22, 0xd4, 0xa3, 0x28,
+	0xca, 0x65, 0x51, 0xd8, 0x20, 0x6c, 0x8a, 0x31, 0xc6, 0x85, 0x16, 0x7a, 0x51, 0x90, 0x33, 0x1e,
+	0xb7, 0x08, 0x43, 0x94, 0x4b, 0xd3, 0x9f, 0x1e, 0xc7, 0x25, 0xfa, 0x3d, 0x15, 0xc5, 0xab, 0x42,
+	0x46, 0xb6, 0x35, 0x4b, 0xc8, 0xf6, 0xc1, 0x0b, 0x45, 0x92, 0x8c, 0x73, 0x11, 0x4a, 0xdf, 0x1d,
+	0x3a, 0x8d, 0x17, 0xe1, 0xa8, 0x94, 0xf3, 0x5a, 0x85, 0xdd, 0x85, 0xae, 0x99, 0x6c, 0x7e, 0x77,
+	0xe8, 0x34, 0x02, 0x7c, 0x8c, 0x42, 0x6e, 0xf7, 0xd8, 0x3d, 0x70, 0x0b, 0x2d, 0xb3, 0xc2, 0xef,
+	0xad, 0x59, 0x24, 0x13, 0xe7, 0x5a, 0x66, 0xdc, 0x6c, 0x07, 0x3f, 0xb5, 0xc0, 0xab, 0xae, 0x41,
+	0x3e, 0x78, 0x91, 0xcc, 0x89, 0xb0, 0xc7, 0x2d, 0xb2, 0x23, 0x9d, 0xa8, 0x5b, 0xa6, 0x15, 0xa6,
+	0x8e, 0x5d, 0xa4, 0x21, 0x56, 0xa4, 0x25, 0x5c, 0x61, 0x1c, 0x07, 0x22, 0x9f, 0x16, 0x44, 0xd9,
+	0xe3, 0xb4, 0x46, 0xfd, 0x48, 0x26, 0x72, 0x2a, 0xb4, 0xa4, 0x61, 0xd4, 0xe7, 0x15, 0xc6, 0x9c,
+	0x47, 0x32, 0xd3, 0x33, 0x9a, 0x46, 0xdb, 0xdc, 0x80, 0x46, 0x94, 0x7b, 0xef, 0x8a, 0x72, 0xff,
+	0x9d, 0x51, 0xf6, 0xd6, 0xa2, 0x1c, 0xfc, 0xdc, 0x02, 0xaf, 0x22, 0xcf, 0x76, 0xa0, 0xad, 0x32,
+	0xcb, 0xb1, 0xad, 0xb2, 0xf7, 0xf2, 0xab, 0x7c, 0x72, 0x9a, 0x3e, 0x35, 0x59, 0x77, 0xde, 0x66,
+	0x9d, 0xc4, 0xa9, 0x61, 0xe7, 0x72, 0x5a, 0xa3, 0xaf, 0xaf, 0xe5, 0x8a, 0x78, 0x79, 0x1c, 0x97,
+	0x68, 0x41, 0x25, 0xd1, 0x85, 0x48, 0x16, 0xd2, 0xf2, 0xaa, 0x30, 0xde, 0x79, 0x45, 0x1b, 0x86,
+	0x9b, 0x01, 0xc8, 0x6e, 0x2e, 0x8b, 0x42, 0x4c, 0x25, 0xb1, 0xf3, 0x78, 0x09, 0xd1, 0xfa, 0x54,
+	0x14, 0x34, 0x5d, 0x3b, 0x1c, 0x97, 0xc1, 0x2f, 0x2d, 0x70, 0xa9, 0x22, 0x3e, 0xa0, 0x9a, 0x6f,
+	0x81, 0x47, 0xd5, 0x73, 0x2a, 0xe6, 0xd2, 0x86, 0xa1, 0x16, 0xa0, 0xbf, 0x5f, 0x15, 0x2a, 0x1d,
+	0x61, 0x3e, 0x6d, 0x9e, 0x4b, 0x8c, 0x7b, 0xa4, 0x88, 0x6d, 0xdf, 0x21, 0xd6, 0x15, 0xc6, 0xec,
+	0xe9, 0x65, 0xe3, 0x5b, 0xc1, 0xa2, 0xf5, 0x21, 0xd3, 0xdd, 0x1c, 0x32, 0x8d, 0xcf, 0x98, 0xde,
+	0xfa, 0x67, 0x8c, 0x0f, 0x3d, 0xbd, 0x3c, 0x49, 0x23, 0xb9, 0xa4, 0xe8, 0xb8, 0xbc, 0x84, 0xc1,
+	0xbf, 0xc1, 0x23, 0xca, 0xf4, 0x7a, 0xd7, 0x6d, 0xd2, 0xba, 0xbe, 0x4d, 0x82, 0x6f, 0x5a, 0x00,
+	0x4f, 0xe2, 0x44, 0xcb, 0xfc, 0x24, 0xbd, 0x54, 0x7f, 0x58, 0xac, 0x4a, 0x6e, 0x97, 0xb9, 0x9a,
+	0x53, 0xb0, 0x3a, 0xbc, 0x16, 0x54, 0xdc, 0xb4, 0x2a, 0x67, 0x81, 0x85, 0xc1, 0x63, 0xe8, 0x5c,
+	0x28, 0x4d, 0xe7, 0x43, 0x91, 0x46, 0x71, 0x84, 0x4d, 0x62, 0x07, 0x70, 0x25, 0xb8, 0x6e, 0xb2,
+	0x07, 0x0f, 0xa0, 0x8f, 0xa7, 0x89, 0xfe, 0x1d, 0x70, 0xaf, 0x94, 0x96, 0x25, 0xfb, 0x81, 0x65,
+	0x8f, 0xfb, 0xdc, 0xec, 0x04, 0xff, 0x05, 0x78, 0x82, 0xe9, 0x5b, 0xcc, 0xa5, 0xf9, 0xae, 0x4a,
+	0xc5, 0xdc, 0xdc, 0xe6, 0x71, 0x5a, 0xa3, 0x0c, 0x8f, 0x59, 0x7e, 0xb4, 0x0e, 0xbe, 0x6b, 0x41,
+	0xff, 0x49, 0xa3, 0xd2, 0xdf, 0x3a, 0xf4, 0x10, 0x3c, 0x61, 0x8d, 0x9a, 0x97, 0x71, 0x70, 0xf0,
+	0x67, 0x7b, 0x7b, 0x7d, 0x1d, 0xaf, 0x75, 0x30, 0x1c, 0xb9, 0xd4, 0x8b, 0xdc, 0x3e, 0x98, 0x1e,
+	0x2f, 0x21, 0x9a, 0xbf, 0x8a, 0xe5, 0x1b, 0x8a, 0x52, 0x9f, 0xd3, 0xda, 0x3e, 0x5f, 0x62, 0x92,
+	0x94, 0xd3, 0xa3, 0x84, 0xc1, 0xb7, 0x6d, 0x70, 0x46, 0x87, 0x27, 0xa8, 0x71, 0x25, 0xf3, 0x02,
+	0x3b, 0xd3, 0xf8, 0x55, 0x42, 0x2c, 0xd3, 0x44, 0xa4, 0xd3, 0x85, 0x98, 0x96, 0x9c, 0x2a, 0xcc,
+	0x1e, 0x80, 0x57, 0x36, 0xb0, 0xf1, 0xa3, 0xfe, 0x4c, 0x29, 0xe9, 0xf2, 0x5a, 0x03, 0xbf, 0x23,
+	0x16, 0xd9, 0x34, 0x17, 0x11, 0x79, 0x62, 0x1c, 0x6c, 0x48, 0xd8, 0x23, 0xd8, 0xc1, 0x29, 0x25,
+	0x2f, 0x44, 0x1e, 0xa3, 0xa0, 0xf0, 0xdd, 0x35, 0x9b, 0xe7, 0x76, 0x93, 0x6f, 0xa8, 0x99, 0x51,
+	0x34, 0xcf, 0x62, 0x1c, 0xc2, 0xdd, 0x72, 0x14, 0x19, 0x4c, 0x83, 0x50, 0x2d, 0xf2, 0x50, 0x56,
+	0x83, 0x90, 0x10, 0x32, 0x4e, 0xe2, 0x49, 0x2e, 0xf2, 0x15, 0xb5, 0x44, 0x9f, 0x97, 0x30, 0x38,
+	0x80, 0x7e, 0x79, 0xd3, 0xef, 0xce, 0xf0, 0x0a, 0x6e, 0x1c, 0xd9, 0x6a, 0xbf, 0xb0, 0x81, 0xdb,
+	0x08, 0xe9, 0xf6, 0x5a, 0x48, 0xab, 0x57, 0xb7, 0xbd, 0xf1, 0xea, 0x36, 0x7a, 0xd8, 0x59, 0xef,
+	0xe1, 0x7a, 0x26, 0x74, 0x9a, 0x33, 0x21, 0xf8, 0xa1, 0x05, 0x5b, 0xe5, 0xdd, 0xd4, 0x90, 0xf8,
+	0x21, 0xbc, 0xd6, 0x88, 0x25, 0xc4, 0x11, 0xa9, 0xde, 0xa4, 0xf6, 0xef, 0xd6, 0x16, 0x37, 0x60,
+	0x23, 0x2d, 0xce, 0x5b, 0x69, 0xb9, 0x0d, 0x10, 0xc9, 0x42, 0xe7, 0x8b, 0x50, 0xdb, 0x97, 0xb8,
+	0xcf, 0x1b, 0x12, 0x76, 0x00, 0x7d, 0xcb, 0xac, 0x4c, 0xd8, 0xcd, 0xf2, 0x2d, 0x5e, 0x0f, 0x09,
+	0xaf, 0xf4, 0x82, 0x33, 0x70, 0x3f, 0x5b, 0xc8, 0x7c, 0xf5, 0x61, 0xd3, 0xe3, 0x6b, 0x3c, 0x12,
+	0xa7, 0x97, 0xca, 0x12, 0xa8, 0x05, 0xf7, 0x1f, 0x43, 0xd7, 0x7c, 0x54, 0x33, 0x80, 0xee, 0xe9,
+	0x19, 0x7f, 0x31, 0x7a, 0xbe, 0xfb, 0x27, 0xb6, 0x03, 0xf0, 0xf4, 0xec, 0xe2, 0x98, 0x9f, 0x8e,
+	0x4e, 0x8f, 0x8e, 0x77, 0x5b, 0xcc, 0x03, 0xf7, 0x70, 0x34, 0x3e, 0x7a, 0xb6, 0xdb, 0x66, 0x03,
+	0xe8, 0xbd, 0x7a, 0xf9, 0x94, 0x8f, 0x3e, 0x3e, 0xde, 0x75, 0x0e, 0x87, 0x5f, 0xdc, 0x9e, 0xc6,
+	0x7a, 0xb6, 0x98, 0xec, 0x87, 0x6a, 0xfe, 0x50, 0xc8, 0x7c, 0xaa, 0x62, 0x65, 0x7e, 0x1f, 0x12,
+	0x95, 0x49, 0x97, 0xfe, 0xd5, 0xfe, 0xe7, 0xb7, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa6, 0x55, 0x5c,
+	0xda, 0xe9, 0x0e, 0x00, 0x00,
 }
@@ -5,6 +5,7 @@ import (
 
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	crypto "github.com/libp2p/go-libp2p-crypto"
 	"github.com/stretchr/testify/assert"
 )
@@ -91,3 +92,84 @@ func TestBlockSign(t *testing.T) {
 	signAssert.Nil(err)
 	signAssert.True(valid)
 }
+
+func TestBlockCoinbaseAccount(t *testing.T) {
+	block := NewBlock(nil, make([]*Tx, 0), 0)
+	h1 := block.calculateBlockHash()
+	block.SetCoinbaseAccount([]byte("coinbase"))
+	h2 := block.calculateBlockHash()
+
+	// the coinbase account is signed with the header
+	assert.NotEqual(t, h1, h2)
+}
+
+func TestBlockEventBloom(t *testing.T) {
+	block := NewBlock(nil, make([]*Tx, 0), 0)
+	hash := block.calculateBlockHash()
+
+	var empty, bloom Bloom
+	bloom.Add([]byte("event"))
+	assert.Nil(t, block.EventBloom())
+	block.SetEventBloom(&empty)
+	assert.Equal(t, &empty, block.EventBloom())
+	block.SetEventBloom(&bloom)
+	assert.Equal(t, &bloom, block.EventBloom())
+
+	// the bloom is not covered by the block hash
+	assert.Equal(t, hash, block.calculateBlockHash())
+
+	// the bloom of the executed events replaces the received one
+	other := bloom
+	other.Add([]byte("other"))
+	block.SetEventBloom(&other)
+	assert.Equal(t, &other, block.EventBloom())
+	block.ClearEventBloom()
+	assert.Nil(t, block.EventBloom())
+}
+
+func TestTxHashFields(t *testing.T) {
+	hash := func(body *TxBody) []byte {
+		return (&Tx{Body: body}).CalculateTxHash()
+	}
+	// the bytes of a field cannot be moved to the next one
+	assert.NotEqual(t, hash(&TxBody{Account: []byte("ab"), Recipient: []byte("c")}),
+		hash(&TxBody{Account: []byte("a"), Recipient: []byte("bc")}))
+	assert.NotEqual(t, hash(&TxBody{Sign: []byte("ab")}),
+		hash(&TxBody{Sign: []byte("a"), FeePayerSign: []byte("b")}))
+	assert.NotEqual(t, hash(&TxBody{MultiSig: &MultiSig{Threshold: 1, PubKeys: [][]byte{[]byte("ab")}}}),
+		hash(&TxBody{MultiSig: &MultiSig{Threshold: 1, PubKeys: [][]byte{[]byte("a"), []byte("b")}}}))
+}
+
+func TestTxFee(t *testing.T) {
+	body := &TxBody{Limit: 100, Price: 3}
+	fee, err := body.MaxFee()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(300), fee)
+	fee, err = body.GasFee(10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(30), fee)
+	fee, err = body.GasFee(1000)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(300), fee)
+
+	body = &TxBody{Limit: 1 << 32, Price: 1 << 32}
+	_, err = body.MaxFee()
+	assert.Equal(t, ErrFeeOverflow, err)
+	fee, err = body.GasFee(1 << 31)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1<<63), fee)
+}
+
+func TestTxRunsContract(t *testing.T) {
+	assert.False(t, (&TxBody{}).RunsContract())
+	assert.True(t, (&TxBody{Payload: []byte(`{"Name":"f"}`)}).RunsContract())
+	assert.False(t, (&TxBody{Type: TxType_UPGRADE, Payload: []byte("code")}).RunsContract())
+
+	transfers, _ := proto.Marshal(&Batch{Operations: []*BatchOperation{{Recipient: []byte("a"), Amount: 1}}})
+	assert.False(t, (&TxBody{Type: TxType_BATCH, Payload: transfers}).RunsContract())
+	calls, _ := proto.Marshal(&Batch{Operations: []*BatchOperation{
+		{Recipient: []byte("a"), Amount: 1},
+		{Recipient: []byte("b"), Payload: []byte(`{"Name":"f"}`)},
+	}})
+	assert.True(t, (&TxBody{Type: TxType_BATCH, Payload: calls}).RunsContract())
+}
@@ -0,0 +1,92 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	sha256 "github.com/minio/sha256-simd"
+)
+
+const (
+	// BloomByteLength is the size of a bloom filter in bytes
+	BloomByteLength = 256
+
+	bloomBitLength = BloomByteLength * 8
+	bloomHashCount = 3
+)
+
+// Bloom is a bloom filter over the contract addresses and the event names of
+// the events emitted in a block. It tells quickly that a block has no event
+// matched to a filter.
+type Bloom [BloomByteLength]byte
+
+// BytesToBloom converts b to a Bloom. It returns nil if b is not a bloom.
+func BytesToBloom(b []byte) *Bloom {
+	if len(b) != BloomByteLength {
+		return nil
+	}
+	var bloom Bloom
+	copy(bloom[:], b)
+	return &bloom
+}
+
+// Add adds data to the bloom filter.
+func (b *Bloom) Add(data []byte) {
+	for _, bit := range bloomBits(data) {
+		b[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// Test returns false if data is not added to the bloom filter. If true, data
+// may be added.
+func (b *Bloom) Test(data []byte) bool {
+	for _, bit := range bloomBits(data) {
+		if b[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// AddEvent adds the contract address and the name of ev to the bloom filter.
+func (b *Bloom) AddEvent(ev *Event) {
+	b.Add(ev.ContractAddress)
+	b.Add([]byte(ev.EventName))
+}
+
+// MatchFilter returns false if no event matched to filter is added to the
+// bloom filter.
+func (b *Bloom) MatchFilter(filter *FilterInfo) bool {
+	if len(filter.ContractAddress) > 0 && !b.Test(filter.ContractAddress) {
+		return false
+	}
+	if len(filter.EventName) > 0 && !b.Test([]byte(filter.EventName)) {
+		return false
+	}
+	return true
+}
+
+func bloomBits(data []byte) [bloomHashCount]uint {
+	var bits [bloomHashCount]uint
+	h := sha256.Sum256(data)
+	for i := range bits {
+		bits[i] = uint(binary.BigEndian.Uint16(h[i*2:])) % bloomBitLength
+	}
+	return bits
+}
+
+// MatchFilter returns true if ev is matched to filter.
+func (ev *Event) MatchFilter(filter *FilterInfo) bool {
+	if len(filter.ContractAddress) > 0 && !bytes.Equal(filter.ContractAddress, ev.ContractAddress) {
+		return false
+	}
+	if len(filter.EventName) > 0 && filter.EventName != ev.EventName {
+		return false
+	}
+	return true
+}
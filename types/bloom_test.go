@@ -0,0 +1,28 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloomFilter(t *testing.T) {
+	contract := []byte("contract1")
+	ev := &Event{ContractAddress: contract, EventName: "transfer"}
+
+	var bloom Bloom
+	assert.False(t, bloom.MatchFilter(&FilterInfo{EventName: "transfer"}))
+
+	bloom.AddEvent(ev)
+	assert.True(t, bloom.MatchFilter(&FilterInfo{}))
+	assert.True(t, bloom.MatchFilter(&FilterInfo{ContractAddress: contract}))
+	assert.True(t, bloom.MatchFilter(&FilterInfo{ContractAddress: contract, EventName: "transfer"}))
+	assert.False(t, bloom.MatchFilter(&FilterInfo{EventName: "approve"}))
+
+	decoded := BytesToBloom(bloom[:])
+	assert.Equal(t, bloom, *decoded)
+	assert.Nil(t, BytesToBloom([]byte{1, 2}))
+
+	assert.True(t, ev.MatchFilter(&FilterInfo{EventName: "transfer"}))
+	assert.False(t, ev.MatchFilter(&FilterInfo{ContractAddress: []byte("contract2")}))
+}
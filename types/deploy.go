@@ -0,0 +1,52 @@
+/**
+ *  @file
+ *  @copyright defined in aergo/LICENSE.txt
+ */
+
+package types
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// DeployPayloadVersion is the format of the payload of a deploy tx, which is
+// the first byte of the payload
+const DeployPayloadVersion byte = 1
+
+// deployHeaderSize is the size of the version and the length of the code
+const deployHeaderSize = 5
+
+// ErrInvalidDeployPayload is returned when the payload of a deploy tx is not
+// in the format of DeployPayloadVersion
+var ErrInvalidDeployPayload = errors.New("invalid deploy payload")
+
+// EncodeDeployPayload returns the payload of a deploy tx, which has the code
+// of a contract and the JSON array of the constructor arguments. The code is
+// the result of the compilation, the length of the bytecode followed by the
+// bytecode and the abi.
+//
+// The payload is the version byte, the length of the code as a little endian
+// uint32, the code and the arguments, which may be empty.
+func EncodeDeployPayload(code, args []byte) []byte {
+	payload := make([]byte, deployHeaderSize+len(code)+len(args))
+	payload[0] = DeployPayloadVersion
+	binary.LittleEndian.PutUint32(payload[1:], uint32(len(code)))
+	copy(payload[deployHeaderSize:], code)
+	copy(payload[deployHeaderSize+len(code):], args)
+	return payload
+}
+
+// DecodeDeployPayload splits the payload of a deploy tx into the code and the
+// constructor arguments.
+func DecodeDeployPayload(payload []byte) (code, args []byte, err error) {
+	if len(payload) < deployHeaderSize || payload[0] != DeployPayloadVersion {
+		return nil, nil, ErrInvalidDeployPayload
+	}
+	l := binary.LittleEndian.Uint32(payload[1:])
+	if uint64(l) > uint64(len(payload)-deployHeaderSize) {
+		return nil, nil, ErrInvalidDeployPayload
+	}
+	end := deployHeaderSize + int(l)
+	return payload[deployHeaderSize:end], payload[end:], nil
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeployPayload(t *testing.T) {
+	// the code is either a bytecode or a Lua source, and both are decoded
+	// with and without arguments
+	for _, body := range [][]byte{
+		append([]byte{0x1b, 'L', 'J', 0x01}, []byte("bytecode")...),
+		[]byte("function f() end"),
+	} {
+		code := make([]byte, 4, 4+len(body)+2)
+		binary.LittleEndian.PutUint32(code, uint32(len(body)))
+		code = append(append(code, body...), []byte("{}")...)
+
+		decoded, args, err := DecodeDeployPayload(EncodeDeployPayload(code, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, code, decoded)
+		assert.Empty(t, args)
+
+		decoded, args, err = DecodeDeployPayload(EncodeDeployPayload(code, []byte(`[1,"a"]`)))
+		assert.NoError(t, err)
+		assert.Equal(t, code, decoded)
+		assert.Equal(t, `[1,"a"]`, string(args))
+	}
+
+	payload := EncodeDeployPayload([]byte("code"), nil)
+	for _, invalid := range [][]byte{
+		nil,
+		payload[:4],
+		append([]byte{DeployPayloadVersion + 1}, payload[1:]...),
+		payload[:len(payload)-1],
+	} {
+		_, _, err := DecodeDeployPayload(invalid)
+		assert.Equal(t, ErrInvalidDeployPayload, err)
+	}
+}
@@ -2,9 +2,12 @@ package types
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"github.com/gogo/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
 	"github.com/mr-tron/base58/base58"
+	"strconv"
 	"strings"
 )
 
@@ -21,7 +24,24 @@ func NewReceiptFromBytes(b []byte) *Receipt {
 	r.ContractAddress = b[:20]
 	endIdx := bytes.IndexByte(b[20:], 0x00) + 20
 	r.Status = string(b[20:endIdx])
-	r.Ret = string(b[endIdx+1:])
+	ret := b[endIdx+1:]
+	// the gas used, the call trace, the events and the trace steps follow the
+	// result. a receipt written without them has none.
+	if gasIdx := bytes.IndexByte(ret, 0x00); gasIdx >= 0 {
+		if len(ret) >= gasIdx+9 {
+			r.GasUsed = binary.LittleEndian.Uint64(ret[gasIdx+1:])
+			if rest := ret[gasIdx+9:]; len(rest) > 0 {
+				var logs Receipt
+				if err := proto.Unmarshal(rest, &logs); err == nil {
+					r.CallTrace = logs.CallTrace
+					r.Events = logs.Events
+					r.Steps = logs.Steps
+				}
+			}
+		}
+		ret = ret[:gasIdx]
+	}
+	r.Ret = string(ret)
 	return r
 }
 
@@ -31,6 +51,14 @@ func (r Receipt) Bytes() []byte {
 	b.WriteString(r.Status)
 	b.WriteByte(0x00)
 	b.WriteString(r.Ret)
+	b.WriteByte(0x00)
+	var gas [8]byte
+	binary.LittleEndian.PutUint64(gas[:], r.GasUsed)
+	b.Write(gas[:])
+	if len(r.CallTrace) > 0 || len(r.Events) > 0 || len(r.Steps) > 0 {
+		logs, _ := proto.Marshal(&Receipt{CallTrace: r.CallTrace, Events: r.Events, Steps: r.Steps})
+		b.Write(logs)
+	}
 	return b.Bytes()
 }
 
@@ -46,6 +74,54 @@ func (r Receipt) MarshalJSON() ([]byte, error) {
 	b.WriteString(strings.Replace(r.Status, "\"", "'", -1))
 	b.WriteString(`","ret":"`)
 	b.WriteString(strings.Replace(r.Ret, "\"", "'", -1))
-	b.WriteString(`"}`)
+	b.WriteString(`","gasUsed":`)
+	b.WriteString(strconv.FormatUint(r.GasUsed, 10))
+	if len(r.CallTrace) > 0 {
+		trace, err := json.Marshal(r.CallTrace)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(`,"callTrace":`)
+		b.Write(trace)
+	}
+	if len(r.Events) > 0 {
+		events, err := json.Marshal(r.Events)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(`,"events":`)
+		b.Write(events)
+	}
+	if len(r.Steps) > 0 {
+		steps, err := json.Marshal(r.Steps)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(`,"steps":`)
+		b.Write(steps)
+	}
+	b.WriteString(`}`)
 	return b.Bytes(), nil
 }
+
+func (ev Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		ContractAddress string `json:"contractAddress"`
+		EventName       string `json:"eventName"`
+		JsonArgs        string `json:"jsonArgs"`
+		EventIdx        int32  `json:"eventIdx"`
+		TxHash          []byte `json:"txHash"`
+		BlockHash       []byte `json:"blockHash,omitempty"`
+		BlockNo         uint64 `json:"blockNo,omitempty"`
+		TxIndex         int32  `json:"txIndex"`
+	}{
+		ContractAddress: base58.Encode(ev.ContractAddress),
+		EventName:       ev.EventName,
+		JsonArgs:        ev.JsonArgs,
+		EventIdx:        ev.EventIdx,
+		TxHash:          ev.TxHash,
+		BlockHash:       ev.BlockHash,
+		BlockNo:         ev.BlockNo,
+		TxIndex:         ev.TxIndex,
+	})
+}
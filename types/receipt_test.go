@@ -0,0 +1,49 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiptBytes(t *testing.T) {
+	address := make([]byte, 20)
+	address[0] = 1
+
+	receipt := NewReceipt(address, "SUCCESS", `["ok"]`)
+	receipt.GasUsed = 1234
+	receipt.CallTrace = []*CallTrace{
+		{Caller: "caller", Contract: "callee", Function: "f", Depth: 1, Status: "SUCCESS", GasUsed: 100},
+	}
+	receipt.Events = []*Event{
+		{ContractAddress: address, EventName: "transfer", JsonArgs: `["a",1]`},
+	}
+	decoded := NewReceiptFromBytes(receipt.Bytes())
+	assert.Equal(t, address, decoded.ContractAddress)
+	assert.Equal(t, "SUCCESS", decoded.Status)
+	assert.Equal(t, `["ok"]`, decoded.Ret)
+	assert.Equal(t, uint64(1234), decoded.GasUsed)
+	assert.Len(t, decoded.CallTrace, 1)
+	assert.Equal(t, "callee", decoded.CallTrace[0].Contract)
+	assert.Equal(t, uint64(100), decoded.CallTrace[0].GasUsed)
+	assert.Len(t, decoded.Events, 1)
+	assert.Equal(t, "transfer", decoded.Events[0].EventName)
+	assert.Equal(t, `["a",1]`, decoded.Events[0].JsonArgs)
+	assert.Len(t, decoded.Steps, 0)
+
+	receipt.Steps = []*TraceStep{
+		{Op: "setItem", Contract: "callee", Key: "k", OldValue: `"a"`, Value: `"b"`, Gas: 101},
+	}
+	decoded = NewReceiptFromBytes(receipt.Bytes())
+	assert.Len(t, decoded.Events, 1)
+	assert.Len(t, decoded.Steps, 1)
+	assert.Equal(t, `"a"`, decoded.Steps[0].OldValue)
+	assert.Equal(t, uint64(101), decoded.Steps[0].Gas)
+
+	// a receipt written without the gas used
+	old := append(append(address, []byte("SUCCESS")...), 0x00)
+	old = append(old, []byte(`["ok"]`)...)
+	decoded = NewReceiptFromBytes(old)
+	assert.Equal(t, `["ok"]`, decoded.Ret)
+	assert.Equal(t, uint64(0), decoded.GasUsed)
+}
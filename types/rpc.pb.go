@@ -661,6 +661,355 @@ func (m *VerifyResult) GetError() VerifyStatus {
 	return VerifyStatus_VERIFY_STATUS_OK
 }
 
+// BalanceChange is the balance of an account changed by a tx. The address
+// is empty if it cannot be found from the tx and its receipt.
+type BalanceChange struct {
+	AccountID            []byte   `protobuf:"bytes,1,opt,name=accountID,proto3" json:"accountID,omitempty"`
+	Address              []byte   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Before               uint64   `protobuf:"varint,3,opt,name=before,proto3" json:"before,omitempty"`
+	After                uint64   `protobuf:"varint,4,opt,name=after,proto3" json:"after,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BalanceChange) Reset()         { *m = BalanceChange{} }
+func (m *BalanceChange) String() string { return proto.CompactTextString(m) }
+func (*BalanceChange) ProtoMessage()    {}
+func (*BalanceChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{12}
+}
+func (m *BalanceChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BalanceChange.Unmarshal(m, b)
+}
+func (m *BalanceChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BalanceChange.Marshal(b, m, deterministic)
+}
+func (m *BalanceChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalanceChange.Merge(m, src)
+}
+func (m *BalanceChange) XXX_Size() int {
+	return xxx_messageInfo_BalanceChange.Size(m)
+}
+func (m *BalanceChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalanceChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalanceChange proto.InternalMessageInfo
+
+func (m *BalanceChange) GetAccountID() []byte {
+	if m != nil {
+		return m.AccountID
+	}
+	return nil
+}
+
+func (m *BalanceChange) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *BalanceChange) GetBefore() uint64 {
+	if m != nil {
+		return m.Before
+	}
+	return 0
+}
+
+func (m *BalanceChange) GetAfter() uint64 {
+	if m != nil {
+		return m.After
+	}
+	return 0
+}
+
+// SimulateResult is the result of a tx run without committing it
+type SimulateResult struct {
+	Receipt *Receipt `protobuf:"bytes,1,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	// fee is the gas used by the tx times its gas price
+	Fee            uint64           `protobuf:"varint,2,opt,name=fee,proto3" json:"fee,omitempty"`
+	BalanceChanges []*BalanceChange `protobuf:"bytes,3,rep,name=balanceChanges,proto3" json:"balanceChanges,omitempty"`
+	// charged is the fee charged to the payer, which is the fee if the block
+	// has a coinbase account, otherwise 0
+	Charged              uint64   `protobuf:"varint,4,opt,name=charged,proto3" json:"charged,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SimulateResult) Reset()         { *m = SimulateResult{} }
+func (m *SimulateResult) String() string { return proto.CompactTextString(m) }
+func (*SimulateResult) ProtoMessage()    {}
+func (*SimulateResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{13}
+}
+func (m *SimulateResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SimulateResult.Unmarshal(m, b)
+}
+func (m *SimulateResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SimulateResult.Marshal(b, m, deterministic)
+}
+func (m *SimulateResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SimulateResult.Merge(m, src)
+}
+func (m *SimulateResult) XXX_Size() int {
+	return xxx_messageInfo_SimulateResult.Size(m)
+}
+func (m *SimulateResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_SimulateResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SimulateResult proto.InternalMessageInfo
+
+func (m *SimulateResult) GetReceipt() *Receipt {
+	if m != nil {
+		return m.Receipt
+	}
+	return nil
+}
+
+func (m *SimulateResult) GetFee() uint64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
+func (m *SimulateResult) GetBalanceChanges() []*BalanceChange {
+	if m != nil {
+		return m.BalanceChanges
+	}
+	return nil
+}
+
+func (m *SimulateResult) GetCharged() uint64 {
+	if m != nil {
+		return m.Charged
+	}
+	return 0
+}
+
+// ContractAddressRequest asks the address of a contract before it is
+// deployed. The address of a contract deployed by the contract of the account
+// with contract.deploy is made from the salt and the code hash, if the salt
+// is given. Otherwise it is made from the account and the nonce of the deploy
+// tx, whose nonce 0 is replaced by the next nonce of the account.
+type ContractAddressRequest struct {
+	Account              []byte   `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	Nonce                uint64   `protobuf:"varint,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Salt                 []byte   `protobuf:"bytes,3,opt,name=salt,proto3" json:"salt,omitempty"`
+	CodeHash             []byte   `protobuf:"bytes,4,opt,name=codeHash,proto3" json:"codeHash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ContractAddressRequest) Reset()         { *m = ContractAddressRequest{} }
+func (m *ContractAddressRequest) String() string { return proto.CompactTextString(m) }
+func (*ContractAddressRequest) ProtoMessage()    {}
+func (*ContractAddressRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{14}
+}
+func (m *ContractAddressRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContractAddressRequest.Unmarshal(m, b)
+}
+func (m *ContractAddressRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContractAddressRequest.Marshal(b, m, deterministic)
+}
+func (m *ContractAddressRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContractAddressRequest.Merge(m, src)
+}
+func (m *ContractAddressRequest) XXX_Size() int {
+	return xxx_messageInfo_ContractAddressRequest.Size(m)
+}
+func (m *ContractAddressRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContractAddressRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContractAddressRequest proto.InternalMessageInfo
+
+func (m *ContractAddressRequest) GetAccount() []byte {
+	if m != nil {
+		return m.Account
+	}
+	return nil
+}
+
+func (m *ContractAddressRequest) GetNonce() uint64 {
+	if m != nil {
+		return m.Nonce
+	}
+	return 0
+}
+
+func (m *ContractAddressRequest) GetSalt() []byte {
+	if m != nil {
+		return m.Salt
+	}
+	return nil
+}
+
+func (m *ContractAddressRequest) GetCodeHash() []byte {
+	if m != nil {
+		return m.CodeHash
+	}
+	return nil
+}
+
+// SourceVerification is the Lua source of a contract, which is compiled and
+// compared with the deployed code of the contract
+type SourceVerification struct {
+	ContractAddress      []byte   `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
+	Source               string   `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SourceVerification) Reset()         { *m = SourceVerification{} }
+func (m *SourceVerification) String() string { return proto.CompactTextString(m) }
+func (*SourceVerification) ProtoMessage()    {}
+func (*SourceVerification) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{15}
+}
+func (m *SourceVerification) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SourceVerification.Unmarshal(m, b)
+}
+func (m *SourceVerification) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SourceVerification.Marshal(b, m, deterministic)
+}
+func (m *SourceVerification) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SourceVerification.Merge(m, src)
+}
+func (m *SourceVerification) XXX_Size() int {
+	return xxx_messageInfo_SourceVerification.Size(m)
+}
+func (m *SourceVerification) XXX_DiscardUnknown() {
+	xxx_messageInfo_SourceVerification.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SourceVerification proto.InternalMessageInfo
+
+func (m *SourceVerification) GetContractAddress() []byte {
+	if m != nil {
+		return m.ContractAddress
+	}
+	return nil
+}
+
+func (m *SourceVerification) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+// TokenBalanceRequest asks the balance of an account in a token contract of
+// the token standard
+type TokenBalanceRequest struct {
+	Token                []byte   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Account              []byte   `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TokenBalanceRequest) Reset()         { *m = TokenBalanceRequest{} }
+func (m *TokenBalanceRequest) String() string { return proto.CompactTextString(m) }
+func (*TokenBalanceRequest) ProtoMessage()    {}
+func (*TokenBalanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{16}
+}
+func (m *TokenBalanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TokenBalanceRequest.Unmarshal(m, b)
+}
+func (m *TokenBalanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TokenBalanceRequest.Marshal(b, m, deterministic)
+}
+func (m *TokenBalanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TokenBalanceRequest.Merge(m, src)
+}
+func (m *TokenBalanceRequest) XXX_Size() int {
+	return xxx_messageInfo_TokenBalanceRequest.Size(m)
+}
+func (m *TokenBalanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TokenBalanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TokenBalanceRequest proto.InternalMessageInfo
+
+func (m *TokenBalanceRequest) GetToken() []byte {
+	if m != nil {
+		return m.Token
+	}
+	return nil
+}
+
+func (m *TokenBalanceRequest) GetAccount() []byte {
+	if m != nil {
+		return m.Account
+	}
+	return nil
+}
+
+// TokenBalance is the balance of an account in a token contract, which is the
+// decimal string of the amount in the smallest unit of the token
+type TokenBalance struct {
+	Token                []byte   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Account              []byte   `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	Balance              string   `protobuf:"bytes,3,opt,name=balance,proto3" json:"balance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TokenBalance) Reset()         { *m = TokenBalance{} }
+func (m *TokenBalance) String() string { return proto.CompactTextString(m) }
+func (*TokenBalance) ProtoMessage()    {}
+func (*TokenBalance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_77a6da22d6a3feb1, []int{17}
+}
+func (m *TokenBalance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TokenBalance.Unmarshal(m, b)
+}
+func (m *TokenBalance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TokenBalance.Marshal(b, m, deterministic)
+}
+func (m *TokenBalance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TokenBalance.Merge(m, src)
+}
+func (m *TokenBalance) XXX_Size() int {
+	return xxx_messageInfo_TokenBalance.Size(m)
+}
+func (m *TokenBalance) XXX_DiscardUnknown() {
+	xxx_messageInfo_TokenBalance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TokenBalance proto.InternalMessageInfo
+
+func (m *TokenBalance) GetToken() []byte {
+	if m != nil {
+		return m.Token
+	}
+	return nil
+}
+
+func (m *TokenBalance) GetAccount() []byte {
+	if m != nil {
+		return m.Account
+	}
+	return nil
+}
+
+func (m *TokenBalance) GetBalance() string {
+	if m != nil {
+		return m.Balance
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*BlockchainStatus)(nil), "types.BlockchainStatus")
 	proto.RegisterType((*Input)(nil), "types.Input")
@@ -674,6 +1023,12 @@ func init() {
 	proto.RegisterType((*CommitResult)(nil), "types.CommitResult")
 	proto.RegisterType((*CommitResultList)(nil), "types.CommitResultList")
 	proto.RegisterType((*VerifyResult)(nil), "types.VerifyResult")
+	proto.RegisterType((*BalanceChange)(nil), "types.BalanceChange")
+	proto.RegisterType((*SimulateResult)(nil), "types.SimulateResult")
+	proto.RegisterType((*ContractAddressRequest)(nil), "types.ContractAddressRequest")
+	proto.RegisterType((*SourceVerification)(nil), "types.SourceVerification")
+	proto.RegisterType((*TokenBalanceRequest)(nil), "types.TokenBalanceRequest")
+	proto.RegisterType((*TokenBalance)(nil), "types.TokenBalance")
 	proto.RegisterEnum("types.CommitStatus", CommitStatus_name, CommitStatus_value)
 	proto.RegisterEnum("types.VerifyStatus", VerifyStatus_name, VerifyStatus_value)
 }
@@ -710,6 +1065,14 @@ type AergoRPCServiceClient interface {
 	QueryContract(ctx context.Context, in *Query, opts ...grpc.CallOption) (*SingleBytes, error)
 	GetPeers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PeerList, error)
 	GetVotes(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*VoteList, error)
+	GetContractInfo(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*ContractInfo, error)
+	ListEvents(ctx context.Context, in *FilterInfo, opts ...grpc.CallOption) (*EventList, error)
+	ListEventStream(ctx context.Context, in *FilterInfo, opts ...grpc.CallOption) (AergoRPCService_ListEventStreamClient, error)
+	SimulateTX(ctx context.Context, in *Tx, opts ...grpc.CallOption) (*SimulateResult, error)
+	TraceTX(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*Receipt, error)
+	GetContractAddress(ctx context.Context, in *ContractAddressRequest, opts ...grpc.CallOption) (*SingleBytes, error)
+	VerifyContractSource(ctx context.Context, in *SourceVerification, opts ...grpc.CallOption) (*ABI, error)
+	GetTokenBalance(ctx context.Context, in *TokenBalanceRequest, opts ...grpc.CallOption) (*TokenBalance, error)
 }
 
 type aergoRPCServiceClient struct {
@@ -900,6 +1263,101 @@ func (c *aergoRPCServiceClient) GetVotes(ctx context.Context, in *SingleBytes, o
 	return out, nil
 }
 
+func (c *aergoRPCServiceClient) GetContractInfo(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*ContractInfo, error) {
+	out := new(ContractInfo)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetContractInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) ListEvents(ctx context.Context, in *FilterInfo, opts ...grpc.CallOption) (*EventList, error) {
+	out := new(EventList)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/ListEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) ListEventStream(ctx context.Context, in *FilterInfo, opts ...grpc.CallOption) (AergoRPCService_ListEventStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_AergoRPCService_serviceDesc.Streams[0], "/types.AergoRPCService/ListEventStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aergoRPCServiceListEventStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AergoRPCService_ListEventStreamClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type aergoRPCServiceListEventStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *aergoRPCServiceListEventStreamClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *aergoRPCServiceClient) SimulateTX(ctx context.Context, in *Tx, opts ...grpc.CallOption) (*SimulateResult, error) {
+	out := new(SimulateResult)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/SimulateTX", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) TraceTX(ctx context.Context, in *SingleBytes, opts ...grpc.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/TraceTX", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) GetContractAddress(ctx context.Context, in *ContractAddressRequest, opts ...grpc.CallOption) (*SingleBytes, error) {
+	out := new(SingleBytes)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetContractAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) VerifyContractSource(ctx context.Context, in *SourceVerification, opts ...grpc.CallOption) (*ABI, error) {
+	out := new(ABI)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/VerifyContractSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aergoRPCServiceClient) GetTokenBalance(ctx context.Context, in *TokenBalanceRequest, opts ...grpc.CallOption) (*TokenBalance, error) {
+	out := new(TokenBalance)
+	err := c.cc.Invoke(ctx, "/types.AergoRPCService/GetTokenBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AergoRPCServiceServer is the server API for AergoRPCService service.
 type AergoRPCServiceServer interface {
 	NodeState(context.Context, *SingleBytes) (*SingleBytes, error)
@@ -922,6 +1380,14 @@ type AergoRPCServiceServer interface {
 	QueryContract(context.Context, *Query) (*SingleBytes, error)
 	GetPeers(context.Context, *Empty) (*PeerList, error)
 	GetVotes(context.Context, *SingleBytes) (*VoteList, error)
+	GetContractInfo(context.Context, *SingleBytes) (*ContractInfo, error)
+	ListEvents(context.Context, *FilterInfo) (*EventList, error)
+	ListEventStream(*FilterInfo, AergoRPCService_ListEventStreamServer) error
+	SimulateTX(context.Context, *Tx) (*SimulateResult, error)
+	TraceTX(context.Context, *SingleBytes) (*Receipt, error)
+	GetContractAddress(context.Context, *ContractAddressRequest) (*SingleBytes, error)
+	VerifyContractSource(context.Context, *SourceVerification) (*ABI, error)
+	GetTokenBalance(context.Context, *TokenBalanceRequest) (*TokenBalance, error)
 }
 
 func RegisterAergoRPCServiceServer(s *grpc.Server, srv AergoRPCServiceServer) {
@@ -1288,6 +1754,153 @@ func _AergoRPCService_GetVotes_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AergoRPCService_GetContractInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SingleBytes)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).GetContractInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/GetContractInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).GetContractInfo(ctx, req.(*SingleBytes))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_ListEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FilterInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).ListEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/ListEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).ListEvents(ctx, req.(*FilterInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_ListEventStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FilterInfo)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AergoRPCServiceServer).ListEventStream(m, &aergoRPCServiceListEventStreamServer{stream})
+}
+
+type AergoRPCService_ListEventStreamServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type aergoRPCServiceListEventStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *aergoRPCServiceListEventStreamServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AergoRPCService_SimulateTX_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Tx)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).SimulateTX(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/SimulateTX",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).SimulateTX(ctx, req.(*Tx))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_TraceTX_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SingleBytes)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).TraceTX(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/TraceTX",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).TraceTX(ctx, req.(*SingleBytes))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_GetContractAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContractAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).GetContractAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/GetContractAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).GetContractAddress(ctx, req.(*ContractAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_VerifyContractSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SourceVerification)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).VerifyContractSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/VerifyContractSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).VerifyContractSource(ctx, req.(*SourceVerification))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AergoRPCService_GetTokenBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AergoRPCServiceServer).GetTokenBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.AergoRPCService/GetTokenBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AergoRPCServiceServer).GetTokenBalance(ctx, req.(*TokenBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _AergoRPCService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "types.AergoRPCService",
 	HandlerType: (*AergoRPCServiceServer)(nil),
@@ -1372,78 +1985,135 @@ var _AergoRPCService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetVotes",
 			Handler:    _AergoRPCService_GetVotes_Handler,
 		},
+		{
+			MethodName: "GetContractInfo",
+			Handler:    _AergoRPCService_GetContractInfo_Handler,
+		},
+		{
+			MethodName: "ListEvents",
+			Handler:    _AergoRPCService_ListEvents_Handler,
+		},
+		{
+			MethodName: "SimulateTX",
+			Handler:    _AergoRPCService_SimulateTX_Handler,
+		},
+		{
+			MethodName: "TraceTX",
+			Handler:    _AergoRPCService_TraceTX_Handler,
+		},
+		{
+			MethodName: "GetContractAddress",
+			Handler:    _AergoRPCService_GetContractAddress_Handler,
+		},
+		{
+			MethodName: "VerifyContractSource",
+			Handler:    _AergoRPCService_VerifyContractSource_Handler,
+		},
+		{
+			MethodName: "GetTokenBalance",
+			Handler:    _AergoRPCService_GetTokenBalance_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListEventStream",
+			Handler:       _AergoRPCService_ListEventStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc.proto",
 }
 
 func init() { proto.RegisterFile("rpc.proto", fileDescriptor_77a6da22d6a3feb1) }
 
 var fileDescriptor_77a6da22d6a3feb1 = []byte{
-	// 1032 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0x6f, 0x6f, 0xe2, 0x46,
-	0x13, 0xc7, 0x24, 0x26, 0xc9, 0x10, 0x0e, 0xdf, 0x5e, 0x9e, 0x1c, 0x0f, 0xad, 0x72, 0xc8, 0xad,
-	0x2a, 0x9a, 0xb6, 0x49, 0x8f, 0xeb, 0xa9, 0x2f, 0x2b, 0xc3, 0x91, 0xc4, 0x2a, 0x81, 0x74, 0xed,
-	0xa4, 0x5c, 0xfb, 0xc2, 0x72, 0xcc, 0x02, 0xd6, 0x81, 0x6d, 0x79, 0x97, 0x28, 0xf4, 0x03, 0xf5,
-	0x13, 0xf6, 0x03, 0x54, 0xde, 0x5d, 0x83, 0x1d, 0x39, 0x2f, 0xae, 0xaf, 0xd8, 0x19, 0xff, 0x66,
-	0x76, 0xfe, 0xfc, 0x66, 0x16, 0x38, 0x88, 0x23, 0xef, 0x2c, 0x8a, 0x43, 0x16, 0x22, 0x95, 0xad,
-	0x23, 0x42, 0x9b, 0x6f, 0x66, 0x61, 0x38, 0x5b, 0x90, 0x73, 0xae, 0xbc, 0x5f, 0x4d, 0xcf, 0x99,
-	0xbf, 0x24, 0x94, 0xb9, 0xcb, 0x48, 0xe0, 0x9a, 0xda, 0xfd, 0x22, 0xf4, 0x3e, 0x79, 0x73, 0xd7,
-	0x0f, 0xa4, 0xa6, 0xe6, 0x7a, 0x5e, 0xb8, 0x0a, 0x98, 0x14, 0x21, 0x08, 0x27, 0x44, 0x9c, 0xf5,
-	0x3f, 0x41, 0xeb, 0x6e, 0xe0, 0x16, 0x73, 0xd9, 0x8a, 0xa2, 0x6f, 0xa0, 0x7e, 0x4f, 0x28, 0x73,
-	0xb8, 0x1f, 0x67, 0xee, 0xd2, 0x79, 0x43, 0x69, 0x29, 0xed, 0x43, 0x5c, 0x4b, 0xd4, 0x1c, 0x7e,
-	0xe5, 0xd2, 0x39, 0x7a, 0x03, 0x55, 0x8e, 0x9b, 0x13, 0x7f, 0x36, 0x67, 0x8d, 0x72, 0x4b, 0x69,
-	0xef, 0x62, 0x48, 0x54, 0x57, 0x5c, 0xa3, 0x7b, 0xa0, 0x9a, 0x41, 0xb4, 0x62, 0x08, 0xc1, 0x6e,
-	0xc6, 0x0d, 0x3f, 0xa3, 0x06, 0xec, 0xb9, 0x93, 0x49, 0x4c, 0x28, 0x6d, 0x94, 0x5b, 0x3b, 0xed,
-	0x43, 0x9c, 0x8a, 0xe8, 0x08, 0xd4, 0x07, 0x77, 0xb1, 0x22, 0x8d, 0x1d, 0x0e, 0x17, 0x02, 0x3a,
-	0x86, 0x0a, 0xf5, 0x62, 0x3f, 0x62, 0x8d, 0x5d, 0xae, 0x96, 0x92, 0x3e, 0x85, 0xca, 0x68, 0xc5,
-	0x92, 0x5b, 0x8e, 0x40, 0xf5, 0x83, 0x09, 0x79, 0xe4, 0xd7, 0xd4, 0xb0, 0x10, 0xf2, 0xf7, 0x28,
-	0xff, 0xfd, 0x9e, 0x3d, 0x50, 0xfb, 0xcb, 0x88, 0xad, 0xf5, 0xaf, 0xa0, 0x6a, 0xf9, 0xc1, 0x6c,
-	0x41, 0xba, 0x6b, 0x46, 0x32, 0x5e, 0x94, 0x8c, 0x17, 0xdd, 0x86, 0xfd, 0x1b, 0x12, 0xd3, 0x30,
-	0x70, 0x17, 0xe8, 0x04, 0x20, 0x72, 0x29, 0x8d, 0xe6, 0xb1, 0x4b, 0x05, 0xec, 0x00, 0x67, 0x34,
-	0xa8, 0x0d, 0x7b, 0xb2, 0x41, 0x3c, 0xc2, 0x6a, 0xe7, 0xc5, 0x19, 0x6f, 0xf5, 0x99, 0x21, 0xb4,
-	0x38, 0xfd, 0xac, 0x0f, 0x12, 0xaf, 0x24, 0x1e, 0xf8, 0x94, 0xa1, 0x36, 0xa8, 0x11, 0x21, 0x31,
-	0x6d, 0x28, 0xad, 0x9d, 0x76, 0xb5, 0x83, 0xa4, 0x4d, 0xf2, 0xdd, 0x10, 0x09, 0x62, 0x01, 0xe0,
-	0x19, 0x31, 0x97, 0x11, 0x51, 0x68, 0x15, 0x4b, 0x49, 0x7f, 0x00, 0x48, 0x3c, 0xdd, 0xb8, 0xb1,
-	0xbb, 0xa4, 0x85, 0x3d, 0x3a, 0x86, 0x4a, 0xae, 0xb9, 0x52, 0x4a, 0xb0, 0xd4, 0xff, 0x4b, 0x14,
-	0xae, 0x86, 0xf9, 0x39, 0xc1, 0x86, 0xd3, 0x29, 0x25, 0xa2, 0x6e, 0x35, 0x2c, 0x25, 0xa4, 0xc1,
-	0x8e, 0x4b, 0xbd, 0x86, 0xda, 0x52, 0xda, 0xfb, 0x38, 0x39, 0xea, 0x3f, 0x43, 0x5d, 0x90, 0x88,
-	0xb8, 0x13, 0x99, 0xcc, 0xd7, 0x50, 0xe1, 0x6c, 0x4b, 0xb3, 0x39, 0x94, 0xd9, 0x70, 0x1c, 0x96,
-	0xdf, 0xf4, 0x6b, 0x38, 0xec, 0x85, 0xcb, 0xa5, 0xcf, 0x30, 0xa1, 0xab, 0x45, 0x31, 0xad, 0xbe,
-	0x05, 0x95, 0xc4, 0x71, 0x18, 0xf3, 0x88, 0x5f, 0x74, 0x5e, 0x49, 0x47, 0xc2, 0x4e, 0x10, 0x1c,
-	0x0b, 0x84, 0x6e, 0x80, 0x96, 0x75, 0xc7, 0x03, 0xf9, 0x01, 0xf6, 0x62, 0x2e, 0xa5, 0x91, 0xe4,
-	0x1d, 0x08, 0x24, 0x4e, 0x31, 0xba, 0x0d, 0x87, 0x77, 0x24, 0xf6, 0xa7, 0x6b, 0x19, 0xd1, 0xff,
-	0xa1, 0xcc, 0x04, 0xff, 0xaa, 0x9d, 0x03, 0x69, 0x69, 0x3f, 0xe2, 0x32, 0x7b, 0x7c, 0x2e, 0x30,
-	0x61, 0x9e, 0x0b, 0xec, 0xf4, 0x6f, 0x25, 0x4d, 0x54, 0x4e, 0xe4, 0x01, 0xa8, 0xf6, 0xd8, 0x19,
-	0xfd, 0xaa, 0x95, 0xd0, 0x11, 0x68, 0xf6, 0xd8, 0x19, 0x8e, 0x86, 0xbd, 0xbe, 0x63, 0x8f, 0x46,
-	0xce, 0x60, 0xf4, 0xbb, 0xa6, 0xa0, 0xff, 0xc1, 0x4b, 0x7b, 0xec, 0x18, 0x03, 0xdc, 0x37, 0x3e,
-	0x7c, 0x74, 0xfa, 0x63, 0xd3, 0xb2, 0x2d, 0xad, 0x8c, 0x5e, 0x41, 0xdd, 0x1e, 0x3b, 0xe6, 0xf0,
-	0xce, 0x18, 0x98, 0x1f, 0x9c, 0x2b, 0xc3, 0xba, 0xd2, 0x76, 0x24, 0x36, 0x55, 0x5e, 0x8c, 0xf0,
-	0xb5, 0x61, 0x6b, 0xbb, 0xe8, 0x0b, 0x78, 0xcd, 0xd5, 0xd6, 0xed, 0xc5, 0x85, 0xd9, 0x33, 0xfb,
-	0x43, 0xdb, 0xe9, 0x1a, 0x03, 0x63, 0xd8, 0xeb, 0x6b, 0xea, 0xc6, 0xc6, 0xee, 0xe3, 0xa1, 0x31,
-	0x70, 0xfa, 0x18, 0x8f, 0xb0, 0x56, 0x39, 0x9d, 0xa6, 0xe9, 0xcb, 0x38, 0x8f, 0x40, 0xbb, 0xeb,
-	0x63, 0xf3, 0xe2, 0xa3, 0x63, 0xd9, 0x86, 0x7d, 0x6b, 0x89, 0x90, 0x5b, 0xf0, 0x65, 0x5e, 0x6b,
-	0x99, 0x97, 0x43, 0x67, 0x38, 0xb2, 0x9d, 0x6b, 0xc3, 0xee, 0x5d, 0x69, 0x0a, 0x3a, 0x81, 0x66,
-	0x1e, 0x91, 0x0b, 0xb9, 0xdc, 0xf9, 0x67, 0x0f, 0xea, 0x06, 0x89, 0x67, 0x21, 0xbe, 0xe9, 0x59,
-	0x24, 0x7e, 0xf0, 0x3d, 0x82, 0xde, 0xc3, 0xc1, 0x30, 0x9c, 0x90, 0xe4, 0x66, 0x82, 0x52, 0xf6,
-	0x67, 0x06, 0xb3, 0x59, 0xa0, 0xd3, 0x4b, 0xe8, 0x3d, 0xc0, 0x76, 0xe1, 0xa1, 0x94, 0x67, 0x7c,
-	0xb2, 0x9b, 0xaf, 0xb3, 0xac, 0xcb, 0x6c, 0x44, 0xbd, 0x84, 0x7e, 0x01, 0x2d, 0xe1, 0x47, 0x86,
-	0xb7, 0x14, 0xbd, 0x94, 0xf0, 0xed, 0x10, 0x35, 0x8f, 0xb3, 0x1e, 0xb6, 0xfc, 0xd6, 0x4b, 0xe8,
-	0x0c, 0xf6, 0x2f, 0x89, 0xb0, 0x2f, 0x8c, 0x36, 0xc7, 0x78, 0xbd, 0x94, 0x8c, 0xf7, 0x25, 0x61,
-	0xf6, 0xb8, 0x10, 0xbc, 0xa5, 0x96, 0x5e, 0x42, 0x3f, 0x01, 0xa4, 0x9e, 0x9f, 0x81, 0x6b, 0x1b,
-	0xb8, 0x19, 0xa4, 0xfe, 0x3b, 0xdc, 0x0a, 0x13, 0x8f, 0xf8, 0x11, 0x2b, 0xb4, 0x4a, 0xb7, 0x90,
-	0xc4, 0xe8, 0x25, 0x74, 0x0a, 0x95, 0x4b, 0xc2, 0x8c, 0xae, 0x59, 0x88, 0x87, 0x74, 0x6b, 0x75,
-	0x4d, 0x81, 0xb5, 0x48, 0x30, 0xb1, 0xc7, 0x68, 0x1b, 0x6c, 0xb3, 0x68, 0x98, 0x78, 0x06, 0xfb,
-	0x42, 0x63, 0x8f, 0x51, 0x6d, 0x83, 0x4e, 0x0a, 0xb7, 0x69, 0xc9, 0xd3, 0x41, 0xdd, 0x54, 0xf4,
-	0xf9, 0xfe, 0xa7, 0x15, 0xe5, 0x08, 0x9e, 0x71, 0xad, 0x17, 0x13, 0x97, 0x11, 0xb9, 0x56, 0x51,
-	0x7d, 0xb3, 0x32, 0xc5, 0xa2, 0x6e, 0x3e, 0xd9, 0xbb, 0x7a, 0x09, 0xbd, 0x85, 0x6a, 0x92, 0xb1,
-	0x90, 0xe9, 0x13, 0xba, 0xa0, 0x3c, 0x5c, 0x86, 0xf5, 0x23, 0x54, 0x07, 0xa1, 0xf7, 0xe9, 0x33,
-	0x2e, 0xe9, 0x40, 0xed, 0x36, 0x58, 0x7c, 0x9e, 0x4d, 0x0b, 0x2a, 0x96, 0x3f, 0x0b, 0xf2, 0xe5,
-	0xcd, 0xd1, 0xe2, 0x7b, 0xd8, 0x17, 0xb3, 0x59, 0xdc, 0x82, 0xec, 0xda, 0xd2, 0x4b, 0xe8, 0x1d,
-	0xd4, 0x7e, 0x5b, 0x91, 0x78, 0xdd, 0x0b, 0x03, 0x16, 0xbb, 0x1e, 0xdb, 0xa4, 0xca, 0xb5, 0xcf,
-	0xcc, 0xd2, 0x77, 0xbc, 0x03, 0x37, 0xfc, 0x91, 0xc9, 0x97, 0xa6, 0x9e, 0x79, 0x8d, 0x64, 0x5d,
-	0xde, 0x72, 0xf0, 0x5d, 0x98, 0xbc, 0x99, 0x45, 0xed, 0x4a, 0x4d, 0x12, 0x84, 0x30, 0xe9, 0xb6,
-	0xfe, 0x38, 0x99, 0xf9, 0x6c, 0xbe, 0xba, 0x3f, 0xf3, 0xc2, 0xe5, 0xb9, 0x9b, 0x2c, 0x00, 0x3f,
-	0x14, 0xbf, 0xe7, 0x1c, 0x7c, 0x5f, 0xe1, 0xff, 0x62, 0xde, 0xfd, 0x1b, 0x00, 0x00, 0xff, 0xff,
-	0xaf, 0x63, 0x82, 0xbe, 0x27, 0x09, 0x00, 0x00,
+	// 1402 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x57, 0x6d, 0x73, 0xda, 0xc6,
+	0x13, 0x47, 0xb6, 0xc1, 0x66, 0x31, 0x46, 0x39, 0x3b, 0x0e, 0xe1, 0x9f, 0x7f, 0xc2, 0xa8, 0x9d,
+	0x0e, 0x4d, 0x5b, 0x3b, 0x71, 0x9a, 0xf6, 0x4d, 0x66, 0x32, 0x32, 0xc6, 0xb6, 0xa6, 0x04, 0xdc,
+	0x93, 0xe2, 0x92, 0xf6, 0x85, 0xe6, 0x10, 0x07, 0x68, 0x02, 0x12, 0x95, 0x0e, 0x8f, 0xdd, 0xcf,
+	0xd3, 0xe9, 0x27, 0xea, 0x07, 0xea, 0xe8, 0x1e, 0x40, 0x22, 0xb8, 0x33, 0xe9, 0x2b, 0x6e, 0xf7,
+	0x7e, 0xbb, 0xb7, 0xcf, 0x2b, 0xa0, 0x18, 0xcd, 0xbc, 0xa3, 0x59, 0x14, 0xb2, 0x10, 0xe5, 0xd9,
+	0xdd, 0x8c, 0xc6, 0xb5, 0x67, 0xa3, 0x30, 0x1c, 0x4d, 0xe8, 0x31, 0x67, 0xf6, 0xe7, 0xc3, 0x63,
+	0xe6, 0x4f, 0x69, 0xcc, 0xc8, 0x74, 0x26, 0x70, 0x35, 0xbd, 0x3f, 0x09, 0xbd, 0x8f, 0xde, 0x98,
+	0xf8, 0x81, 0xe4, 0x94, 0x89, 0xe7, 0x85, 0xf3, 0x80, 0x49, 0x12, 0x82, 0x70, 0x40, 0xc5, 0xd9,
+	0xf8, 0x0d, 0xf4, 0xd3, 0x05, 0xdc, 0x66, 0x84, 0xcd, 0x63, 0xf4, 0x15, 0x54, 0xfa, 0x34, 0x66,
+	0x2e, 0xd7, 0xe3, 0x8e, 0x49, 0x3c, 0xae, 0x6a, 0x75, 0xad, 0xb1, 0x8b, 0xcb, 0x09, 0x9b, 0xc3,
+	0x2f, 0x49, 0x3c, 0x46, 0xcf, 0xa0, 0xc4, 0x71, 0x63, 0xea, 0x8f, 0xc6, 0xac, 0xba, 0x51, 0xd7,
+	0x1a, 0x5b, 0x18, 0x12, 0xd6, 0x25, 0xe7, 0x18, 0x1e, 0xe4, 0xad, 0x60, 0x36, 0x67, 0x08, 0xc1,
+	0x56, 0x4a, 0x0d, 0x3f, 0xa3, 0x2a, 0x6c, 0x93, 0xc1, 0x20, 0xa2, 0x71, 0x5c, 0xdd, 0xa8, 0x6f,
+	0x36, 0x76, 0xb1, 0x22, 0xd1, 0x01, 0xe4, 0x6f, 0xc8, 0x64, 0x4e, 0xab, 0x9b, 0x1c, 0x2e, 0x08,
+	0x74, 0x08, 0x85, 0xd8, 0x8b, 0xfc, 0x19, 0xab, 0x6e, 0x71, 0xb6, 0xa4, 0x8c, 0x21, 0x14, 0xba,
+	0x73, 0x96, 0xbc, 0x72, 0x00, 0x79, 0x3f, 0x18, 0xd0, 0x5b, 0xfe, 0x4c, 0x19, 0x0b, 0x22, 0xfb,
+	0x8e, 0xf6, 0xdf, 0xdf, 0xd9, 0x86, 0x7c, 0x6b, 0x3a, 0x63, 0x77, 0xc6, 0x17, 0x50, 0xb2, 0xfd,
+	0x60, 0x34, 0xa1, 0xa7, 0x77, 0x8c, 0xa6, 0xb4, 0x68, 0x29, 0x2d, 0x86, 0x03, 0x3b, 0x57, 0x34,
+	0x8a, 0xc3, 0x80, 0x4c, 0xd0, 0x53, 0x80, 0x19, 0x89, 0xe3, 0xd9, 0x38, 0x22, 0xb1, 0x80, 0x15,
+	0x71, 0x8a, 0x83, 0x1a, 0xb0, 0x2d, 0x13, 0xc4, 0x2d, 0x2c, 0x9d, 0xec, 0x1d, 0xf1, 0x54, 0x1f,
+	0x99, 0x82, 0x8b, 0xd5, 0xb5, 0xd1, 0x4e, 0xb4, 0xd2, 0xa8, 0xed, 0xc7, 0x0c, 0x35, 0x20, 0x3f,
+	0xa3, 0x34, 0x8a, 0xab, 0x5a, 0x7d, 0xb3, 0x51, 0x3a, 0x41, 0x52, 0x26, 0xb9, 0x37, 0x85, 0x83,
+	0x58, 0x00, 0xb8, 0x47, 0x8c, 0x30, 0x2a, 0x02, 0x9d, 0xc7, 0x92, 0x32, 0x6e, 0x00, 0x12, 0x4d,
+	0x57, 0x24, 0x22, 0xd3, 0x78, 0x6d, 0x8e, 0x0e, 0xa1, 0x90, 0x49, 0xae, 0xa4, 0x12, 0x6c, 0xec,
+	0xff, 0x21, 0x02, 0x57, 0xc6, 0xfc, 0x9c, 0x60, 0xc3, 0xe1, 0x30, 0xa6, 0x22, 0x6e, 0x65, 0x2c,
+	0x29, 0xa4, 0xc3, 0x26, 0x89, 0xbd, 0x6a, 0xbe, 0xae, 0x35, 0x76, 0x70, 0x72, 0x34, 0x7e, 0x84,
+	0x8a, 0x28, 0x22, 0x4a, 0x06, 0xd2, 0x99, 0x2f, 0xa1, 0xc0, 0xab, 0x4d, 0x79, 0xb3, 0x2b, 0xbd,
+	0xe1, 0x38, 0x2c, 0xef, 0x8c, 0x77, 0xb0, 0xdb, 0x0c, 0xa7, 0x53, 0x9f, 0x61, 0x1a, 0xcf, 0x27,
+	0xeb, 0xcb, 0xea, 0x6b, 0xc8, 0xd3, 0x28, 0x0a, 0x23, 0x6e, 0xf1, 0xde, 0xc9, 0xbe, 0x54, 0x24,
+	0xe4, 0x44, 0x81, 0x63, 0x81, 0x30, 0x4c, 0xd0, 0xd3, 0xea, 0xb8, 0x21, 0xdf, 0xc1, 0x76, 0xc4,
+	0x29, 0x65, 0x49, 0x56, 0x81, 0x40, 0x62, 0x85, 0x31, 0x1c, 0xd8, 0xbd, 0xa6, 0x91, 0x3f, 0xbc,
+	0x93, 0x16, 0x3d, 0x86, 0x0d, 0x26, 0xea, 0xaf, 0x74, 0x52, 0x94, 0x92, 0xce, 0x2d, 0xde, 0x60,
+	0xb7, 0xf7, 0x19, 0x26, 0xc4, 0xb3, 0x86, 0xcd, 0xa1, 0x7c, 0x4a, 0x26, 0x24, 0xf0, 0x68, 0x73,
+	0x4c, 0x82, 0x11, 0x45, 0x4f, 0xa0, 0x28, 0x4b, 0xc0, 0x3a, 0x93, 0xde, 0x2e, 0x19, 0xff, 0x52,
+	0xe1, 0x87, 0x50, 0xe8, 0xd3, 0x61, 0x18, 0x89, 0x4c, 0x6d, 0x61, 0x49, 0x25, 0x35, 0x4b, 0x86,
+	0x8c, 0x46, 0x3c, 0x55, 0x5b, 0x58, 0x10, 0xc6, 0x9f, 0x1a, 0xec, 0xd9, 0xfe, 0x74, 0x3e, 0x21,
+	0x8c, 0x4a, 0x7f, 0x1a, 0x49, 0x38, 0x3c, 0x9a, 0x74, 0x83, 0x96, 0x29, 0x4d, 0x2c, 0xb8, 0x58,
+	0x5d, 0x27, 0x69, 0x1e, 0x52, 0x2a, 0xeb, 0x24, 0x39, 0xa2, 0x37, 0xb0, 0xd7, 0x4f, 0x7b, 0x11,
+	0x57, 0x37, 0x79, 0x44, 0x0f, 0x54, 0x6e, 0xd3, 0x97, 0x78, 0x05, 0x9b, 0x38, 0xe5, 0x8d, 0x49,
+	0x34, 0xa2, 0x03, 0x69, 0xa4, 0x22, 0x8d, 0x5b, 0x38, 0x6c, 0x86, 0x01, 0x8b, 0x88, 0xc7, 0x54,
+	0xa1, 0xd3, 0xdf, 0xe7, 0x34, 0x66, 0x3c, 0x10, 0xb2, 0x91, 0x34, 0x19, 0x08, 0x41, 0x26, 0x0e,
+	0x07, 0x61, 0xe0, 0x29, 0xfb, 0x04, 0xc1, 0xcb, 0x98, 0x4c, 0x98, 0xec, 0x7f, 0x7e, 0x46, 0x35,
+	0xd8, 0xf1, 0xc2, 0x01, 0x4d, 0x06, 0x9c, 0x1c, 0x00, 0x0b, 0xda, 0xb8, 0x06, 0x64, 0x87, 0xf3,
+	0xc8, 0xa3, 0x3c, 0x69, 0xbe, 0x47, 0x98, 0x1f, 0x06, 0xa8, 0x01, 0x15, 0x2f, 0x6b, 0x8f, 0x7c,
+	0x7d, 0x95, 0xcd, 0x1b, 0x91, 0xcb, 0x73, 0x33, 0x8a, 0x58, 0x52, 0x46, 0x0b, 0xf6, 0x9d, 0xf0,
+	0x23, 0x0d, 0x64, 0x44, 0x94, 0x3b, 0x07, 0x90, 0x67, 0x09, 0x5b, 0x4d, 0x16, 0x4e, 0xa4, 0x9d,
+	0xdc, 0xc8, 0x38, 0x69, 0xf4, 0x60, 0x37, 0xad, 0xe6, 0x73, 0xe5, 0x93, 0x1b, 0x99, 0x04, 0x1e,
+	0x91, 0x22, 0x56, 0xe4, 0xf3, 0xbf, 0x34, 0xd5, 0x79, 0x72, 0x45, 0x14, 0x21, 0xef, 0xf4, 0xdc,
+	0xee, 0x4f, 0x7a, 0x0e, 0x1d, 0x80, 0xee, 0xf4, 0xdc, 0x4e, 0xb7, 0xd3, 0x6c, 0xb9, 0x4e, 0xb7,
+	0xeb, 0xb6, 0xbb, 0xbf, 0xe8, 0x1a, 0x7a, 0x08, 0x0f, 0x9c, 0x9e, 0x6b, 0xb6, 0x71, 0xcb, 0x3c,
+	0xfb, 0xe0, 0xb6, 0x7a, 0x96, 0xed, 0xd8, 0xfa, 0x06, 0xda, 0x87, 0x8a, 0xd3, 0x73, 0xad, 0xce,
+	0xb5, 0xd9, 0xb6, 0xce, 0xdc, 0x4b, 0xd3, 0xbe, 0xd4, 0x37, 0x25, 0x56, 0x31, 0xcf, 0xbb, 0xf8,
+	0x9d, 0xe9, 0xe8, 0x5b, 0xe8, 0x7f, 0xf0, 0x88, 0xb3, 0xed, 0xf7, 0xe7, 0xe7, 0x56, 0xd3, 0x6a,
+	0x75, 0x1c, 0xf7, 0xd4, 0x6c, 0x9b, 0x9d, 0x66, 0x4b, 0xcf, 0x2f, 0x64, 0x9c, 0x16, 0xee, 0x98,
+	0x6d, 0xb7, 0x85, 0x71, 0x17, 0xeb, 0x85, 0xe7, 0x43, 0xd5, 0x8f, 0xd2, 0xce, 0x03, 0xd0, 0xaf,
+	0x5b, 0xd8, 0x3a, 0xff, 0xe0, 0xda, 0x8e, 0xe9, 0xbc, 0xb7, 0x85, 0xc9, 0x75, 0x78, 0x92, 0xe5,
+	0xda, 0xd6, 0x45, 0xc7, 0xed, 0x74, 0x1d, 0xf7, 0x9d, 0xe9, 0x34, 0x2f, 0x75, 0x0d, 0x3d, 0x85,
+	0x5a, 0x16, 0x91, 0x31, 0x79, 0xe3, 0xe4, 0xef, 0x12, 0x54, 0x4c, 0x1a, 0x8d, 0x42, 0x7c, 0xd5,
+	0xb4, 0x69, 0x74, 0xe3, 0x7b, 0x14, 0xbd, 0x86, 0x62, 0x27, 0x1c, 0xd0, 0xe4, 0x65, 0x8a, 0xd4,
+	0x38, 0x4e, 0x6d, 0x8a, 0xda, 0x1a, 0x9e, 0x91, 0x43, 0xaf, 0x01, 0x96, 0x1b, 0x18, 0xa9, 0xc1,
+	0xc7, 0x57, 0x4d, 0xed, 0x51, 0x7a, 0x0c, 0xa6, 0x56, 0xb4, 0x91, 0x43, 0x6f, 0x41, 0x4f, 0x06,
+	0x56, 0x6a, 0x90, 0xc6, 0xe8, 0x81, 0x84, 0x2f, 0xa7, 0x7a, 0xed, 0x30, 0xad, 0x61, 0x39, 0x70,
+	0x8d, 0x1c, 0x3a, 0x82, 0x9d, 0x0b, 0x2a, 0xe4, 0xd7, 0x5a, 0x9b, 0x19, 0xc1, 0x46, 0x2e, 0xd9,
+	0x37, 0x17, 0x94, 0x39, 0xbd, 0xb5, 0xe0, 0xe5, 0xac, 0x33, 0x72, 0xe8, 0x7b, 0x00, 0xa5, 0xf9,
+	0x1e, 0xb8, 0xbe, 0x80, 0x5b, 0x81, 0xd2, 0x7f, 0xc2, 0xa5, 0xe4, 0x5c, 0x59, 0x2b, 0xb5, 0x32,
+	0x7b, 0x8c, 0x1c, 0x7a, 0x0e, 0x85, 0x0b, 0xca, 0xcc, 0x53, 0x6b, 0x2d, 0x1e, 0xd4, 0x1a, 0x3d,
+	0xb5, 0x04, 0xd6, 0xa6, 0xc1, 0xc0, 0xe9, 0xa1, 0xa5, 0xb1, 0xb5, 0x75, 0xd3, 0x9d, 0x7b, 0xb0,
+	0x23, 0x38, 0x4e, 0x0f, 0x95, 0x17, 0xe8, 0x24, 0x70, 0x8b, 0x94, 0xac, 0x6e, 0x8e, 0x45, 0x44,
+	0xef, 0xcf, 0xbf, 0x8a, 0x28, 0x47, 0x70, 0x8f, 0xcb, 0xcd, 0x88, 0x12, 0x46, 0xe5, 0x9e, 0x47,
+	0x95, 0xc5, 0x0e, 0x17, 0x5f, 0x0e, 0xb5, 0x95, 0x0f, 0x01, 0x23, 0x87, 0x5e, 0x42, 0x29, 0xf1,
+	0x58, 0xd0, 0xf1, 0x4a, 0xb9, 0xa0, 0x2c, 0x5c, 0x9a, 0xf5, 0x02, 0x4a, 0xed, 0xd0, 0xfb, 0xf8,
+	0x19, 0x8f, 0x9c, 0x40, 0xf9, 0x7d, 0x30, 0xf9, 0x3c, 0x99, 0x3a, 0x14, 0x6c, 0x7f, 0x14, 0x64,
+	0xc3, 0x9b, 0x29, 0x8b, 0x6f, 0x61, 0x47, 0xf4, 0xe6, 0xfa, 0x14, 0xa4, 0xf7, 0xa8, 0x91, 0x43,
+	0xaf, 0xa0, 0xfc, 0xf3, 0x9c, 0x46, 0x77, 0x6a, 0xd4, 0x2f, 0x5c, 0xe5, 0xdc, 0x7b, 0x7a, 0xe9,
+	0x1b, 0x9e, 0x81, 0x2b, 0xfe, 0xd5, 0x93, 0x0d, 0x4d, 0x25, 0xf5, 0x79, 0x24, 0xe3, 0xf2, 0x92,
+	0x83, 0xaf, 0x43, 0x46, 0xe3, 0xb5, 0xe9, 0x52, 0x22, 0x09, 0x42, 0x8a, 0xbc, 0x81, 0xca, 0x05,
+	0x65, 0xca, 0x24, 0x2b, 0x18, 0x86, 0x6b, 0x25, 0x97, 0x55, 0xb5, 0x04, 0x72, 0x97, 0xf8, 0xf7,
+	0x56, 0xeb, 0x86, 0x06, 0x6c, 0xd9, 0xac, 0xe7, 0xfe, 0x84, 0xd1, 0x28, 0x81, 0x2c, 0xda, 0x82,
+	0x23, 0xe4, 0x93, 0x3f, 0x40, 0x65, 0x21, 0x64, 0xb3, 0x88, 0x92, 0xe9, 0x3a, 0xc9, 0xdd, 0xb4,
+	0xa4, 0x91, 0x7b, 0xa1, 0xa1, 0x17, 0x00, 0x6a, 0x97, 0x67, 0xe3, 0xfd, 0x70, 0x61, 0x70, 0x7a,
+	0xd3, 0x1b, 0x39, 0x74, 0x0c, 0xdb, 0x4e, 0x44, 0x3c, 0x7a, 0x4f, 0xcf, 0x7e, 0xda, 0x7d, 0x16,
+	0xa0, 0x54, 0x34, 0xd4, 0x92, 0xfb, 0xff, 0x8a, 0xf3, 0xd9, 0x1d, 0x7d, 0x4f, 0xe2, 0xde, 0xc2,
+	0x81, 0xc8, 0xbf, 0x92, 0x12, 0x7b, 0x16, 0x3d, 0x56, 0xe8, 0x4f, 0xd6, 0xee, 0x4a, 0x77, 0x9f,
+	0xf1, 0xcc, 0x64, 0xd6, 0x5f, 0x4d, 0xf9, 0xfc, 0xe9, 0x6a, 0xad, 0xed, 0xaf, 0xb9, 0x33, 0x72,
+	0xa7, 0xf5, 0x5f, 0x9f, 0x8e, 0x7c, 0x36, 0x9e, 0xf7, 0x8f, 0xbc, 0x70, 0x7a, 0x4c, 0x92, 0x01,
+	0xef, 0x87, 0xe2, 0xf7, 0x98, 0x0b, 0xf4, 0x0b, 0xfc, 0x6f, 0xd3, 0xab, 0x7f, 0x02, 0x00, 0x00,
+	0xff, 0xff, 0x1b, 0x4d, 0x99, 0xc6, 0x98, 0x0d, 0x00, 0x00,
 }
@@ -123,6 +123,25 @@ type BlockState struct {
 	BlockInfo
 	accounts map[AccountID]*State
 	Undo     undoStates
+	// EventBloom is the bloom filter of the events emitted in the block
+	EventBloom Bloom
+	// DryRun is set when the txs are simulated. Nothing is written to the
+	// state db, and the block state is thrown away after the run.
+	DryRun bool
+	// Trace is set to record the steps of the contract executions in their
+	// receipts.
+	Trace bool
+	// Coinbase is the account credited with the fees of the txs of the
+	// block. The txs of a block without a coinbase account are free.
+	Coinbase []byte
+	// BaseRoot is the state root the block is executed on when it is not the
+	// latest one. The accounts not in the block state are read at it. It is
+	// nil for the blocks executed on the latest state.
+	BaseRoot []byte
+	// Deadline is the time in unix nanoseconds after which the contracts
+	// fail. The txs of the blocks have none, but the txs run for the clients
+	// must not run for long.
+	Deadline int64
 }
 type undoStates struct {
 	StateRoot HashID